@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -57,3 +58,153 @@ func TestPrintSessionTable(t *testing.T) {
 		t.Errorf("expected KB for 2048 bytes, got %q", size)
 	}
 }
+
+func TestPrintSessionUsage(t *testing.T) {
+	sess := &session.Session{
+		Slug:  "usage-slug",
+		Model: "claude-opus-4-6",
+		Turns: []session.Turn{
+			{Number: 1, UserText: "first", Model: "claude-opus-4-6", Usage: session.Usage{InputTokens: 1000, OutputTokens: 2000}},
+			{Number: 2, UserText: "second", Model: "unknown-model", Usage: session.Usage{InputTokens: 10}},
+		},
+	}
+	prices := session.PriceTable{"opus": {Input: 5, Output: 25}}
+
+	var buf bytes.Buffer
+	if err := printSessionUsage(&buf, sess, prices); err != nil {
+		t.Fatalf("printSessionUsage error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"usage-slug", "$0.0550", "TOTAL", "1010", "$0.0550*", "price table"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintAnalytics(t *testing.T) {
+	a := &session.Analytics{
+		Sessions:       2,
+		Turns:          3,
+		TotalDuration:  90 * time.Minute,
+		SessionsPerDay: []session.DayCount{{Date: "2026-02-13", Sessions: 2}},
+		Models:         []session.ModelCount{{Model: "claude-opus-4-6", Turns: 3}},
+		Tools:          []session.ToolStats{{Name: "Bash", Calls: 4, Errors: 1, ErrorRate: 0.25}},
+		Projects:       []session.ProjectStats{{Name: "proj", Sessions: 2, Turns: 3}},
+	}
+
+	var buf bytes.Buffer
+	if err := printAnalytics(&buf, a); err != nil {
+		t.Fatalf("printAnalytics error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"Sessions: 2", "1h30m", "2026-02-13", "claude-opus-4-6", "100%", "25.0%", "proj"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintFileTimeline(t *testing.T) {
+	sess := &session.Session{CWD: "/p", Turns: []session.Turn{
+		{Number: 1, Blocks: []session.Block{
+			{Type: session.BlockToolUse, ToolName: "Write", ToolID: "w1", ToolInput: map[string]interface{}{"file_path": "/p/a.txt", "content": "one\ntwo\n"}},
+			{Type: session.BlockToolResult, ToolID: "w1", Text: "ok"},
+		}},
+		{Number: 2, Blocks: []session.Block{
+			{Type: session.BlockToolUse, ToolName: "Edit", ToolID: "e1", ToolInput: map[string]interface{}{"file_path": "/p/a.txt", "old_string": "two", "new_string": "2"}},
+			{Type: session.BlockToolResult, ToolID: "e1", Text: "ok"},
+		}},
+	}}
+	histories := session.FileHistories(sess)
+
+	var buf bytes.Buffer
+	printFileTimeline(&buf, histories[0], sess.CWD)
+	want := `a.txt
+
+turn 1 · Write +2 -0
++ one
++ two
+
+turn 2 · Edit +1 -1
+  one
+- two
++ 2
+`
+	if buf.String() != want {
+		t.Errorf("timeline:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := printFiles(&buf, histories, sess.CWD); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "a.txt  0      1      1       2 lines  1,2") {
+		t.Errorf("files table:\n%s", buf.String())
+	}
+}
+
+func TestParseTurnRange(t *testing.T) {
+	tests := []struct {
+		spec        string
+		first, last int
+		wantErr     bool
+	}{
+		{"3", 3, 3, false},
+		{"2-4", 2, 4, false},
+		{"5", 0, 0, false},
+		{"0", 0, 0, true},
+		{"4-2", 0, 0, true},
+		{"1-6", 0, 0, true},
+		{"x", 0, 0, true},
+	}
+	for _, tt := range tests {
+		first, last, err := parseTurnRange(tt.spec, 5)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseTurnRange(%q) = %d, %d, want an error", tt.spec, first, last)
+			}
+			continue
+		}
+		if tt.first == 0 {
+			tt.first, tt.last = 5, 5
+		}
+		if err != nil || first != tt.first || last != tt.last {
+			t.Errorf("parseTurnRange(%q) = %d, %d, %v; want %d, %d", tt.spec, first, last, err, tt.first, tt.last)
+		}
+	}
+}
+
+func TestWriteListJSON(t *testing.T) {
+	rows := []sessionInfoJSON{
+		{ID: "a", Turns: 2, FileSize: 10},
+		{ID: "b", Slug: "bee", Turns: 3},
+	}
+
+	var buf bytes.Buffer
+	if err := writeListJSON(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[1]["slug"] != "bee" {
+		t.Errorf("JSON array: %v\n%s", err, buf.String())
+	}
+
+	buf.Reset()
+	if err := writeListJSON[sessionInfoJSON](&buf, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty list should print [], got %q", buf.String())
+	}
+
+	listJSONL = true
+	defer func() { listJSONL = false }()
+	buf.Reset()
+	if err := writeListJSON(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"id":"a"`) {
+		t.Errorf("JSON lines:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+var diffSummary bool
+
+// diffWrapper wraps replay.DiffModel to implement tea.Model.
+type diffWrapper struct {
+	model replay.DiffModel
+}
+
+func (w diffWrapper) Init() tea.Cmd {
+	return w.model.Init()
+}
+
+func (w diffWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	w.model, cmd = w.model.Update(msg)
+	return w, cmd
+}
+
+func (w diffWrapper) View() string {
+	return w.model.View()
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <sessionA> <sessionB>",
+	Short: "Compare two sessions side by side",
+	Long: `Compare two runs of the same task. Turns are aligned by user text and shown
+in a split-pane replay where both sides advance together; the timeline marks
+turns whose tool calls diverge or that exist on one side only. Press s for a
+summary of turn counts, durations, tokens and files touched.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := findAndLoadSession(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := findAndLoadSession(args[1])
+		if err != nil {
+			return err
+		}
+
+		if diffSummary {
+			fmt.Print(replay.FormatComparison(a, b, session.Compare(a, b)))
+			return nil
+		}
+
+		model := replay.NewDiff(a, b, 120, 40)
+		p := tea.NewProgram(diffWrapper{model: model}, tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("running diff: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffSummary, "summary", false, "print the comparison summary instead of opening the split view")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+// findAndLoadSession resolves a session query and loads the session.
+func findAndLoadSession(query string) (*session.Session, error) {
+	info, err := source.FindSession(query)
+	if err != nil {
+		return nil, fmt.Errorf("finding session %s: %w", query, err)
+	}
+	sess, err := source.LoadSession(info.ID)
+	if err != nil {
+		return nil, fmt.Errorf("loading session %s: %w", query, err)
+	}
+	return sess, nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var dumpCompact bool
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <session>",
+	Short: "Print a session as JSON",
+	Long: `Print a session as JSON, segmented into turns and blocks as it is replayed,
+for scripts and notebooks. Sessions read from --git are dumped the same way.
+
+The top-level "version" field is the schema version. It is raised when a
+field is removed or changes meaning; fields may be added at any time, so
+ignore the ones you don't know. Version 1 has:
+
+  id, slug, path, cwd, git_branch, client_version, model, models,
+  start_time, end_time, usage, forks, turns
+
+Each turn has number, uuid, prompt, timestamp, duration_ms, model, cwd,
+git_branch, usage (its own API calls), total_usage (with subagents) and
+blocks. Each block has a type (text, thinking, tool_use, tool_result,
+compaction, api_error, hook or interruption) and, when set: text,
+tool_name, tool_id (shared by a tool_use and its tool_result), tool_input,
+is_error, detail, timestamp and sidechain (the turns of a subagent). Usage
+objects have input_tokens, output_tokens, cache_creation_tokens and
+cache_read_tokens. Forks list rewound prompts: the turn the replayed branch
+starts, the selected branch index and each branch's prompt.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := findAndLoadSession(args[0])
+		if err != nil {
+			return err
+		}
+		if dumpCompact {
+			return json.NewEncoder(os.Stdout).Encode(sess.Dump())
+		}
+		return writeJSON(os.Stdout, sess.Dump())
+	},
+}
+
+func init() {
+	dumpCmd.Flags().BoolVar(&dumpCompact, "compact", false, "print the JSON on a single line")
+
+	rootCmd.AddCommand(dumpCmd)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/Trailblaze-work/claude-replay/internal/export"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
 )
 
 var (
@@ -15,12 +16,69 @@ var (
 	exportOutput string
 	exportWidth  int
 	exportHeight int
+
+	exportGranularity string
+	exportAnimate     bool
+
+	exportThinking    bool
+	exportFullResults bool
+
+	exportRedact      bool
+	exportRedactRules string
+
+	exportNoAnnotations bool
 )
 
+// transcriptFormats are export formats written straight from the session.
+var transcriptFormats = map[string]func(*session.Session, export.Options) error{
+	"html": export.GenerateHTML,
+	"md":   export.GenerateMarkdown,
+}
+
+// imageFormats are recordings rasterized straight to an animated image,
+// with the file extension they are written with.
+var imageFormats = map[string]struct {
+	generate  func(*session.Session, export.Options) error
+	extension string
+}{
+	"gif":  {export.GenerateGIF, "gif"},
+	"apng": {export.GenerateAPNG, "png"},
+}
+
 var exportCmd = &cobra.Command{
 	Use:   "export <session>",
-	Short: "Export a session as an asciinema recording",
-	Long:  "Export a session as an asciinema .cast file, with optional conversion to GIF or MP4",
+	Short: "Export a session as a recording, animated image, HTML page or Markdown",
+	Long: `Export a session as an asciinema .cast file, an animated GIF or PNG (APNG), an MP4
+video, or as a self-contained HTML or Markdown transcript.
+
+GIF and APNG frames are drawn by claude-replay itself, with a bundled
+monospace font and the replay's colors; no other tools are needed. MP4 is
+converted from the GIF with ffmpeg.
+
+With --redact, secrets are replaced by [REDACTED:<rule>] markers before the
+session is rendered: private keys, AWS, GitHub, Slack, OpenAI, Anthropic,
+Google and Stripe keys, JWTs, URL passwords, KEY=value assignments of
+secret-looking names and high-entropy strings. Extra rules are read from a
+JSON file (default: ` + "`<config dir>/claude-replay/redact.json`" + `):
+
+  {"patterns": [{"name": "internal-host", "regex": "[a-z0-9-]+\\.corp\\.example\\.com"}],
+   "paths": ["secrets/", "*.env"]}
+
+Path rules hide file contents read or written by tools: a rule ending in "/"
+matches any path containing that directory, others are globs matched against
+the full path and the file name.
+
+With --granularity block, recordings reveal each turn block by block (text,
+tool calls, then their results) instead of one frame per turn, scrolled to
+the newest block. In realtime and fast modes, blocks are paced by the times
+their records were written.
+
+With --animate, recordings type each prompt, stream the assistant's text and
+show a spinner while a tool runs, for as long as it ran in the session (scaled
+by the timing mode, at most 3s per wait). Implies block granularity.
+
+Bookmarks and notes made in the replay (m and a keys) are shown with their
+turns in every format; --no-annotations leaves them out.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := args[0]
@@ -41,12 +99,40 @@ var exportCmd = &cobra.Command{
 			return fmt.Errorf("session has no turns")
 		}
 
+		// Redact before anything is rendered
+		var report session.RedactionReport
+		if exportRedact || exportRedactRules != "" {
+			redactor, err := loadRedactor(exportRedactRules)
+			if err != nil {
+				return err
+			}
+			sess, report = redactor.Session(sess)
+		}
+
+		granularity := export.Granularity(exportGranularity)
+		if granularity != export.GranularityTurn && granularity != export.GranularityBlock {
+			return fmt.Errorf("unknown granularity %q (want turn or block)", exportGranularity)
+		}
+
 		// Build options
 		opts := export.Options{
 			TimingMode: export.TimingMode(exportMode),
 			Width:      exportWidth,
 			Height:     exportHeight,
 			Format:     exportFormat,
+
+			Granularity: granularity,
+			Animate:     exportAnimate,
+
+			IncludeThinking: exportThinking,
+			FullToolResults: exportFullResults,
+		}
+		if !exportNoAnnotations {
+			notes, err := session.AnnotationStoreFor(source).LoadAnnotations(sess.ID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+			opts.Annotations = notes
 		}
 
 		// Determine output path
@@ -55,57 +141,87 @@ var exportCmd = &cobra.Command{
 			if slug == "" && len(sess.ID) > 8 {
 				slug = sess.ID[:8]
 			}
-			exportOutput = slug + "." + exportFormat
+			extension := exportFormat
+			if image, ok := imageFormats[exportFormat]; ok {
+				extension = image.extension
+			}
+			exportOutput = slug + "." + extension
 		}
 
-		// Generate .cast file
-		castPath := exportOutput
-		if !strings.HasSuffix(castPath, ".cast") && opts.Format == "cast" {
-			// Output is already the right path
-		} else if opts.Format != "cast" {
-			castPath = strings.TrimSuffix(exportOutput, "."+opts.Format) + ".cast"
+		// Transcripts are rendered directly from the session, without a recording
+		if generate, ok := transcriptFormats[opts.Format]; ok {
+			opts.Output = exportOutput
+			fmt.Printf("Exporting session: %s\n", sess.Slug)
+			fmt.Printf("  Turns: %d\n", len(sess.Turns))
+			printRedactionReport(report)
+			fmt.Printf("  Output: %s\n", exportOutput)
+			if err := generate(sess, opts); err != nil {
+				return fmt.Errorf("generating %s: %w", opts.Format, err)
+			}
+			fmt.Printf("  Done: %s\n", exportOutput)
+			return nil
 		}
 
-		opts.Output = castPath
+		recordingMode := fmt.Sprintf("%s, one frame per %s", opts.TimingMode, opts.Granularity)
+		if opts.Animate {
+			recordingMode = fmt.Sprintf("%s, animated", opts.TimingMode)
+		}
+
+		// Animated images are rasterized directly
+		if image, ok := imageFormats[opts.Format]; ok {
+			opts.Output = exportOutput
+			fmt.Printf("Exporting session: %s\n", sess.Slug)
+			fmt.Printf("  Turns: %d\n", len(sess.Turns))
+			fmt.Printf("  Mode: %s\n", recordingMode)
+			printRedactionReport(report)
+			fmt.Printf("  Output: %s\n", exportOutput)
+			if err := image.generate(sess, opts); err != nil {
+				return fmt.Errorf("generating %s: %w", opts.Format, err)
+			}
+			fmt.Printf("  Done: %s\n", export.FormatImageInfo(exportOutput))
+			return nil
+		}
+
+		if opts.Format == "mp4" {
+			gifPath := strings.TrimSuffix(exportOutput, ".mp4") + ".gif"
+			opts.Output = gifPath
+			fmt.Printf("Exporting session: %s\n", sess.Slug)
+			fmt.Printf("  Turns: %d\n", len(sess.Turns))
+			fmt.Printf("  Mode: %s\n", recordingMode)
+			printRedactionReport(report)
+			fmt.Printf("  Output: %s\n", exportOutput)
+			if err := export.GenerateGIF(sess, opts); err != nil {
+				return fmt.Errorf("generating gif: %w", err)
+			}
+			fmt.Printf("  Converting to MP4...\n")
+			if err := export.ConvertToMP4(gifPath, exportOutput); err != nil {
+				fmt.Printf("  Note: %v\n", err)
+				fmt.Printf("  GIF kept: %s\n", export.FormatImageInfo(gifPath))
+				return nil
+			}
+			os.Remove(gifPath)
+			fmt.Printf("  MP4: %s\n", exportOutput)
+			return nil
+		}
+
+		if opts.Format != "cast" {
+			return fmt.Errorf("unknown format %q (want cast, gif, apng, mp4, html or md)", opts.Format)
+		}
+
+		// Generate .cast file
+		opts.Output = exportOutput
 
 		fmt.Printf("Exporting session: %s\n", sess.Slug)
 		fmt.Printf("  Turns: %d\n", len(sess.Turns))
-		fmt.Printf("  Mode: %s\n", opts.TimingMode)
-		fmt.Printf("  Output: %s\n", castPath)
+		fmt.Printf("  Mode: %s\n", recordingMode)
+		printRedactionReport(report)
+		fmt.Printf("  Output: %s\n", exportOutput)
 
 		if err := export.GenerateCast(sess, opts); err != nil {
 			return fmt.Errorf("generating cast: %w", err)
 		}
 
-		fmt.Printf("  Done: %s\n", export.FormatCastInfo(castPath))
-
-		// Convert if needed
-		if opts.Format == "gif" {
-			gifPath := exportOutput
-			fmt.Printf("  Converting to GIF...\n")
-			if err := export.ConvertToGif(castPath, gifPath); err != nil {
-				fmt.Printf("  Note: %v\n", err)
-				fmt.Printf("  You can convert manually: agg %s %s\n", castPath, gifPath)
-			} else {
-				fmt.Printf("  GIF: %s\n", gifPath)
-				os.Remove(castPath)
-			}
-		} else if opts.Format == "mp4" {
-			gifPath := strings.TrimSuffix(exportOutput, ".mp4") + ".gif"
-			fmt.Printf("  Converting to GIF...\n")
-			if err := export.ConvertToGif(castPath, gifPath); err != nil {
-				fmt.Printf("  Note: %v\n", err)
-			} else {
-				fmt.Printf("  Converting to MP4...\n")
-				if err := export.ConvertToMP4(gifPath, exportOutput); err != nil {
-					fmt.Printf("  Note: %v\n", err)
-				} else {
-					fmt.Printf("  MP4: %s\n", exportOutput)
-					os.Remove(castPath)
-					os.Remove(gifPath)
-				}
-			}
-		}
+		fmt.Printf("  Done: %s\n", export.FormatCastInfo(exportOutput))
 
 		return nil
 	},
@@ -113,10 +229,39 @@ var exportCmd = &cobra.Command{
 
 func init() {
 	exportCmd.Flags().StringVar(&exportMode, "mode", "compressed", "timing mode: realtime, compressed, fast, instant")
-	exportCmd.Flags().StringVar(&exportFormat, "format", "cast", "output format: cast, gif, mp4")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "cast", "output format: cast, gif, apng, mp4, html, md")
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file path")
 	exportCmd.Flags().IntVar(&exportWidth, "width", 120, "terminal width")
 	exportCmd.Flags().IntVar(&exportHeight, "height", 40, "terminal height")
+	exportCmd.Flags().StringVar(&exportGranularity, "granularity", "turn", "recording frames: turn, or block to reveal turns block by block (cast, gif, apng, mp4)")
+	exportCmd.Flags().BoolVar(&exportAnimate, "animate", false, "type prompts, stream text and show spinners while tools run (cast, gif, apng, mp4)")
+	exportCmd.Flags().BoolVar(&exportThinking, "thinking", false, "include thinking blocks (md)")
+	exportCmd.Flags().BoolVar(&exportFullResults, "full-results", false, "include complete tool results instead of the first lines (md)")
+
+	exportCmd.Flags().BoolVar(&exportRedact, "redact", false, "replace secrets (tokens, keys, high-entropy strings) before exporting")
+	exportCmd.Flags().StringVar(&exportRedactRules, "redact-rules", "", "JSON file with extra redaction rules (implies --redact)")
+	exportCmd.Flags().BoolVar(&exportNoAnnotations, "no-annotations", false, "leave out bookmarks and notes")
 
 	rootCmd.AddCommand(exportCmd)
 }
+
+// loadRedactor reads the given rules file, or the default rules file if
+// present.
+func loadRedactor(path string) (*session.Redactor, error) {
+	if path != "" {
+		return session.LoadRedactor(path, true)
+	}
+	return session.LoadRedactor(session.DefaultRedactRulesPath(), false)
+}
+
+// printRedactionReport summarizes what --redact replaced.
+func printRedactionReport(report session.RedactionReport) {
+	if report == nil {
+		return
+	}
+	if report.Total() == 0 {
+		fmt.Printf("  Redacted: nothing found\n")
+		return
+	}
+	fmt.Printf("  Redacted: %d (%s)\n", report.Total(), report)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+var (
+	filesContent bool
+	filesAt      int
+)
+
+var filesCmd = &cobra.Command{
+	Use:   "files <session> [path]",
+	Short: "List the files a session touched, or the history of one file",
+	Long: `Without a path, list every file read or written by a tool call in the session
+(subagents included) with the turns that touched it.
+
+With a path (or a unique suffix of one, like ui/app.go), print the file's
+timeline: the diff of each Edit and Write, turn by turn. With --content,
+print the file as it was at the end of turn --at (default: the end of the
+session) instead.
+
+Content is reconstructed from the tool calls alone, so it is only known from
+a complete Read or a Write onwards, for as long as every later edit applies.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := findAndLoadSession(args[0])
+		if err != nil {
+			return err
+		}
+		histories := session.FileHistories(sess)
+
+		if len(args) == 1 {
+			return printFiles(os.Stdout, histories, sess.CWD)
+		}
+
+		h, candidates := session.FindFileHistory(histories, args[1])
+		if h == nil {
+			if len(candidates) > 0 {
+				return fmt.Errorf("%s matches several files:\n  %s", args[1], strings.Join(candidates, "\n  "))
+			}
+			return fmt.Errorf("the session did not touch %s", args[1])
+		}
+
+		if filesContent {
+			turn := filesAt
+			if turn == 0 {
+				turn = len(sess.Turns)
+			}
+			content, ok := h.ContentAt(turn)
+			if !ok {
+				return fmt.Errorf("content of %s at turn %d is unknown: no complete Read or Write precedes it, or an edit did not apply", h.Path, turn)
+			}
+			fmt.Print(content)
+			return nil
+		}
+		printFileTimeline(os.Stdout, *h, sess.CWD)
+		return nil
+	},
+}
+
+func init() {
+	filesCmd.Flags().BoolVar(&filesContent, "content", false, "print the reconstructed file content instead of its timeline")
+	filesCmd.Flags().IntVar(&filesAt, "at", 0, "turn whose end --content shows (default: last turn)")
+
+	rootCmd.AddCommand(filesCmd)
+}
+
+func printFiles(out io.Writer, histories []session.FileHistory, cwd string) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tREADS\tEDITS\tWRITES\tFINAL\tTURNS")
+	for _, h := range histories {
+		final := "unknown"
+		if content, ok := h.Final(); ok {
+			final = fmt.Sprintf("%d lines", lineCount(content))
+		}
+		turns := make([]string, 0, len(h.Turns()))
+		for _, t := range h.Turns() {
+			turns = append(turns, fmt.Sprint(t))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n",
+			replay.ShortenPath(h.Path, cwd),
+			h.Count(session.FileRead),
+			h.Count(session.FileEdit),
+			h.Count(session.FileWrite),
+			final,
+			strings.Join(turns, ","),
+		)
+	}
+	return w.Flush()
+}
+
+func printFileTimeline(out io.Writer, h session.FileHistory, cwd string) {
+	fmt.Fprintf(out, "%s\n", replay.ShortenPath(h.Path, cwd))
+	for _, c := range h.Changes {
+		fmt.Fprintf(out, "\n%s\n", replay.FileChangeTitle(c))
+		switch {
+		case c.IsError:
+			fmt.Fprintln(out, "  failed, not applied")
+		case c.Op == session.FileRead && c.AfterKnown:
+			fmt.Fprintf(out, "  read %d lines\n", lineCount(c.After))
+		case c.Op == session.FileRead:
+			fmt.Fprintln(out, "  partial read")
+		}
+		for _, op := range replay.ChangeDiff(c) {
+			if op.Kind == '@' {
+				fmt.Fprintf(out, "  %s\n", op.Text)
+				continue
+			}
+			fmt.Fprintf(out, "%c %s\n", op.Kind, op.Text)
+		}
+	}
+}
+
+func lineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
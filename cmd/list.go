@@ -1,18 +1,31 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
 )
 
+var (
+	listJSON  bool
+	listJSONL bool
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list [project]",
 	Short: "List projects or sessions (non-interactive)",
-	Long:  "List all projects, or sessions within a project. Useful for scripting.",
+	Long: `List all projects, or sessions within a project. Useful for scripting.
+
+With --json, a JSON array is printed instead of a table; with --jsonl, one
+JSON object per line. Projects have the fields name, path, dir_name,
+sessions and last_used; sessions have id, slug, path, model, turns,
+first_time, last_time and file_size (bytes). Times are RFC 3339.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if gitMode {
 			return listGitSessions()
@@ -25,15 +38,64 @@ var listCmd = &cobra.Command{
 }
 
 func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "print a JSON array instead of a table")
+	listCmd.Flags().BoolVar(&listJSONL, "jsonl", false, "print one JSON object per line instead of a table")
+
 	rootCmd.AddCommand(listCmd)
 }
 
+// projectJSON is the JSON form of a row of listProjects.
+type projectJSON struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	DirName  string    `json:"dir_name"`
+	Sessions int       `json:"sessions"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// sessionInfoJSON is the JSON form of a row of printSessionTable.
+type sessionInfoJSON struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Turns     int       `json:"turns"`
+	FirstTime time.Time `json:"first_time"`
+	LastTime  time.Time `json:"last_time"`
+	FileSize  int64     `json:"file_size"`
+}
+
+// writeListJSON writes rows as a JSON array, or as JSON lines with --jsonl.
+func writeListJSON[T any](out io.Writer, rows []T) error {
+	if listJSONL {
+		enc := json.NewEncoder(out)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if rows == nil {
+		rows = []T{}
+	}
+	return writeJSON(out, rows)
+}
+
 func listProjects() error {
 	projects, err := source.ListProjects()
 	if err != nil {
 		return err
 	}
 
+	if listJSON || listJSONL {
+		var rows []projectJSON
+		for _, p := range projects {
+			rows = append(rows, projectJSON{Name: p.Name, Path: p.Path, DirName: p.DirName, Sessions: p.Sessions, LastUsed: p.LastUsed})
+		}
+		return writeListJSON(os.Stdout, rows)
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
 	fmt.Fprintln(w, "NAME\tPATH\tSESSIONS\tLAST USED")
 	for _, p := range projects {
@@ -97,6 +159,23 @@ func listGitSessions() error {
 }
 
 func printSessionTable(sessions []session.SessionInfo) error {
+	if listJSON || listJSONL {
+		var rows []sessionInfoJSON
+		for _, s := range sessions {
+			rows = append(rows, sessionInfoJSON{
+				ID:        s.ID,
+				Slug:      s.Slug,
+				Path:      s.Path,
+				Model:     s.Model,
+				Turns:     s.TurnCount,
+				FirstTime: s.FirstTime,
+				LastTime:  s.LastTime,
+				FileSize:  s.FileSize,
+			})
+		}
+		return writeListJSON(os.Stdout, rows)
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
 	fmt.Fprintln(w, "SLUG\tID\tMODEL\tTURNS\tDATE\tSIZE")
 	for _, s := range sessions {
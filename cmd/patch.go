@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/export"
+)
+
+var (
+	patchFrom   int
+	patchTo     int
+	patchRoot   string
+	patchOutput string
+)
+
+var patchCmd = &cobra.Command{
+	Use:   "patch <session>",
+	Short: "Print the changes a session made as a unified diff",
+	Long: `Fold every Edit and Write of a session (or of turns --from to --to) into a
+single unified diff that git apply accepts, e.g. to replay an agent's changes
+onto a clean checkout:
+
+  claude-replay patch <session> | git apply
+
+Each file is diffed from its content before the first change in the range to
+its content after the last one, using earlier Read results as the baseline.
+A Write to a file the session never read creates it. When the baseline is
+unknown, hunks are built from the edited text alone and a warning is printed
+on stderr: such hunks carry no context, need git apply --unidiff-zero and may
+still fail.
+
+Paths are relative to the session's working directory, or to --root.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := findAndLoadSession(args[0])
+		if err != nil {
+			return err
+		}
+
+		p, err := export.BuildPatch(sess, export.PatchOptions{From: patchFrom, To: patchTo, Root: patchRoot})
+		if err != nil {
+			return err
+		}
+		for _, w := range p.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+
+		var out io.Writer = os.Stdout
+		if patchOutput != "" {
+			f, err := os.Create(patchOutput)
+			if err != nil {
+				return fmt.Errorf("creating output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if _, err := p.WriteTo(out); err != nil {
+			return err
+		}
+		if len(p.Files) == 0 {
+			fmt.Fprintln(os.Stderr, "no file changes in the selected turns")
+		}
+		return nil
+	},
+}
+
+func init() {
+	patchCmd.Flags().IntVar(&patchFrom, "from", 0, "first turn to include (default: first)")
+	patchCmd.Flags().IntVar(&patchTo, "to", 0, "last turn to include (default: last)")
+	patchCmd.Flags().StringVar(&patchRoot, "root", "", "directory patch paths are relative to (default: session working directory)")
+	patchCmd.Flags().StringVarP(&patchOutput, "output", "o", "", "write the patch to a file instead of stdout")
+
+	rootCmd.AddCommand(patchCmd)
+}
@@ -5,6 +5,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/export"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
 )
 
@@ -27,10 +29,15 @@ func (w replayWrapper) View() string {
 	return w.model.View()
 }
 
+var playFollow bool
+
 var playCmd = &cobra.Command{
 	Use:   "play <session>",
 	Short: "Replay a specific session",
-	Long:  "Replay a session by UUID, slug, or file path",
+	Long: `Replay a session by UUID, slug, or file path.
+
+With --follow, the session file is tailed and new turns are shown as they are
+written, so a running Claude Code session can be watched from another terminal.`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := args[0]
@@ -45,11 +52,18 @@ var playCmd = &cobra.Command{
 			return fmt.Errorf("loading session: %w", err)
 		}
 
-		if len(sess.Turns) == 0 {
+		if len(sess.Turns) == 0 && !playFollow {
 			return fmt.Errorf("session has no turns")
 		}
 
 		model := replay.New(sess, 120, 40)
+		model.SetSnapshotter(export.SaveSnapshot)
+		model.SetAnnotationStore(session.AnnotationStoreFor(source))
+		if playFollow {
+			if err := model.StartFollow(); err != nil {
+				return err
+			}
+		}
 		p := tea.NewProgram(replayWrapper{model: model}, tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
 			return fmt.Errorf("running replay: %w", err)
@@ -59,5 +73,7 @@ var playCmd = &cobra.Command{
 }
 
 func init() {
+	playCmd.Flags().BoolVarP(&playFollow, "follow", "f", false, "keep reading new records as the session file grows")
+
 	rootCmd.AddCommand(playCmd)
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+var publishRepo string
+
+var publishCmd = &cobra.Command{
+	Use:   "publish <session...>",
+	Short: "Commit local sessions to a claude-sessions git branch",
+	Long: `Commit local sessions to the claude-sessions branch of a git repository,
+creating the branch if needed, so they can be browsed with --git and shared
+by pushing the branch.
+
+Each session is stored gzipped as sessions/<id>.jsonl.gz, next to a
+sessions/<id>.meta.json with its models, turn counts, tool usage and
+compressed size. Sessions published before are replaced. The commit is
+made without checking the branch out: the worktree, the index and the
+current branch are left alone.
+
+The repository is --repo, or --git-repo, or the current directory.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if gitMode {
+			return fmt.Errorf("publish reads local sessions and cannot be used with --git")
+		}
+		repo := publishRepo
+		if repo == "" {
+			repo = gitRepo
+		}
+		if repo == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting current directory: %w", err)
+			}
+			repo = cwd
+		}
+
+		var sessions []*session.Session
+		for _, query := range args {
+			sess, err := findAndLoadSession(query)
+			if err != nil {
+				return err
+			}
+			sessions = append(sessions, sess)
+		}
+
+		dest := &session.GitSource{RepoPath: repo}
+		defer dest.Close()
+		published, err := dest.Publish(sessions)
+		if err != nil {
+			return fmt.Errorf("publishing: %w", err)
+		}
+		for _, p := range published {
+			fmt.Printf("%s  %-30s %3d turns  %s\n", p.ID, p.Slug, p.Turns, formatBytes(p.CompressedSize))
+		}
+		fmt.Fprintf(os.Stderr, "Published %d sessions to the claude-sessions branch of %s\n", len(published), repo)
+		return nil
+	},
+}
+
+func init() {
+	publishCmd.Flags().StringVar(&publishRepo, "repo", "", "git repository to publish to (default: --git-repo or the current directory)")
+
+	rootCmd.AddCommand(publishCmd)
+}
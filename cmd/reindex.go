@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the session metadata index",
+	Long:  "Rescan every local session file and rebuild the metadata index used to speed up listing and lookups.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		local, ok := source.(*session.LocalSource)
+		if !ok {
+			return fmt.Errorf("reindex only applies to local sessions")
+		}
+		if local.Index == nil {
+			return fmt.Errorf("the index is disabled by --no-index")
+		}
+
+		local.Index.Reset()
+		projects, err := local.ListProjects()
+		if err != nil {
+			return fmt.Errorf("listing projects: %w", err)
+		}
+
+		sessions := 0
+		for _, p := range projects {
+			infos, err := local.ListSessions(p.DirPath)
+			if err != nil {
+				return fmt.Errorf("listing sessions of %s: %w", p.Name, err)
+			}
+			sessions += len(infos)
+		}
+		if err := local.Index.Save(); err != nil {
+			return fmt.Errorf("saving index: %w", err)
+		}
+
+		fmt.Printf("Indexed %d sessions in %d projects (%d files)\n", sessions, len(projects), local.Index.Len())
+		fmt.Printf("  Index: %s\n", local.Index.Path())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
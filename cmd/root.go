@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -22,6 +23,7 @@ var (
 	claudeDir string
 	gitMode   bool
 	gitRepo   string
+	noIndex   bool
 )
 
 // source is the session source used by all subcommands.
@@ -44,7 +46,17 @@ var rootCmd = &cobra.Command{
 			}
 			source = &session.GitSource{RepoPath: repo}
 		} else {
-			source = &session.LocalSource{ClaudeDir: claudeDir}
+			local := &session.LocalSource{ClaudeDir: claudeDir}
+			if !noIndex {
+				local.Index = session.OpenIndex(session.DefaultIndexPath(claudeDir))
+			}
+			source = local
+		}
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if c, ok := source.(io.Closer); ok {
+			return c.Close()
 		}
 		return nil
 	},
@@ -64,6 +76,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&claudeDir, "claude-dir", defaultDir, "path to Claude Code data directory")
 	rootCmd.PersistentFlags().BoolVar(&gitMode, "git", false, "browse sessions from a claude-sessions git branch")
 	rootCmd.PersistentFlags().StringVar(&gitRepo, "git-repo", "", "path to git repository (default: current directory)")
+	rootCmd.PersistentFlags().BoolVar(&noIndex, "no-index", false, "scan every session file instead of using the metadata index")
 
 	// Default command is browse
 	rootCmd.RunE = browseCmd.RunE
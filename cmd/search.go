@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+var (
+	searchRegex bool
+	searchLimit int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search text, thinking and tool calls across all sessions",
+	Long: `Search user prompts, assistant text, thinking, tool inputs and tool results
+across every session.
+
+Filters can be combined with the search text:
+  tool:<name>     only tool calls/results of this tool (e.g. tool:Bash)
+  error:true      only failed tool results (error:false for successful ones)
+  in:<field>      only one field: user, text, thinking, input, result
+
+Text wrapped in slashes (/pattern/) is treated as a regular expression.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := session.ParseSearchQuery(strings.Join(args, " "), searchRegex)
+		if err != nil {
+			return err
+		}
+		if q.IsEmpty() {
+			return fmt.Errorf("empty search query")
+		}
+
+		hits, err := session.Search(source, q)
+		if err != nil {
+			return fmt.Errorf("searching sessions: %w", err)
+		}
+		if len(hits) == 0 {
+			fmt.Println("No matches.")
+			return nil
+		}
+
+		return printSearchHits(hits, searchLimit)
+	},
+}
+
+func init() {
+	searchCmd.Flags().BoolVarP(&searchRegex, "regex", "e", false, "treat the query text as a regular expression")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 100, "maximum number of matches to print (0 for all)")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+func printSearchHits(hits []session.SearchHit, limit int) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tSESSION\tTURN\tFIELD\tMATCH")
+	for i, h := range hits {
+		if limit > 0 && i == limit {
+			break
+		}
+		name := h.Session.Slug
+		if name == "" {
+			name = h.Session.ID
+			if len(name) > 8 {
+				name = name[:8]
+			}
+		}
+		field := h.Field
+		if h.ToolName != "" {
+			field = h.ToolName + " " + field
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			h.Project,
+			name,
+			h.Turn,
+			field,
+			h.Snippet,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if limit > 0 && len(hits) > limit {
+		fmt.Printf("\n%d of %d matches shown (use --limit 0 for all)\n", limit, len(hits))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/export"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+var (
+	snapshotTurn     string
+	snapshotExpanded bool
+	snapshotOutput   string
+	snapshotWidth    int
+	snapshotHeight   int
+	snapshotCrop     bool
+
+	snapshotRedact      bool
+	snapshotRedactRules string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <session>",
+	Short: "Save one turn, or a range of turns, as an SVG or PNG image",
+	Long: `Render a turn as it appears in the replay and save it as an image, e.g. for
+a slide or a bug report. --turn takes a turn number or a range such as 3-5,
+whose turns are shown below each other.
+
+The output format follows the extension of -o: .svg (default) keeps the
+replay's colors and selectable text, .png is drawn with a bundled monospace
+font. With --crop, the image is as tall as the turns instead of --height
+rows. With --redact (or --redact-rules), secrets are replaced as by export
+--redact before the turn is rendered. Press P in the replay to snapshot the
+current view, redacted while the redaction preview is on.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := findAndLoadSession(args[0])
+		if err != nil {
+			return err
+		}
+		if len(sess.Turns) == 0 {
+			return fmt.Errorf("session has no turns")
+		}
+
+		first, last, err := parseTurnRange(snapshotTurn, len(sess.Turns))
+		if err != nil {
+			return err
+		}
+
+		// Redact before anything is rendered
+		var report session.RedactionReport
+		if snapshotRedact || snapshotRedactRules != "" {
+			redactor, err := loadRedactor(snapshotRedactRules)
+			if err != nil {
+				return err
+			}
+			sess, report = redactor.Session(sess)
+		}
+
+		output := snapshotOutput
+		if output == "" {
+			slug := sess.Slug
+			if slug == "" && len(sess.ID) > 8 {
+				slug = sess.ID[:8]
+			}
+			output = fmt.Sprintf("%s-turn%s.svg", slug, snapshotTurn)
+		}
+
+		height := snapshotHeight
+		if snapshotCrop {
+			height = 0
+		}
+		frame := export.RenderSnapshot(sess, first-1, last-1, snapshotExpanded, snapshotWidth, height)
+		if err := export.SaveSnapshot(output, frame, snapshotWidth); err != nil {
+			return err
+		}
+		fmt.Println(export.FormatImageInfo(output))
+		printRedactionReport(report)
+		return nil
+	},
+}
+
+func init() {
+	snapshotCmd.Flags().StringVar(&snapshotTurn, "turn", "1", "turn number, or range of turns such as 3-5")
+	snapshotCmd.Flags().BoolVar(&snapshotExpanded, "expanded", false, "show tool results and thinking in full")
+	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "output file, .svg or .png (default: <slug>-turn<N>.svg)")
+	snapshotCmd.Flags().IntVar(&snapshotWidth, "width", 120, "terminal width")
+	snapshotCmd.Flags().IntVar(&snapshotHeight, "height", 40, "terminal height")
+	snapshotCmd.Flags().BoolVar(&snapshotCrop, "crop", false, "crop to the height of the content instead of --height")
+	snapshotCmd.Flags().BoolVar(&snapshotRedact, "redact", false, "replace secrets (tokens, keys, high-entropy strings) before rendering")
+	snapshotCmd.Flags().StringVar(&snapshotRedactRules, "redact-rules", "", "JSON file with extra redaction rules (implies --redact)")
+
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+// parseTurnRange parses "N" or "N-M" into 1-based turn numbers of a
+// session with count turns.
+func parseTurnRange(spec string, count int) (int, int, error) {
+	from, to, isRange := strings.Cut(spec, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid turn %q", spec)
+	}
+	last := first
+	if isRange {
+		if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+			return 0, 0, fmt.Errorf("invalid turn range %q", spec)
+		}
+	}
+	if first < 1 || last < first || last > count {
+		return 0, 0, fmt.Errorf("turn %s out of range (session has %d turns)", spec, count)
+	}
+	return first, last, nil
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+var (
+	statsPrices string
+	statsJSON   bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [session]",
+	Short: "Show usage analytics across all sessions, or token usage of one session",
+	Long: `Without arguments, aggregate every session of the current source: sessions per
+day, turns per session, total turn duration, model mix, most-used tools with
+error rates, busiest projects and token usage.
+
+With a session, show input, output and cache token counts per turn and in
+total, with a cost estimate. Subagent usage is included in the turn that
+spawned it.
+
+Prices are USD per million tokens, keyed by a model name fragment; the
+longest fragment contained in the model name applies. Override or extend the
+built-in table with a JSON file (default: ` + "`<config dir>/claude-replay/prices.json`" + `):
+
+  {"opus-4-6": {"input": 5, "output": 25, "cache_write": 6.25, "cache_read": 0.5}}`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prices, err := loadPrices()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			a, err := session.Analyze(source, prices)
+			if err != nil {
+				return fmt.Errorf("analyzing sessions: %w", err)
+			}
+			if statsJSON {
+				return writeJSON(os.Stdout, a)
+			}
+			return printAnalytics(os.Stdout, a)
+		}
+
+		info, err := source.FindSession(args[0])
+		if err != nil {
+			return fmt.Errorf("finding session: %w", err)
+		}
+		sess, err := source.LoadSession(info.ID)
+		if err != nil {
+			return fmt.Errorf("loading session: %w", err)
+		}
+
+		if statsJSON {
+			return writeJSON(os.Stdout, sessionUsageReport(sess, prices))
+		}
+		return printSessionUsage(os.Stdout, sess, prices)
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsPrices, "prices", "", "JSON price table overriding the built-in prices")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print JSON instead of tables")
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+// loadPrices reads the --prices file, or the default price file if present.
+func loadPrices() (session.PriceTable, error) {
+	if statsPrices != "" {
+		return session.LoadPriceTable(statsPrices, true)
+	}
+	return session.LoadPriceTable(session.DefaultPricesPath(), false)
+}
+
+func printSessionUsage(out io.Writer, sess *session.Session, prices session.PriceTable) error {
+	name := sess.Slug
+	if name == "" {
+		name = sess.ID
+	}
+	fmt.Fprintf(out, "Session: %s\n", name)
+	fmt.Fprintf(out, "  Model: %s\n", sess.Model)
+	fmt.Fprintf(out, "  Turns: %d\n\n", len(sess.Turns))
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TURN\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ\tCOST\t PROMPT")
+	for _, t := range sess.Turns {
+		u := t.TotalUsage()
+		cost, ok := prices.TurnCost(t)
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%s\t %s\n",
+			t.Number,
+			u.InputTokens,
+			u.OutputTokens,
+			u.CacheCreationTokens,
+			u.CacheReadTokens,
+			formatCost(cost, ok),
+			truncatePrompt(t.UserText, 40),
+		)
+	}
+	total := sess.TotalUsage()
+	cost, ok := prices.SessionCost(sess)
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%d\t%s\t\n",
+		total.InputTokens,
+		total.OutputTokens,
+		total.CacheCreationTokens,
+		total.CacheReadTokens,
+		formatCost(cost, ok),
+	)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !ok {
+		fmt.Fprintf(out, "\n* cost excludes models missing from the price table (see --prices)\n")
+	}
+	return nil
+}
+
+// turnUsageJSON is the JSON form of one row of printSessionUsage.
+type turnUsageJSON struct {
+	Turn                int     `json:"turn"`
+	Model               string  `json:"model,omitempty"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	Cost                float64 `json:"estimated_cost_usd"`
+	CostComplete        bool    `json:"cost_complete"`
+}
+
+// sessionUsageJSON is the JSON form of printSessionUsage.
+type sessionUsageJSON struct {
+	Session string          `json:"session"`
+	Slug    string          `json:"slug,omitempty"`
+	Turns   []turnUsageJSON `json:"turns"`
+	Total   turnUsageJSON   `json:"total"`
+}
+
+func sessionUsageReport(sess *session.Session, prices session.PriceTable) sessionUsageJSON {
+	row := func(u session.Usage, cost float64, ok bool) turnUsageJSON {
+		return turnUsageJSON{
+			InputTokens:         u.InputTokens,
+			OutputTokens:        u.OutputTokens,
+			CacheCreationTokens: u.CacheCreationTokens,
+			CacheReadTokens:     u.CacheReadTokens,
+			Cost:                cost,
+			CostComplete:        ok,
+		}
+	}
+
+	report := sessionUsageJSON{Session: sess.ID, Slug: sess.Slug, Turns: []turnUsageJSON{}}
+	for _, t := range sess.Turns {
+		cost, ok := prices.TurnCost(t)
+		r := row(t.TotalUsage(), cost, ok)
+		r.Turn = t.Number
+		r.Model = t.Model
+		report.Turns = append(report.Turns, r)
+	}
+	cost, ok := prices.SessionCost(sess)
+	report.Total = row(sess.TotalUsage(), cost, ok)
+	return report
+}
+
+func printAnalytics(out io.Writer, a *session.Analytics) error {
+	fmt.Fprintf(out, "Sessions: %d\n", a.Sessions)
+	fmt.Fprintf(out, "  Turns: %d (per session: min %d, median %d, mean %.1f, max %d)\n",
+		a.Turns, a.TurnsPerSession.Min, a.TurnsPerSession.Median, a.TurnsPerSession.Mean, a.TurnsPerSession.Max)
+	fmt.Fprintf(out, "  Total turn duration: %s\n", formatHours(a.TotalDuration))
+	fmt.Fprintf(out, "  Tokens: %s in, %s out, %s cache write, %s cache read\n",
+		session.FormatTokens(a.Usage.InputTokens),
+		session.FormatTokens(a.Usage.OutputTokens),
+		session.FormatTokens(a.Usage.CacheCreationTokens),
+		session.FormatTokens(a.Usage.CacheReadTokens))
+	fmt.Fprintf(out, "  Estimated cost: %s\n", formatCost(a.Usage.Cost, a.Usage.CostComplete))
+
+	section := func(title string) *tabwriter.Writer {
+		fmt.Fprintf(out, "\n%s\n", title)
+		return tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	}
+
+	w := section("SESSIONS PER DAY")
+	busiest := 0
+	for _, d := range a.SessionsPerDay {
+		busiest = max(busiest, d.Sessions)
+	}
+	for _, d := range a.SessionsPerDay {
+		bar := max(1, d.Sessions*statsBarWidth/busiest)
+		fmt.Fprintf(w, "  %s\t%d\t%s\n", d.Date, d.Sessions, strings.Repeat("▇", bar))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	w = section("MODELS")
+	for _, m := range a.Models {
+		fmt.Fprintf(w, "  %s\t%d turns\t%.0f%%\n", m.Model, m.Turns, percent(m.Turns, a.Turns))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	w = section("TOOLS")
+	fmt.Fprintln(w, "  NAME\tCALLS\tERRORS\tERROR RATE")
+	for i, t := range a.Tools {
+		if i == statsTopN {
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f%%\n", t.Name, t.Calls, t.Errors, t.ErrorRate*100)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	w = section("PROJECTS")
+	fmt.Fprintln(w, "  NAME\tSESSIONS\tTURNS\tDURATION")
+	for i, p := range a.Projects {
+		if i == statsTopN {
+			break
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%s\n", p.Name, p.Sessions, p.Turns, formatHours(p.Duration))
+	}
+	return w.Flush()
+}
+
+const (
+	statsTopN     = 15 // tools and projects listed by printAnalytics
+	statsBarWidth = 40 // width of the busiest day's bar
+)
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// formatHours renders a long duration as hours and minutes.
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func writeJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatCost renders a USD amount, marking estimates that miss some models.
+func formatCost(cost float64, ok bool) string {
+	s := fmt.Sprintf("$%.4f", cost)
+	if !ok {
+		s += "*"
+	}
+	return s
+}
+
+// truncatePrompt flattens a user prompt to one line of at most maxLen runes.
+func truncatePrompt(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxLen {
+		return string(r[:maxLen-1]) + "…"
+	}
+	return s
+}
@@ -7,7 +7,6 @@
 #   ./demo/generate.sh <session-id>     # use a specific session
 #
 # Requirements:
-#   - agg (cargo install agg)
 #   - go (to build claude-replay)
 #
 set -euo pipefail
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// Pacing of animated recordings. Typing and streaming take a bounded number
+// of frames however long the text, and no wait between two blocks lasts
+// longer than maxBlockWait, so recordings stay watchable whatever the
+// session's real durations. Waits between turns are cut to
+// animateIdleLimit the same way.
+const (
+	typeInterval     = 30 * time.Millisecond
+	maxTypeFrames    = 40 // per prompt; longer prompts are typed in chunks
+	streamInterval   = 50 * time.Millisecond
+	maxStreamFrames  = 20 // per text block
+	spinnerInterval  = 100 * time.Millisecond
+	maxBlockWait     = 3 * time.Second
+	promptPause      = 400 * time.Millisecond // after a prompt is typed
+	animateIdleLimit = 5 * time.Second        // idle_time_limit of the recording
+)
+
+// spinnerGlyphs are the frames of Claude Code's working indicator.
+var spinnerGlyphs = []string{"·", "✢", "✳", "✶", "✻", "✽"}
+
+// animator writes the animated frames of one turn.
+type animator struct {
+	rec   recorder
+	sess  *session.Session
+	index int
+	opts  Options
+}
+
+// animateSession writes a recording that types each prompt, streams
+// assistant text and shows a spinner while tools run. Tool waits come from
+// the time between a block's record and the next one, scaled by the timing
+// mode.
+func animateSession(rec recorder, sess *session.Session, opts Options) {
+	var last time.Time
+	for i, turn := range sess.Turns {
+		if i > 0 {
+			rec.wait(min(opts.TurnDelay(turn.Timestamp.Sub(last), i), animateIdleLimit))
+		}
+		a := animator{rec: rec, sess: sess, index: i, opts: opts}
+		a.typePrompt(turn)
+		for b := range turn.Blocks {
+			if turn.Blocks[b].Type == session.BlockText {
+				a.stream(turn, b)
+			} else {
+				a.show(replay.PartialTurn(turn, b+1), "")
+			}
+			a.waitAfter(turn, b)
+		}
+		a.show(turn, "")
+		if opts.TimingMode != TimingInstant {
+			rec.wait(500 * time.Millisecond)
+		}
+		last = stepTime(turn, len(turn.Blocks))
+	}
+}
+
+// show writes a frame of the turn, scrolled to its end, with an optional
+// line below it.
+func (a *animator) show(turn session.Turn, footer string) {
+	a.rec.frame(renderTurnFrame(a.sess, a.opts.Annotations, a.index, turn, footer, true, a.opts.Width, a.opts.Height))
+}
+
+// typePrompt types the turn's prompt behind a cursor.
+func (a *animator) typePrompt(turn session.Turn) {
+	text := []rune(turn.UserText)
+	frames := min(len(text), maxTypeFrames)
+
+	partial := replay.PartialTurn(turn, 0)
+	for k := 0; k <= frames; k++ {
+		cut := 0
+		if frames > 0 {
+			cut = k * len(text) / frames
+		}
+		partial.UserText = string(text[:cut]) + "▌"
+		a.show(partial, "")
+		a.rec.wait(typeInterval)
+	}
+	partial.UserText = turn.UserText
+	a.show(partial, "")
+	a.rec.wait(promptPause)
+}
+
+// stream reveals text block b word by word.
+func (a *animator) stream(turn session.Turn, b int) {
+	text := turn.Blocks[b].Text
+	cuts := wordEnds(text)
+	frames := min(len(cuts), maxStreamFrames)
+
+	partial := replay.PartialTurn(turn, b+1)
+	partial.Blocks = append([]session.Block(nil), partial.Blocks...)
+	for k := 1; k <= frames; k++ {
+		partial.Blocks[b].Text = text[:cuts[k*len(cuts)/frames-1]]
+		a.show(partial, "")
+		if k < frames {
+			a.rec.wait(streamInterval)
+		}
+	}
+}
+
+// wordEnds returns the offsets where the words of s end, s's length last.
+func wordEnds(s string) []int {
+	var ends []int
+	inWord := false
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if space && inWord {
+			ends = append(ends, i)
+		}
+		inWord = !space
+	}
+	if len(ends) == 0 || ends[len(ends)-1] != len(s) {
+		ends = append(ends, len(s))
+	}
+	return ends
+}
+
+// waitAfter waits until block b's successor appears, showing a spinner
+// when the wait is long enough to notice: the tool still running, or the
+// model thinking.
+func (a *animator) waitAfter(turn session.Turn, b int) {
+	var gap time.Duration
+	if b+1 < len(turn.Blocks) {
+		from, to := turn.Blocks[b].Timestamp, turn.Blocks[b+1].Timestamp
+		if !from.IsZero() && !to.IsZero() {
+			gap = to.Sub(from)
+		}
+	}
+	wait := min(a.opts.StepDelay(gap), maxBlockWait)
+	frames := int(wait / spinnerInterval)
+	if frames < 2 {
+		a.rec.wait(wait)
+		return
+	}
+
+	label := "Thinking…"
+	if tool := pendingTool(turn, b); tool != "" {
+		label = "Running " + replay.ToolDisplayName(tool) + "…"
+	}
+	partial := replay.PartialTurn(turn, b+1)
+	for j := 0; j < frames; j++ {
+		// The counter shows the session's real time, not the recording's
+		ran := gap * time.Duration(j) / time.Duration(frames)
+		a.show(partial, spinnerLine(j, label, ran))
+		a.rec.wait(spinnerInterval)
+	}
+}
+
+// pendingTool returns the name of the latest tool called in blocks 0..b
+// whose result is not among them.
+func pendingTool(turn session.Turn, b int) string {
+	answered := map[string]bool{}
+	for _, block := range turn.Blocks[:b+1] {
+		if block.Type == session.BlockToolResult {
+			answered[block.ToolID] = true
+		}
+	}
+	for i := b; i >= 0; i-- {
+		block := turn.Blocks[i]
+		if block.Type == session.BlockToolUse && !answered[block.ToolID] {
+			return block.ToolName
+		}
+	}
+	return ""
+}
+
+// spinnerLine renders frame j of the working indicator.
+func spinnerLine(j int, label string, elapsed time.Duration) string {
+	glyph := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Render(spinnerGlyphs[j%len(spinnerGlyphs)])
+	line := "  " + glyph + " " + lipgloss.NewStyle().Foreground(theme.ColorPrimary).Render(label)
+	if elapsed >= time.Second {
+		line += lipgloss.NewStyle().Foreground(theme.ColorDim).Render(fmt.Sprintf(" (%ds)", int(elapsed.Seconds())))
+	}
+	return line
+}
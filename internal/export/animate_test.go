@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+func TestGenerateCast_Animate(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.cast")
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	prompt := strings.Repeat("please run the tests ", 5)
+	answer := strings.Repeat("word ", 100)
+	sess := &session.Session{
+		ID:   "test-session",
+		Slug: "test-slug",
+		Turns: []session.Turn{{
+			Number:    1,
+			UserText:  prompt,
+			Timestamp: start,
+			Blocks: []session.Block{
+				{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]interface{}{"command": "go test ./..."}, Timestamp: start.Add(time.Second)},
+				{Type: session.BlockToolResult, ToolID: "t1", Text: "ok", Timestamp: start.Add(time.Minute + time.Second)},
+				{Type: session.BlockText, Text: answer, Timestamp: start.Add(time.Minute + 2*time.Second)},
+			},
+		}},
+	}
+
+	opts := Options{TimingMode: TimingRealtime, Width: 80, Height: 24, Output: output, Animate: true}
+	if err := GenerateCast(sess, opts); err != nil {
+		t.Fatalf("GenerateCast error: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("parsing header: %v", err)
+	}
+	if header.Version != 2 || header.IdleTimeLimit != animateIdleLimit.Seconds() {
+		t.Errorf("header = %+v, want version 2 with an idle time limit", header)
+	}
+
+	var times []float64
+	var frames []string
+	for _, l := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(l), &event); err != nil {
+			t.Fatalf("parsing event %q: %v", l, err)
+		}
+		if len(event) != 3 || event[1] != "o" {
+			t.Fatalf("not an output event: %q", l)
+		}
+		times = append(times, event[0].(float64))
+		frames = append(frames, stripANSI(event[2].(string)))
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i] < times[i-1] {
+			t.Fatalf("frame %d at %.3fs goes back from %.3fs", i, times[i], times[i-1])
+		}
+	}
+
+	var typing, spinning, streaming int
+	for _, f := range frames {
+		switch {
+		case strings.Contains(f, "▌"):
+			typing++
+		case strings.Contains(f, "Running Bash…"):
+			spinning++
+		case strings.Contains(f, "word") && !strings.Contains(f, "Thinking…"):
+			streaming++
+		}
+	}
+	if typing != maxTypeFrames+1 {
+		t.Errorf("typing frames = %d, want %d", typing, maxTypeFrames+1)
+	}
+	if !strings.Contains(frames[5], "please run") || strings.Contains(frames[5], "tests") {
+		t.Errorf("typing frames should show the start of the prompt:\n%s", frames[5])
+	}
+
+	// The minute-long tool run is capped, its counter follows the real time
+	if spinning != int(maxBlockWait/spinnerInterval) {
+		t.Errorf("spinner frames = %d, want %d", spinning, int(maxBlockWait/spinnerInterval))
+	}
+	last := ""
+	for _, f := range frames {
+		if strings.Contains(f, "Running Bash…") {
+			last = f
+		}
+	}
+	if !strings.Contains(last, "(58s)") {
+		t.Errorf("last spinner frame should count the tool's real duration:\n%s", last)
+	}
+
+	if streaming != maxStreamFrames {
+		t.Errorf("streaming frames = %d, want %d", streaming, maxStreamFrames)
+	}
+	if final := replayCast(t, data, 80, 24, nil).text(); !strings.Contains(final, "word word") || strings.Contains(final, "Running") || strings.Contains(final, "Thinking") {
+		t.Errorf("recording should end on the whole turn:\n%s", final)
+	}
+}
+
+// waitRecorder records the waits of a recording.
+type waitRecorder struct {
+	waits []time.Duration
+}
+
+func (r *waitRecorder) wait(d time.Duration) { r.waits = append(r.waits, d) }
+func (r *waitRecorder) frame(string)         {}
+
+func TestAnimateSession_CapsTurnGaps(t *testing.T) {
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	sess := &session.Session{ID: "test-session", Turns: []session.Turn{
+		{Number: 1, UserText: "one", Timestamp: start},
+		{Number: 2, UserText: "two", Timestamp: start.Add(time.Hour)},
+	}}
+
+	rec := &waitRecorder{}
+	animateSession(rec, sess, Options{TimingMode: TimingRealtime, Width: 40, Height: 10, Animate: true})
+	for _, d := range rec.waits {
+		if d > animateIdleLimit {
+			t.Errorf("waited %v, want at most %v", d, animateIdleLimit)
+		}
+	}
+}
+
+func TestWordEnds(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"", []int{0}},
+		{"one", []int{3}},
+		{"one two", []int{3, 7}},
+		{"one\n\ntwo ", []int{3, 8, 9}},
+	}
+	for _, tt := range tests {
+		if got := wordEnds(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wordEnds(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPendingTool(t *testing.T) {
+	turn := session.Turn{Blocks: []session.Block{
+		{Type: session.BlockToolUse, ToolName: "Read", ToolID: "t1"},
+		{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t2"},
+		{Type: session.BlockToolResult, ToolID: "t2"},
+		{Type: session.BlockToolResult, ToolID: "t1"},
+		{Type: session.BlockText, Text: "done"},
+	}}
+	for b, want := range []string{"Read", "Bash", "Read", "", ""} {
+		if got := pendingTool(turn, b); got != want {
+			t.Errorf("pendingTool(turn, %d) = %q, want %q", b, got, want)
+		}
+	}
+}
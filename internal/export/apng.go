@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+// pngSignature starts every PNG file.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// GenerateAPNG renders the frames of a recording of sess into an animated
+// PNG. Unlike GIF, colors are kept exactly, at the cost of larger files.
+func GenerateAPNG(sess *session.Session, opts Options) error {
+	return generateImage(sess, opts, &apngEncoder{})
+}
+
+// apngFrame is a frame encoded as PNG image data.
+type apngFrame struct {
+	bounds image.Rectangle
+	data   []byte // concatenated IDAT contents
+}
+
+// apngEncoder builds an animated PNG: the first frame is the default
+// image, later frames are drawn over it at their offsets.
+type apngEncoder struct {
+	header []byte // IHDR contents of the first frame
+	frames []apngFrame
+}
+
+func (e *apngEncoder) add(region *image.RGBA) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, region); err != nil {
+		return fmt.Errorf("encoding frame: %w", err)
+	}
+
+	frame := apngFrame{bounds: region.Bounds()}
+	chunks := buf.Bytes()[len(pngSignature):]
+	for len(chunks) >= 12 {
+		n := binary.BigEndian.Uint32(chunks)
+		kind, data := string(chunks[4:8]), chunks[8:8+n]
+		switch kind {
+		case "IHDR":
+			if e.header == nil {
+				e.header = data
+			}
+		case "IDAT":
+			frame.data = append(frame.data, data...)
+		}
+		chunks = chunks[12+n:]
+	}
+	e.frames = append(e.frames, frame)
+	return nil
+}
+
+func (e *apngEncoder) write(w io.Writer, delays []int) error {
+	pw := &pngWriter{w: w}
+	pw.raw([]byte(pngSignature))
+	pw.chunk("IHDR", e.header)
+	pw.chunk("acTL", be32(uint32(len(e.frames)), 0)) // loop forever
+
+	var seq uint32
+	for i, frame := range e.frames {
+		b := frame.bounds
+		control := be32(seq, uint32(b.Dx()), uint32(b.Dy()), uint32(b.Min.X), uint32(b.Min.Y))
+		delay := frameDelay(delays[i])
+		control = append(control, byte(delay>>8), byte(delay), 0, 100) // delay in 1/100s
+		control = append(control, 0, 0)                                // dispose none, blend source
+		pw.chunk("fcTL", control)
+		seq++
+
+		if i == 0 {
+			pw.chunk("IDAT", frame.data)
+			continue
+		}
+		pw.chunk("fdAT", append(be32(seq), frame.data...))
+		seq++
+	}
+	pw.chunk("IEND", nil)
+	if pw.err != nil {
+		return fmt.Errorf("writing APNG: %w", pw.err)
+	}
+	return nil
+}
+
+// pngWriter writes PNG chunks, keeping the first error.
+type pngWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (pw *pngWriter) raw(b []byte) {
+	if pw.err == nil {
+		_, pw.err = pw.w.Write(b)
+	}
+}
+
+// chunk writes a chunk with its length and checksum.
+func (pw *pngWriter) chunk(kind string, data []byte) {
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(kind))
+	crc.Write(data)
+	pw.raw(be32(uint32(len(data))))
+	pw.raw([]byte(kind))
+	pw.raw(data)
+	pw.raw(be32(crc.Sum32()))
+}
+
+// be32 encodes values as consecutive big-endian 32-bit integers.
+func be32(values ...uint32) []byte {
+	b := make([]byte, 0, 4*len(values))
+	for _, v := range values {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
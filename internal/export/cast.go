@@ -3,6 +3,7 @@ package export
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -21,15 +22,13 @@ type castHeader struct {
 	Timestamp int64             `json:"timestamp"`
 	Title     string            `json:"title,omitempty"`
 	Env       map[string]string `json:"env,omitempty"`
+
+	// Players shorten pauses longer than this many seconds
+	IdleTimeLimit float64 `json:"idle_time_limit,omitempty"`
 }
 
 // GenerateCast creates an asciinema .cast file from a session.
 func GenerateCast(sess *session.Session, opts Options) error {
-	// Force TrueColor output so lipgloss emits ANSI color codes
-	// even when stdout is not a TTY (writing to a file).
-	lipgloss.SetColorProfile(termenv.TrueColor)
-	lipgloss.SetHasDarkBackground(true)
-
 	f, err := os.Create(opts.Output)
 	if err != nil {
 		return fmt.Errorf("creating output file: %w", err)
@@ -53,6 +52,9 @@ func GenerateCast(sess *session.Session, opts Options) error {
 			"TERM":  "xterm-256color",
 		},
 	}
+	if opts.Animate {
+		header.IdleTimeLimit = animateIdleLimit.Seconds()
+	}
 
 	headerJSON, err := json.Marshal(header)
 	if err != nil {
@@ -60,57 +62,146 @@ func GenerateCast(sess *session.Session, opts Options) error {
 	}
 	fmt.Fprintf(f, "%s\n", headerJSON)
 
-	// Generate frames
-	var elapsed time.Duration
+	cw := &castWriter{w: f}
+	recordSession(cw, sess, opts)
+	return cw.err
+}
 
-	for i := range sess.Turns {
-		// First frame starts immediately; subsequent frames get a delay
-		if i > 0 {
-			realDuration := sess.Turns[i].Timestamp.Sub(sess.Turns[i-1].Timestamp)
-			elapsed += opts.TurnDelay(realDuration, i)
-		}
+// recorder receives the frames of a recording.
+type recorder interface {
+	// wait advances the clock of the next frame.
+	wait(d time.Duration)
+	// frame records a full-screen frame at the current time.
+	frame(frame string)
+}
 
-		// Render frame
-		frame := RenderFrame(sess, i, opts.Width, opts.Height)
+// recordSession renders the frames of a recording of sess to rec, one per
+// turn or block, or animated.
+func recordSession(rec recorder, sess *session.Session, opts Options) {
+	// Force TrueColor output so lipgloss emits ANSI color codes
+	// even when stdout is not a TTY (writing to a file).
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
 
-		// Replace \n with \r\n for proper terminal rendering.
-		// The cast format bypasses the tty driver's onlcr (NL→CR+NL),
-		// so bare \n only moves the cursor down without returning to column 0.
-		frame = strings.ReplaceAll(frame, "\n", "\r\n")
+	if opts.Animate {
+		animateSession(rec, sess, opts)
+		return
+	}
 
-		// Clear screen + render
-		output := "\033[2J\033[H" + frame
+	// Generate frames
+	var last time.Time // when the records of the previous frame were written
+
+	for i, turn := range sess.Turns {
+		// One frame per turn, or per block with the turn's prompt first
+		steps := []int{-1}
+		if opts.Granularity == GranularityBlock {
+			steps = steps[:0]
+			for n := 0; n <= len(turn.Blocks); n++ {
+				steps = append(steps, n)
+			}
+		}
 
-		// Write event: [time, "o", data]
-		timestamp := float64(elapsed) / float64(time.Second)
-		eventData, err := json.Marshal(output)
-		if err != nil {
-			continue
+		for _, visible := range steps {
+			at := stepTime(turn, visible)
+
+			// First frame starts immediately; subsequent frames get a delay
+			if i > 0 || visible > 0 {
+				realDuration := at.Sub(last)
+				if visible > 0 {
+					rec.wait(opts.StepDelay(realDuration))
+				} else {
+					rec.wait(opts.TurnDelay(realDuration, i))
+				}
+			}
+			last = at
+
+			rec.frame(renderFrame(sess, opts.Annotations, i, visible, opts.Width, opts.Height))
 		}
-		fmt.Fprintf(f, "[%.6f, \"o\", %s]\n", timestamp, eventData)
 
-		// Add a small delay after the frame appears for readability
+		// Add a small delay after the turn appears for readability
 		if opts.TimingMode != TimingInstant {
-			elapsed += 500 * time.Millisecond
+			rec.wait(500 * time.Millisecond)
 		}
 	}
+}
 
-	return nil
+// stepTime returns when the newest of the visible blocks of a turn was
+// written, or the turn's start for its prompt and whole-turn frames.
+func stepTime(turn session.Turn, visible int) time.Time {
+	for i := min(visible, len(turn.Blocks)) - 1; i >= 0; i-- {
+		if t := turn.Blocks[i].Timestamp; !t.IsZero() {
+			return t
+		}
+	}
+	return turn.Timestamp
+}
+
+// castWriter writes frames as asciinema output events, keeping the
+// recording's clock. Each frame is written as its difference from the
+// previous one: only the lines that changed are redrawn, addressed with the
+// cursor, so players don't flicker and long recordings stay small. The first
+// write error is kept and stops later writes.
+type castWriter struct {
+	w       io.Writer
+	elapsed time.Duration
+	screen  []string // lines of the last frame written, nil before the first
+	err     error
 }
 
-// ConvertToGif converts a .cast file to .gif using agg.
-func ConvertToGif(castPath, gifPath string) error {
-	aggPath, err := exec.LookPath("agg")
+// wait advances the clock of the next frame.
+func (cw *castWriter) wait(d time.Duration) {
+	cw.elapsed += d
+}
+
+// frame writes a full-screen frame at the current time. Frames identical to
+// the previous one are not written.
+func (cw *castWriter) frame(frame string) {
+	if cw.err != nil {
+		return
+	}
+
+	output := screenDiff(cw.screen, strings.Split(frame, "\n"))
+	if cw.screen == nil {
+		output = "\033[2J" + output
+	}
+	cw.screen = strings.Split(frame, "\n")
+	if output == "" {
+		return
+	}
+
+	// Write event: [time, "o", data]
+	timestamp := float64(cw.elapsed) / float64(time.Second)
+	eventData, err := json.Marshal(output)
 	if err != nil {
-		return fmt.Errorf("GIF conversion requires 'agg' (https://github.com/asciinema/agg). Install with: cargo install agg")
+		cw.err = fmt.Errorf("marshaling frame: %w", err)
+		return
 	}
-	cmd := exec.Command(aggPath, castPath, gifPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("agg failed: %w", err)
+	if _, err := fmt.Fprintf(cw.w, "[%.6f, \"o\", %s]\n", timestamp, eventData); err != nil {
+		cw.err = err
 	}
-	return nil
+}
+
+// screenDiff returns the output turning a screen showing the lines prev
+// into one showing next. Each changed line is addressed with the cursor
+// (rows count from 1), cleared with the default style and redrawn; lines
+// past the end of next are cleared. Clearing before drawing, rather than
+// after, keeps the last column of full-width lines.
+func screenDiff(prev, next []string) string {
+	var b strings.Builder
+	for i := 0; i < max(len(prev), len(next)); i++ {
+		line := ""
+		if i < len(next) {
+			line = next[i]
+		}
+		if i < len(prev) && prev[i] == line {
+			continue
+		}
+		if i >= len(prev) && line == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\033[%d;1H\033[0m\033[2K%s", i+1, line)
+	}
+	return b.String()
 }
 
 // ConvertToMP4 converts a .gif to .mp4 using ffmpeg.
@@ -1,14 +1,19 @@
 package export
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
 )
 
@@ -389,3 +394,310 @@ func TestTimingModes(t *testing.T) {
 		t.Errorf("instant: expected 100ms, got %v", delay)
 	}
 }
+
+func TestGenerateCast_BlockGranularity(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.cast")
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	sess := &session.Session{
+		ID:   "test-session",
+		Slug: "test-slug",
+		Turns: []session.Turn{
+			{
+				Number:    1,
+				UserText:  "List files",
+				Timestamp: start,
+				Blocks: []session.Block{
+					{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]interface{}{"command": "ls"}, Timestamp: start.Add(2 * time.Second)},
+					{Type: session.BlockToolResult, ToolID: "t1", Text: "main.go", Timestamp: start.Add(5 * time.Second)},
+					{Type: session.BlockText, Text: "There is one file", Timestamp: start.Add(6 * time.Second)},
+				},
+			},
+			{
+				Number:    2,
+				UserText:  "Thanks",
+				Timestamp: start.Add(time.Minute),
+			},
+		},
+	}
+
+	opts := Options{TimingMode: TimingRealtime, Width: 80, Height: 24, Output: output, Granularity: GranularityBlock}
+	if err := GenerateCast(sess, opts); err != nil {
+		t.Fatalf("GenerateCast error: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")[1:]
+	if len(lines) != 5 { // prompt + 3 blocks, then the second prompt
+		t.Fatalf("expected 5 frames, got %d", len(lines))
+	}
+	var times []float64
+	var frames []string
+	for _, l := range lines {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(l), &event); err != nil {
+			t.Fatalf("parsing event: %v", err)
+		}
+		times = append(times, event[0].(float64))
+		frames = append(frames, stripANSI(event[2].(string)))
+	}
+
+	// Blocks are paced by their records' timestamps
+	want := []float64{0, 2, 5, 6, 60.5}
+	for i := range want {
+		if times[i] != want[i] {
+			t.Errorf("frame %d at %.1fs, want %.1fs", i, times[i], want[i])
+		}
+	}
+
+	if strings.Contains(frames[0], "ls") || !strings.Contains(frames[0], "List files") {
+		t.Errorf("first frame should show the prompt only:\n%s", frames[0])
+	}
+	if !strings.Contains(frames[1], "Bash(ls)") || strings.Contains(frames[1], "main.go") {
+		t.Errorf("second frame should show the tool call without its result:\n%s", frames[1])
+	}
+	if !strings.Contains(frames[3], "There is one file") {
+		t.Errorf("last frame of the turn should show every block:\n%s", frames[3])
+	}
+}
+
+func TestRenderStepFrame_ScrollsToNewestBlock(t *testing.T) {
+	var blocks []session.Block
+	for i := 1; i <= 30; i++ {
+		blocks = append(blocks, session.Block{Type: session.BlockText, Text: fmt.Sprintf("paragraph %d", i)})
+	}
+	sess := &session.Session{ID: "test-session", Slug: "s", Turns: []session.Turn{{Number: 1, UserText: "go", Blocks: blocks}}}
+
+	frame := stripANSI(RenderStepFrame(sess, 0, 25, 80, 24))
+	if !strings.Contains(frame, "paragraph 25") || strings.Contains(frame, "paragraph 26") || regexp.MustCompile(`paragraph 1\s`).MatchString(frame) {
+		t.Errorf("step frame should end at the newest block:\n%s", frame)
+	}
+	if whole := stripANSI(RenderFrame(sess, 0, 80, 24)); !regexp.MustCompile(`paragraph 1\s`).MatchString(whole) {
+		t.Errorf("whole-turn frame should start at the top:\n%s", whole)
+	}
+}
+
+// terminal is a minimal terminal emulator to replay recordings: it keeps
+// the characters on screen and follows the cursor movements and erasures
+// the cast writer emits. Styles are ignored.
+type terminal struct {
+	cells    [][]string
+	row, col int
+}
+
+func newTerminal(width, height int) *terminal {
+	t := &terminal{cells: make([][]string, height)}
+	for i := range t.cells {
+		t.cells[i] = blankRow(width)
+	}
+	return t
+}
+
+func blankRow(width int) []string {
+	row := make([]string, width)
+	for i := range row {
+		row[i] = " "
+	}
+	return row
+}
+
+func (t *terminal) write(s string) {
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "\033["):
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			t.csi(s[i+2:j], s[j])
+			i = j + 1
+		case s[i] == '\r':
+			t.col = 0
+			i++
+		case s[i] == '\n':
+			t.lineFeed()
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			t.print(string(r))
+			i += size
+		}
+	}
+}
+
+func (t *terminal) print(ch string) {
+	w := lipgloss.Width(ch)
+	if w == 0 {
+		if t.col > 0 {
+			t.cells[t.row][t.col-1] += ch
+		}
+		return
+	}
+	width := len(t.cells[0])
+	if t.col+w > width {
+		t.col = 0
+		t.lineFeed()
+	}
+	t.cells[t.row][t.col] = ch
+	if w == 2 {
+		t.cells[t.row][t.col+1] = ""
+	}
+	t.col += w
+}
+
+func (t *terminal) lineFeed() {
+	if t.row < len(t.cells)-1 {
+		t.row++
+		return
+	}
+	t.cells = append(t.cells[1:], blankRow(len(t.cells[0])))
+}
+
+func (t *terminal) csi(params string, final byte) {
+	args := strings.Split(strings.TrimPrefix(params, "?"), ";")
+	arg := func(i, def int) int {
+		if i < len(args) {
+			if n, err := strconv.Atoi(args[i]); err == nil {
+				return n
+			}
+		}
+		return def
+	}
+	switch final {
+	case 'H':
+		t.row = min(max(arg(0, 1), 1), len(t.cells)) - 1
+		t.col = min(max(arg(1, 1), 1), len(t.cells[0])) - 1
+	case 'J':
+		if arg(0, 0) == 2 {
+			for i := range t.cells {
+				t.cells[i] = blankRow(len(t.cells[i]))
+			}
+		}
+	case 'K':
+		from := t.col
+		if arg(0, 0) == 2 {
+			from = 0
+		}
+		for i := from; i < len(t.cells[t.row]); i++ {
+			t.cells[t.row][i] = " "
+		}
+	}
+}
+
+// text returns the screen's lines without trailing spaces.
+func (t *terminal) text() string {
+	lines := make([]string, len(t.cells))
+	for i, row := range t.cells {
+		lines[i] = strings.TrimRight(strings.Join(row, ""), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// screenText returns how a frame looks on a terminal of the given height.
+func screenText(frame string, height int) string {
+	lines := strings.Split(stripANSI(frame), "\n")
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// replayCast plays a recording's events into a terminal and returns it,
+// calling check after each event.
+func replayCast(t *testing.T, data []byte, width, height int, check func(event int, term *terminal)) *terminal {
+	t.Helper()
+	term := newTerminal(width, height)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, l := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(l), &event); err != nil {
+			t.Fatalf("parsing event %d: %v", i, err)
+		}
+		term.write(event[2].(string))
+		if check != nil {
+			check(i, term)
+		}
+	}
+	return term
+}
+
+func TestCastWriter_ReplaysFrames(t *testing.T) {
+	frames := []string{
+		"header\nfirst line\nsecond line\n\nfooter",
+		"header\nfirst\nsecond line, longer\n\nfooter",
+		"header\nfirst\nsecond line, longer\n\nfooter", // unchanged
+		"header\n★ wide 中文 line\n\nfooter",
+		"header\n" + strings.Repeat("x", 40) + "\n\x1b[1mbold\x1b[0m",
+		"header",
+	}
+	var buf bytes.Buffer
+	buf.WriteString("{}\n")
+	cw := &castWriter{w: &buf}
+	for _, f := range frames {
+		cw.frame(f)
+	}
+	if cw.err != nil {
+		t.Fatalf("writing frames: %v", cw.err)
+	}
+	if n := strings.Count(buf.String(), "\n") - 1; n != len(frames)-1 {
+		t.Errorf("wrote %d events, want %d (identical frames are skipped)", n, len(frames)-1)
+	}
+
+	want := append(frames[:2:2], frames[3:]...)
+	replayCast(t, buf.Bytes(), 40, 6, func(i int, term *terminal) {
+		if got, exp := term.text(), screenText(want[i], 6); got != exp {
+			t.Errorf("screen after event %d:\n%s\nwant:\n%s", i, got, exp)
+		}
+	})
+
+	// Unchanged lines are not redrawn
+	events := strings.Split(buf.String(), "\n")
+	if strings.Contains(events[2], "header") || strings.Contains(events[2], "footer") {
+		t.Errorf("second event should redraw only changed lines: %s", events[2])
+	}
+}
+
+func TestGenerateCast_ReplaysInTerminal(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.cast")
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	var turns []session.Turn
+	for i := 1; i <= 3; i++ {
+		turns = append(turns, session.Turn{
+			Number:    i,
+			UserText:  fmt.Sprintf("Question %d", i),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Blocks: []session.Block{
+				{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]interface{}{"command": "ls"}},
+				{Type: session.BlockToolResult, ToolID: "t1", Text: strings.Repeat("file.go\n", 30)},
+				{Type: session.BlockText, Text: fmt.Sprintf("Answer %d: %s", i, strings.Repeat("lorem ipsum ", 40))},
+			},
+		})
+	}
+	sess := &session.Session{ID: "test-session", Slug: "test-slug", Turns: turns}
+
+	opts := Options{TimingMode: TimingCompressed, Width: 80, Height: 24, Output: output, Animate: true}
+	if err := GenerateCast(sess, opts); err != nil {
+		t.Fatalf("GenerateCast error: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+
+	term := replayCast(t, data, opts.Width, opts.Height, nil)
+	last := renderTurnFrame(sess, nil, 2, turns[2], "", true, opts.Width, opts.Height)
+	if got, want := term.text(), screenText(last, opts.Height); got != want {
+		t.Errorf("final screen:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Full redraws of every frame would be much larger
+	events := strings.Count(string(data), "\n") - 1
+	if full := events * len(last); len(data) > full/2 {
+		t.Errorf("recording is %d bytes, full redraws would be about %d", len(data), full)
+	}
+}
@@ -0,0 +1,265 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+// finalHold is how long the last frame of a GIF or APNG shows before the
+// animation loops.
+const finalHold = 3 * time.Second
+
+// frameEncoder stores the rasterized frames of an image recording and
+// writes them out once the recording is complete.
+type frameEncoder interface {
+	// add stores a frame drawing the region of the canvas that changed.
+	add(region *image.RGBA) error
+	// write writes the animation, given each frame's delay in hundredths
+	// of a second.
+	write(w io.Writer, delays []int) error
+}
+
+// imageRecorder rasterizes the frames of a recording. Each frame only
+// redraws the rectangle of cells that changed since the previous one, so
+// encoded animations stay small. Frames recorded within the same hundredth
+// of a second replace each other. The first error is kept and stops later
+// frames.
+type imageRecorder struct {
+	enc       frameEncoder
+	r         *rasterizer
+	cols      int
+	rows      int
+	idleLimit time.Duration // longest delay between two frames
+
+	canvas  *image.RGBA
+	shown   *screen // screen drawn on the canvas, nil before the first
+	starts  []int   // when each stored frame appears, in hundredths of a second
+	elapsed time.Duration
+
+	pending   string
+	pendingAt int
+	queued    bool
+	err       error
+}
+
+// newImageRecorder returns a recorder rasterizing frames of the size set
+// in opts for enc.
+func newImageRecorder(enc frameEncoder, opts Options) (*imageRecorder, error) {
+	r, err := newRasterizer()
+	if err != nil {
+		return nil, err
+	}
+	// Long pauses are cut in every timing mode, as asciinema players do
+	return &imageRecorder{
+		enc:       enc,
+		r:         r,
+		cols:      opts.Width,
+		rows:      opts.Height,
+		idleLimit: animateIdleLimit,
+		canvas:    image.NewRGBA(r.size(opts.Width, opts.Height)),
+	}, nil
+}
+
+// wait advances the clock of the next frame.
+func (ir *imageRecorder) wait(d time.Duration) {
+	ir.elapsed += d
+}
+
+// frame records a full-screen frame at the current time.
+func (ir *imageRecorder) frame(frame string) {
+	at := centiseconds(ir.elapsed)
+	if ir.queued && at > ir.pendingAt {
+		ir.flush()
+	}
+	ir.pending, ir.pendingAt, ir.queued = frame, at, true
+}
+
+// flush draws the queued frame and stores the cells that changed.
+func (ir *imageRecorder) flush() {
+	if !ir.queued || ir.err != nil {
+		return
+	}
+	ir.queued = false
+
+	next := parseScreen(ir.pending, ir.cols, ir.rows)
+	area := image.Rect(0, 0, ir.cols, ir.rows)
+	if ir.shown != nil {
+		area = changedCells(ir.shown, next)
+	}
+	ir.shown = next
+	if area.Empty() {
+		return
+	}
+
+	ir.r.drawCells(ir.canvas, next, area)
+	pixels := image.Rect(area.Min.X*ir.r.cellW, area.Min.Y*ir.r.cellH, area.Max.X*ir.r.cellW, area.Max.Y*ir.r.cellH)
+	if err := ir.enc.add(ir.canvas.SubImage(pixels).(*image.RGBA)); err != nil {
+		ir.err = err
+		return
+	}
+	ir.starts = append(ir.starts, ir.pendingAt)
+}
+
+// finish writes the recorded animation to w. The last frame shows for the
+// time left on the clock, and at least finalHold.
+func (ir *imageRecorder) finish(w io.Writer) error {
+	ir.flush()
+	if ir.err != nil {
+		return ir.err
+	}
+	if len(ir.starts) == 0 {
+		return fmt.Errorf("recording has no frames")
+	}
+
+	end := max(centiseconds(ir.elapsed), ir.starts[len(ir.starts)-1]+centiseconds(finalHold))
+	delays := make([]int, len(ir.starts))
+	for i, start := range ir.starts {
+		next := end
+		if i+1 < len(ir.starts) {
+			next = ir.starts[i+1]
+		}
+		delays[i] = next - start
+		if i+1 < len(ir.starts) {
+			delays[i] = min(delays[i], centiseconds(ir.idleLimit))
+		}
+	}
+	return ir.enc.write(w, delays)
+}
+
+// centiseconds converts d to the hundredths of a second image formats
+// count delays in.
+func centiseconds(d time.Duration) int {
+	return int((d + 5*time.Millisecond) / (10 * time.Millisecond))
+}
+
+// maxFrameDelay is the longest frame delay GIF and APNG can store, in
+// hundredths of a second.
+const maxFrameDelay = 1<<16 - 1
+
+// frameDelay clamps a delay to what GIF and APNG can store.
+func frameDelay(cs int) int {
+	return min(max(cs, 0), maxFrameDelay)
+}
+
+// generateImage records sess with enc and writes the animation to
+// opts.Output.
+func generateImage(sess *session.Session, opts Options, enc frameEncoder) error {
+	rec, err := newImageRecorder(enc, opts)
+	if err != nil {
+		return err
+	}
+	recordSession(rec, sess, opts)
+
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	if err := rec.finish(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// FormatImageInfo returns info about a generated animated image.
+func FormatImageInfo(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return path
+	}
+	return fmt.Sprintf("%s (%s)", path, formatFileSize(info.Size()))
+}
+
+// GenerateGIF renders the frames of a recording of sess, the same as
+// GenerateCast writes, into an animated GIF, without external tools.
+func GenerateGIF(sess *session.Session, opts Options) error {
+	return generateImage(sess, opts, &gifEncoder{})
+}
+
+// gifEncoder builds an animated GIF. Each frame gets its own palette of the
+// colors it uses, so true colors survive as long as a frame holds at most
+// 256 of them.
+type gifEncoder struct {
+	g gif.GIF
+}
+
+func (e *gifEncoder) add(region *image.RGBA) error {
+	if len(e.g.Image) == 0 {
+		b := region.Bounds()
+		e.g.Config = image.Config{Width: b.Max.X, Height: b.Max.Y}
+	}
+	e.g.Image = append(e.g.Image, paletted(region))
+	e.g.Disposal = append(e.g.Disposal, gif.DisposalNone)
+	return nil
+}
+
+func (e *gifEncoder) write(w io.Writer, delays []int) error {
+	e.g.Delay = make([]int, len(delays))
+	for i, d := range delays {
+		e.g.Delay[i] = frameDelay(d)
+	}
+	if err := gif.EncodeAll(w, &e.g); err != nil {
+		return fmt.Errorf("encoding GIF: %w", err)
+	}
+	return nil
+}
+
+// paletted converts img to a paletted image of its most used colors;
+// beyond 256 colors, the rarer ones map to their nearest palette entry.
+func paletted(img *image.RGBA) *image.Paletted {
+	b := img.Bounds()
+	counts := map[color.RGBA]int{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			counts[img.RGBAAt(x, y)]++
+		}
+	}
+
+	colors := make([]color.RGBA, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+		return rgbaKey(colors[i]) < rgbaKey(colors[j])
+	})
+	if len(colors) > 256 {
+		colors = colors[:256]
+	}
+
+	palette := make(color.Palette, len(colors))
+	index := make(map[color.RGBA]uint8, len(counts))
+	for i, c := range colors {
+		palette[i] = c
+		index[c] = uint8(i)
+	}
+
+	out := image.NewPaletted(b, palette)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			i, ok := index[c]
+			if !ok {
+				i = uint8(palette.Index(c))
+				index[c] = i
+			}
+			out.Pix[out.PixOffset(x, y)] = i
+		}
+	}
+	return out
+}
+
+// rgbaKey orders colors deterministically.
+func rgbaKey(c color.RGBA) uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
@@ -0,0 +1,214 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+// imageTestSession returns a session of two short turns.
+func imageTestSession() *session.Session {
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	return &session.Session{
+		ID:   "test-session",
+		Slug: "test-slug",
+		Turns: []session.Turn{
+			{
+				Number:    1,
+				UserText:  "Hello",
+				Timestamp: start,
+				Blocks:    []session.Block{{Type: session.BlockText, Text: "Hi there!"}},
+			},
+			{
+				Number:    2,
+				UserText:  "List the files",
+				Timestamp: start.Add(time.Minute),
+				Blocks: []session.Block{
+					{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]interface{}{"command": "ls"}},
+					{Type: session.BlockToolResult, ToolID: "t1", Text: "main.go\ngo.mod"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateGIF(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.gif")
+	opts := Options{TimingMode: TimingCompressed, Width: 60, Height: 16, Output: output, Granularity: GranularityBlock}
+	if err := GenerateGIF(imageTestSession(), opts); err != nil {
+		t.Fatalf("GenerateGIF error: %v", err)
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatalf("opening output: %v", err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("decoding GIF: %v", err)
+	}
+
+	r, _ := newRasterizer()
+	size := r.size(opts.Width, opts.Height)
+	if g.Config.Width != size.Dx() || g.Config.Height != size.Dy() {
+		t.Errorf("GIF is %dx%d, want %v", g.Config.Width, g.Config.Height, size.Size())
+	}
+	// Prompt and block frames of both turns
+	if len(g.Image) < 4 {
+		t.Fatalf("got %d frames, want at least 4", len(g.Image))
+	}
+	if g.Image[0].Bounds() != size {
+		t.Errorf("first frame covers %v, want the whole screen %v", g.Image[0].Bounds(), size)
+	}
+	smaller := false
+	for _, img := range g.Image[1:] {
+		if !img.Bounds().In(size) {
+			t.Errorf("frame %v outside the screen", img.Bounds())
+		}
+		smaller = smaller || img.Bounds() != size
+	}
+	if !smaller {
+		t.Errorf("every frame redraws the whole screen, want only changed cells")
+	}
+
+	total := 0
+	for _, d := range g.Delay {
+		total += d
+	}
+	// Compressed mode: 2s between turns, 0.4s between blocks, 0.5s after
+	// each turn, plus the final hold
+	if total < 500 {
+		t.Errorf("GIF lasts %d/100s, want at least 5s", total)
+	}
+	if last := g.Delay[len(g.Delay)-1]; last < 300 {
+		t.Errorf("last frame shows for %d/100s, want at least 3s", last)
+	}
+}
+
+func TestImageRecorder_MergesFramesWithinACentisecond(t *testing.T) {
+	enc := &gifEncoder{}
+	rec, err := newImageRecorder(enc, Options{Width: 10, Height: 2})
+	if err != nil {
+		t.Fatalf("newImageRecorder: %v", err)
+	}
+	rec.frame("one")
+	rec.wait(time.Millisecond)
+	rec.frame("two") // replaces "one"
+	rec.wait(time.Second)
+	rec.frame("two") // unchanged
+	rec.wait(time.Second)
+	rec.frame("three")
+
+	var buf bytes.Buffer
+	if err := rec.finish(&buf); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if len(enc.g.Image) != 2 {
+		t.Fatalf("stored %d frames, want 2", len(enc.g.Image))
+	}
+	if got := enc.g.Delay; got[0] != 200 || got[1] != 300 {
+		t.Errorf("delays = %v, want [200 300]", got)
+	}
+}
+
+func TestGenerateGIF_LongPause(t *testing.T) {
+	sess := imageTestSession()
+	sess.Turns[1].Timestamp = sess.Turns[0].Timestamp.Add(20 * time.Minute)
+	output := filepath.Join(t.TempDir(), "test.gif")
+	opts := Options{TimingMode: TimingRealtime, Width: 60, Height: 16, Output: output}
+	if err := GenerateGIF(sess, opts); err != nil {
+		t.Fatalf("GenerateGIF error: %v", err)
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatalf("opening output: %v", err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("decoding GIF: %v", err)
+	}
+	for i, d := range g.Delay[:len(g.Delay)-1] {
+		if d > centiseconds(animateIdleLimit) {
+			t.Errorf("frame %d shows for %d/100s, want the pause cut to the idle limit", i, d)
+		}
+	}
+}
+
+func TestImageRecorder_ClampsDelays(t *testing.T) {
+	enc := &apngEncoder{}
+	rec, err := newImageRecorder(enc, Options{Width: 10, Height: 2})
+	if err != nil {
+		t.Fatalf("newImageRecorder: %v", err)
+	}
+	rec.frame("one")
+	rec.wait(20 * time.Minute)
+	rec.frame("two")
+	rec.wait(20 * time.Minute) // the last frame holds for the time left
+
+	var buf bytes.Buffer
+	if err := rec.finish(&buf); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+
+	var delays []int
+	rest := buf.Bytes()[len(pngSignature):]
+	for len(rest) >= 12 {
+		n := binary.BigEndian.Uint32(rest)
+		if string(rest[4:8]) == "fcTL" {
+			delays = append(delays, int(binary.BigEndian.Uint16(rest[8+20:])))
+		}
+		rest = rest[12+n:]
+	}
+	if len(delays) != 2 || delays[0] != 500 || delays[1] != maxFrameDelay {
+		t.Errorf("delays = %v, want [500 %d]", delays, maxFrameDelay)
+	}
+}
+
+func TestGenerateAPNG(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.png")
+	opts := Options{TimingMode: TimingCompressed, Width: 60, Height: 16, Output: output}
+	if err := GenerateAPNG(imageTestSession(), opts); err != nil {
+		t.Fatalf("GenerateAPNG error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+
+	// Viewers without APNG support show the first frame
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	r, _ := newRasterizer()
+	if got, want := img.Bounds(), r.size(opts.Width, opts.Height); got != want {
+		t.Errorf("PNG covers %v, want %v", got, want)
+	}
+
+	chunks := map[string]int{}
+	var frames uint32
+	rest := data[len(pngSignature):]
+	for len(rest) >= 12 {
+		n := binary.BigEndian.Uint32(rest)
+		kind := string(rest[4:8])
+		chunks[kind]++
+		if kind == "acTL" {
+			frames = binary.BigEndian.Uint32(rest[8:])
+		}
+		rest = rest[12+n:]
+	}
+	if frames != 2 || chunks["fcTL"] != 2 || chunks["fdAT"] != 1 || chunks["IEND"] != 1 {
+		t.Errorf("chunks = %v, acTL frames = %d; want 2 frames, one of them fdAT", chunks, frames)
+	}
+}
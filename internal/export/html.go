@@ -0,0 +1,389 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/components"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+var htmlMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// GenerateHTML writes a self-contained HTML transcript of a session.
+func GenerateHTML(sess *session.Session, opts Options) error {
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := WriteHTML(f, sess, opts); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteHTML renders a session as a single HTML page with all CSS and JS
+// inlined, so it can be opened offline or attached to a ticket. Turns with
+// annotations in opts show their notes and are marked in the index.
+func WriteHTML(w io.Writer, sess *session.Session, opts Options) error {
+	title := sess.Slug
+	if title == "" && len(sess.ID) > 8 {
+		title = sess.ID[:8]
+	}
+
+	page := htmlPage{
+		Title:   title,
+		Session: sess,
+		Turns:   make([]htmlTurn, len(sess.Turns)),
+	}
+	for i, turn := range sess.Turns {
+		page.Turns[i] = newHTMLTurn(turn, sess.CWD)
+		for _, item := range opts.Annotations.ForTurn(turn.Number) {
+			page.Turns[i].Notes = append(page.Turns[i].Notes, replay.AnnotationText(item))
+		}
+	}
+
+	if err := htmlTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("rendering HTML: %w", err)
+	}
+	return nil
+}
+
+type htmlPage struct {
+	Title   string
+	Session *session.Session
+	Turns   []htmlTurn
+}
+
+type htmlTurn struct {
+	session.Turn
+	Body  template.HTML
+	Notes []string // annotations
+}
+
+func newHTMLTurn(turn session.Turn, cwd string) htmlTurn {
+	return htmlTurn{Turn: turn, Body: renderHTMLBlocks(turn, cwd)}
+}
+
+// renderHTMLBlocks renders a turn's blocks. Tool results are nested in the
+// <details> element of the tool_use they answer.
+func renderHTMLBlocks(turn session.Turn, cwd string) template.HTML {
+	results := map[string]session.Block{}
+	for _, block := range turn.Blocks {
+		if block.Type == session.BlockToolResult {
+			results[block.ToolID] = block
+		}
+	}
+	readContents := readContentsFor(turn)
+
+	var b strings.Builder
+	used := map[string]bool{}
+	for _, block := range turn.Blocks {
+		switch block.Type {
+		case session.BlockText:
+			b.WriteString(`<div class="text">`)
+			b.WriteString(markdownHTML(block.Text))
+			b.WriteString("</div>\n")
+
+		case session.BlockThinking:
+			fmt.Fprintf(&b, `<details class="thinking"><summary>thinking (%d chars)</summary><pre>%s</pre></details>`+"\n",
+				len(block.Text), template.HTMLEscapeString(block.Text))
+
+		case session.BlockToolUse:
+			result, hasResult := results[block.ToolID]
+			used[block.ToolID] = hasResult
+			writeHTMLToolUse(&b, block, result, hasResult, cwd, readContents)
+
+		case session.BlockToolResult:
+			if used[block.ToolID] {
+				continue
+			}
+			writeHTMLToolResult(&b, block)
+
+		case session.BlockCompaction, session.BlockAPIError, session.BlockHook, session.BlockInterruption:
+			writeHTMLEvent(&b, block)
+		}
+	}
+	return template.HTML(b.String())
+}
+
+// writeHTMLEvent writes a compaction, API error, hook or interruption. A
+// compaction's summary is rendered as markdown, other texts as is.
+func writeHTMLEvent(b *strings.Builder, block session.Block) {
+	class := "event"
+	if block.Type == session.BlockAPIError || block.Type == session.BlockInterruption {
+		class += " error"
+	}
+	title := template.HTMLEscapeString(components.EventSymbol(block.Type) + " " + replay.EventTitle(block))
+	text := strings.TrimSpace(block.Text)
+	if text == "" || block.Type == session.BlockInterruption {
+		fmt.Fprintf(b, `<div class="%s">%s</div>`+"\n", class, title)
+		return
+	}
+	fmt.Fprintf(b, `<details class="%s"><summary>%s</summary>`, class, title)
+	if block.Type == session.BlockCompaction {
+		fmt.Fprintf(b, `<div class="text">%s</div>`, markdownHTML(text))
+	} else {
+		fmt.Fprintf(b, `<pre>%s</pre>`, template.HTMLEscapeString(text))
+	}
+	b.WriteString("</details>\n")
+}
+
+func writeHTMLToolUse(b *strings.Builder, block, result session.Block, hasResult bool, cwd string, readContents map[string]string) {
+	// Diffs are always visible, matching the TUI
+	open := ""
+	if block.ToolName == "Edit" || block.ToolName == "Write" {
+		open = " open"
+	}
+	class := "tool"
+	if hasResult && result.IsError {
+		class += " error"
+	}
+
+	summary := template.HTMLEscapeString(replay.ToolDisplayName(block.ToolName))
+	if brief := replay.ToolBriefParam(block, cwd); brief != "" {
+		summary += `<span class="param">(` + template.HTMLEscapeString(brief) + `)</span>`
+	}
+	fmt.Fprintf(b, `<details class="%s"%s><summary>%s</summary>`+"\n", class, open, summary)
+
+	path, _ := block.ToolInput["file_path"].(string)
+	switch block.ToolName {
+	case "Edit":
+		oldStr, _ := block.ToolInput["old_string"].(string)
+		newStr, _ := block.ToolInput["new_string"].(string)
+		writeHTMLDiff(b, replay.ComputeDiff(oldStr, newStr), path)
+	case "Write":
+		content, _ := block.ToolInput["content"].(string)
+		if old, ok := readContents[path]; ok {
+			writeHTMLDiff(b, replay.ComputeDiff(old, content), path)
+		} else {
+			writeHTMLDiff(b, replay.ComputeDiff("", content), path)
+		}
+	case "Bash":
+		cmd, _ := block.ToolInput["command"].(string)
+		fmt.Fprintf(b, `<pre class="input">%s</pre>`+"\n", template.HTMLEscapeString(cmd))
+	default:
+		if block.ToolInput != nil {
+			if pretty, err := json.MarshalIndent(block.ToolInput, "", "  "); err == nil {
+				fmt.Fprintf(b, `<pre class="input">%s</pre>`+"\n", template.HTMLEscapeString(string(pretty)))
+			}
+		}
+	}
+
+	if len(block.Sidechain) > 0 {
+		fmt.Fprintf(b, `<details class="subagent"><summary>subagent transcript (%d turns)</summary>`+"\n", len(block.Sidechain))
+		for _, sub := range block.Sidechain {
+			b.WriteString(`<div class="user">` + template.HTMLEscapeString(sub.UserText) + "</div>\n")
+			b.WriteString(string(renderHTMLBlocks(sub, cwd)))
+		}
+		b.WriteString("</details>\n")
+	}
+
+	if hasResult {
+		writeHTMLToolResult(b, result)
+	}
+	b.WriteString("</details>\n")
+}
+
+func writeHTMLToolResult(b *strings.Builder, block session.Block) {
+	class := "result"
+	if block.IsError {
+		class += " error"
+	}
+	text := block.Text
+	if text == "" {
+		text = "(No output)"
+	}
+	fmt.Fprintf(b, `<pre class="%s">%s</pre>`+"\n", class, template.HTMLEscapeString(text))
+}
+
+// writeHTMLDiff renders diff lines with syntax highlighting for path.
+func writeHTMLDiff(b *strings.Builder, ops []replay.DiffOp, path string) {
+	b.WriteString(`<div class="diff">`)
+	for _, op := range ops {
+		class, sign := "ctx", " "
+		switch op.Kind {
+		case '+':
+			class, sign = "add", "+"
+		case '-':
+			class, sign = "del", "-"
+		}
+		fmt.Fprintf(b, `<div class="%s"><span class="sign">%s</span>`, class, sign)
+		for _, span := range replay.HighlightSpans(path, op.Text) {
+			text := template.HTMLEscapeString(span.Text)
+			if span.Color != "" {
+				fmt.Fprintf(b, `<span style="color:%s">%s</span>`, span.Color, text)
+			} else {
+				b.WriteString(text)
+			}
+		}
+		b.WriteString("</div>")
+	}
+	b.WriteString("</div>\n")
+}
+
+// readContentsFor maps file paths to their content from successful Read
+// results in the turn, used as the baseline for Write diffs.
+func readContentsFor(turn session.Turn) map[string]string {
+	readContents := map[string]string{}
+	for i, block := range turn.Blocks {
+		if block.Type != session.BlockToolUse || block.ToolName != "Read" {
+			continue
+		}
+		path, _ := block.ToolInput["file_path"].(string)
+		if path == "" {
+			continue
+		}
+		for _, next := range turn.Blocks[i+1:] {
+			if next.Type == session.BlockToolResult && next.ToolID == block.ToolID && !next.IsError {
+				readContents[path] = next.Text
+				break
+			}
+		}
+	}
+	return readContents
+}
+
+func markdownHTML(text string) string {
+	var buf bytes.Buffer
+	if err := htmlMarkdown.Convert([]byte(text), &buf); err != nil {
+		return "<p>" + template.HTMLEscapeString(text) + "</p>"
+	}
+	return buf.String()
+}
+
+// truncateTitle shortens a user message for the turn index sidebar.
+func truncateTitle(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= maxLen {
+		return s
+	}
+	return string([]rune(s)[:maxLen-1]) + "…"
+}
+
+var htmlTemplate = template.Must(template.New("session").Funcs(template.FuncMap{
+	"truncate":       truncateTitle,
+	"formatDuration": replay.FormatDuration,
+}).Parse(htmlSource))
+
+const htmlSource = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} — claude-replay</title>
+<style>
+:root {
+  --bg: #1A1B26; --bg-alt: #24283B; --text: #C0CAF5; --dim: #565656; --secondary: #A0A0A0;
+  --primary: #D4A574; --accent: #7AA2F7; --success: #9ECE6A; --error: #F7768E; --thinking: #BB9AF7;
+  --add-bg: #225A34; --del-bg: #5A2234; --diff-fg: #DEE4EE; --diff-ctx: #96A0AA;
+}
+* { box-sizing: border-box; }
+body { margin: 0; background: var(--bg); color: var(--text); font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; }
+pre, code, .diff { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 13px; }
+a { color: var(--accent); }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 280px; overflow-y: auto; background: var(--bg-alt); padding: 16px 0; }
+nav h1 { color: var(--primary); font-size: 16px; margin: 0 16px 4px; }
+nav .meta { color: var(--secondary); font-size: 12px; margin: 0 16px 12px; word-break: break-all; }
+nav .controls { margin: 0 16px 12px; }
+nav button { background: var(--bg); color: var(--text); border: 1px solid var(--dim); border-radius: 4px; padding: 2px 8px; cursor: pointer; }
+nav ol { list-style: none; margin: 0; padding: 0; }
+nav li a { display: block; padding: 4px 16px; color: var(--secondary); text-decoration: none; font-size: 13px; }
+nav li a:hover, nav li a.active { background: var(--bg); color: var(--primary); }
+main { margin-left: 280px; padding: 24px 32px; max-width: 1100px; }
+.turn { border-bottom: 1px solid var(--bg-alt); padding: 8px 0 24px; }
+.turn h2 { font-size: 13px; color: var(--dim); font-weight: normal; margin: 0 0 8px; }
+.note { color: var(--success); font-style: italic; background: var(--bg-alt); border-left: 2px solid var(--success); padding: 4px 8px; margin: 4px 0; }
+nav .mark { color: var(--success); }
+.user { color: var(--success); white-space: pre-wrap; margin: 8px 0; }
+.user::before { content: "❯ "; font-weight: bold; }
+.text { margin: 8px 0 8px 16px; }
+.text pre { background: var(--bg-alt); padding: 8px; overflow-x: auto; }
+details { margin: 6px 0 6px 16px; }
+summary { cursor: pointer; }
+details.tool > summary::before { content: "● "; color: var(--success); }
+details.tool.error > summary::before { color: var(--error); }
+details.tool > summary { font-weight: bold; }
+.param { color: var(--secondary); font-weight: normal; }
+details.thinking > summary { color: var(--thinking); font-style: italic; }
+details.thinking pre { color: var(--secondary); }
+.event { color: var(--primary); margin: 6px 0 6px 16px; }
+details.event > summary { color: var(--primary); }
+.event.error, details.event.error > summary { color: var(--error); }
+details.event pre { color: var(--secondary); }
+details.subagent { border-left: 2px solid var(--dim); padding-left: 12px; }
+details.subagent > summary { color: var(--accent); }
+pre { white-space: pre-wrap; word-break: break-word; margin: 4px 0 4px 16px; }
+pre.input { color: var(--secondary); }
+pre.result { color: var(--secondary); border-left: 1px solid var(--dim); padding-left: 8px; max-height: 480px; overflow-y: auto; }
+pre.result.error { color: var(--error); }
+.diff { margin: 4px 0 4px 16px; white-space: pre-wrap; word-break: break-word; }
+.diff > div { padding: 0 8px; }
+.diff .add { background: var(--add-bg); color: var(--diff-fg); }
+.diff .del { background: var(--del-bg); color: var(--diff-fg); }
+.diff .ctx { color: var(--diff-ctx); }
+.diff .sign { display: inline-block; width: 1.5em; user-select: none; }
+.duration { color: var(--dim); font-style: italic; margin: 8px 0 0 16px; }
+</style>
+</head>
+<body>
+<nav>
+<h1>{{.Title}}</h1>
+<div class="meta">{{.Session.CWD}}{{if .Session.GitBranch}} · {{.Session.GitBranch}}{{end}}<br>{{.Session.Model}}{{if not .Session.StartTime.IsZero}} · {{.Session.StartTime.Format "2006-01-02 15:04"}}{{end}}</div>
+<div class="controls"><button id="expand">Expand all</button> <button id="collapse">Collapse all</button></div>
+<ol>
+{{- range .Turns}}
+<li><a href="#turn-{{.Number}}">{{.Number}}. {{truncate .UserText 40}}{{if .Notes}} <span class="mark">✦</span>{{end}}</a></li>
+{{- end}}
+</ol>
+</nav>
+<main>
+{{- range .Turns}}
+<section class="turn" id="turn-{{.Number}}">
+<h2>Turn {{.Number}}{{if not .Timestamp.IsZero}} · {{.Timestamp.Format "Jan 02 15:04:05"}}{{end}}{{if .Model}} · {{.Model}}{{end}}</h2>
+{{- range .Notes}}
+<div class="note">✦ {{.}}</div>
+{{- end}}
+<div class="user">{{.UserText}}</div>
+{{.Body}}
+{{- if .Duration}}
+<div class="duration">* {{formatDuration .Duration}}</div>
+{{- end}}
+</section>
+{{- end}}
+</main>
+<script>
+(function () {
+  function setAll(open) {
+    document.querySelectorAll("main details").forEach(function (d) { d.open = open; });
+  }
+  document.getElementById("expand").onclick = function () { setAll(true); };
+  document.getElementById("collapse").onclick = function () { setAll(false); };
+
+  var links = {};
+  document.querySelectorAll("nav a").forEach(function (a) { links[a.getAttribute("href").slice(1)] = a; });
+  if (!("IntersectionObserver" in window)) return;
+  var observer = new IntersectionObserver(function (entries) {
+    entries.forEach(function (e) {
+      if (!e.isIntersecting) return;
+      document.querySelectorAll("nav a.active").forEach(function (a) { a.classList.remove("active"); });
+      var link = links[e.target.id];
+      if (link) { link.classList.add("active"); link.scrollIntoView({ block: "nearest" }); }
+    });
+  }, { rootMargin: "0px 0px -80% 0px" });
+  document.querySelectorAll("section.turn").forEach(function (s) { observer.observe(s); });
+})();
+</script>
+</body>
+</html>
+`
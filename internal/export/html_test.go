@@ -0,0 +1,70 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "test.html")
+
+	sess := &session.Session{
+		ID:        "test-session",
+		Slug:      "test-slug",
+		CWD:       "/test",
+		Model:     "claude-opus-4-6",
+		StartTime: time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC),
+		Turns: []session.Turn{
+			{
+				Number:   1,
+				UserText: "Fix the <bug>",
+				Blocks: []session.Block{
+					{Type: session.BlockThinking, Text: "Let me look"},
+					{Type: session.BlockText, Text: "Done with **bold**"},
+					{Type: session.BlockToolUse, ToolName: "Edit", ToolID: "t1", ToolInput: map[string]any{
+						"file_path":  "/test/main.go",
+						"old_string": "x := 1",
+						"new_string": "x := 2",
+					}},
+					{Type: session.BlockToolResult, ToolID: "t1", Text: "edited"},
+				},
+				Duration: 3 * time.Second,
+			},
+			{Number: 2, UserText: "Thanks"},
+		},
+	}
+
+	if err := GenerateHTML(sess, Options{Output: output}); err != nil {
+		t.Fatalf("GenerateHTML error: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		`href="#turn-1"`,
+		`id="turn-2"`,
+		"Fix the &lt;bug&gt;",
+		`<details class="thinking">`,
+		"<strong>bold</strong>",
+		`<details class="tool" open>`,
+		`<span class="param">(main.go)</span>`,
+		`<div class="del">`,
+		`<div class="add">`,
+		`<pre class="result">edited</pre>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<bug>") {
+		t.Error("user text was not escaped")
+	}
+}
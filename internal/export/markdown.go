@@ -0,0 +1,242 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/components"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+// markdownResultLines is how many lines of a tool result are kept when
+// full results are not requested.
+const markdownResultLines = 5
+
+// GenerateMarkdown writes a GitHub-flavored Markdown transcript of a session.
+func GenerateMarkdown(sess *session.Session, opts Options) error {
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := WriteMarkdown(f, sess, opts); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteMarkdown renders a session as Markdown suitable for pasting into docs
+// and pull requests. Thinking and untruncated tool results are included only
+// when opts.IncludeThinking and opts.FullToolResults are set.
+func WriteMarkdown(w io.Writer, sess *session.Session, opts Options) error {
+	var b strings.Builder
+
+	title := sess.Slug
+	if title == "" && len(sess.ID) > 8 {
+		title = sess.ID[:8]
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	var meta []string
+	if sess.CWD != "" {
+		meta = append(meta, "`"+sess.CWD+"`")
+	}
+	if sess.GitBranch != "" {
+		meta = append(meta, "branch `"+sess.GitBranch+"`")
+	}
+	if sess.Model != "" {
+		meta = append(meta, sess.Model)
+	}
+	if !sess.StartTime.IsZero() {
+		meta = append(meta, sess.StartTime.Format("2006-01-02 15:04"))
+	}
+	if len(meta) > 0 {
+		b.WriteString(strings.Join(meta, " · ") + "\n\n")
+	}
+
+	for _, turn := range sess.Turns {
+		fmt.Fprintf(&b, "## Turn %d\n\n", turn.Number)
+		for _, item := range opts.Annotations.ForTurn(turn.Number) {
+			fmt.Fprintf(&b, "**%s %s**\n\n", components.BookmarkSymbol, replay.AnnotationText(item))
+		}
+		writeMarkdownTurn(&b, turn, sess.CWD, opts)
+		if turn.Duration > 0 {
+			fmt.Fprintf(&b, "_%s_\n\n", replay.FormatDuration(turn.Duration))
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n"); err != nil {
+		return fmt.Errorf("writing Markdown: %w", err)
+	}
+	return nil
+}
+
+func writeMarkdownTurn(b *strings.Builder, turn session.Turn, cwd string, opts Options) {
+	if turn.UserText != "" {
+		b.WriteString(markdownQuote(turn.UserText) + "\n\n")
+	}
+
+	results := map[string]session.Block{}
+	for _, block := range turn.Blocks {
+		if block.Type == session.BlockToolResult {
+			results[block.ToolID] = block
+		}
+	}
+	readContents := readContentsFor(turn)
+
+	used := map[string]bool{}
+	for _, block := range turn.Blocks {
+		switch block.Type {
+		case session.BlockText:
+			b.WriteString(strings.TrimSpace(block.Text) + "\n\n")
+
+		case session.BlockThinking:
+			if !opts.IncludeThinking {
+				continue
+			}
+			b.WriteString("<details>\n<summary>Thinking</summary>\n\n")
+			b.WriteString(markdownQuote(block.Text) + "\n\n")
+			b.WriteString("</details>\n\n")
+
+		case session.BlockToolUse:
+			result, hasResult := results[block.ToolID]
+			used[block.ToolID] = hasResult
+			writeMarkdownToolUse(b, block, result, hasResult, cwd, readContents, opts)
+
+		case session.BlockToolResult:
+			if used[block.ToolID] {
+				continue
+			}
+			b.WriteString(markdownFence("", markdownResult(block, opts)) + "\n\n")
+
+		case session.BlockCompaction, session.BlockAPIError, session.BlockHook, session.BlockInterruption:
+			writeMarkdownEvent(b, block)
+		}
+	}
+}
+
+// writeMarkdownEvent writes a compaction, API error, hook or interruption
+// as an emphasized line, followed by its text. A compaction's summary is
+// already markdown and goes in a collapsed section.
+func writeMarkdownEvent(b *strings.Builder, block session.Block) {
+	fmt.Fprintf(b, "*%s %s*\n\n", components.EventSymbol(block.Type), replay.EventTitle(block))
+	text := strings.TrimSpace(block.Text)
+	if text == "" || block.Type == session.BlockInterruption {
+		return
+	}
+	if block.Type == session.BlockCompaction {
+		b.WriteString("<details>\n<summary>Summary</summary>\n\n" + text + "\n\n</details>\n\n")
+		return
+	}
+	b.WriteString(markdownFence("", text) + "\n\n")
+}
+
+// writeMarkdownToolUse writes a tool call as a fenced block headed by the
+// tool name and brief param, followed by its result and, for edits, a diff.
+func writeMarkdownToolUse(b *strings.Builder, block, result session.Block, hasResult bool, cwd string, readContents map[string]string, opts Options) {
+	call := replay.ToolDisplayName(block.ToolName)
+	if brief := replay.ToolBriefParam(block, cwd); brief != "" {
+		call += "(" + brief + ")"
+	}
+
+	var lines []string
+	lines = append(lines, call)
+	if block.ToolName == "Bash" {
+		if cmd, _ := block.ToolInput["command"].(string); strings.Contains(cmd, "\n") {
+			lines = append(lines, cmd)
+		}
+	}
+	if hasResult {
+		for _, line := range strings.Split(markdownResult(result, opts), "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	b.WriteString(markdownFence("", strings.Join(lines, "\n")) + "\n\n")
+
+	path, _ := block.ToolInput["file_path"].(string)
+	var ops []replay.DiffOp
+	switch block.ToolName {
+	case "Edit":
+		oldStr, _ := block.ToolInput["old_string"].(string)
+		newStr, _ := block.ToolInput["new_string"].(string)
+		ops = replay.ComputeDiff(oldStr, newStr)
+	case "Write":
+		content, _ := block.ToolInput["content"].(string)
+		ops = replay.ComputeDiff(readContents[path], content)
+	}
+	if len(ops) > 0 {
+		var diff strings.Builder
+		for i, op := range ops {
+			if i > 0 {
+				diff.WriteByte('\n')
+			}
+			diff.WriteByte(op.Kind)
+			diff.WriteString(op.Text)
+		}
+		b.WriteString(markdownFence("diff", diff.String()) + "\n\n")
+	}
+
+	if len(block.Sidechain) > 0 {
+		fmt.Fprintf(b, "<details>\n<summary>Subagent transcript (%d turns)</summary>\n\n", len(block.Sidechain))
+		for _, sub := range block.Sidechain {
+			writeMarkdownTurn(b, sub, cwd, opts)
+		}
+		b.WriteString("</details>\n\n")
+	}
+}
+
+// markdownResult returns a tool result's text, cut to markdownResultLines
+// unless full results were requested.
+func markdownResult(block session.Block, opts Options) string {
+	text := strings.TrimRight(block.Text, "\n")
+	if text == "" {
+		return "(No output)"
+	}
+	prefix := "⎿ "
+	if block.IsError {
+		prefix = "⎿ Error: "
+	}
+	if !opts.FullToolResults {
+		lines := strings.Split(text, "\n")
+		if len(lines) > markdownResultLines {
+			text = strings.Join(lines[:markdownResultLines], "\n") +
+				fmt.Sprintf("\n… +%d lines", len(lines)-markdownResultLines)
+		}
+	}
+	return prefix + text
+}
+
+// markdownQuote prefixes every line with "> ".
+func markdownQuote(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownFence wraps text in a code fence longer than any backtick run it
+// contains, so embedded fences cannot close the block early.
+func markdownFence(lang, text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + text + "\n" + fence
+}
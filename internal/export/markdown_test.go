@@ -0,0 +1,126 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+func markdownTestSession() *session.Session {
+	return &session.Session{
+		ID:   "test-session",
+		Slug: "test-slug",
+		CWD:  "/test",
+		Turns: []session.Turn{
+			{
+				Number:   1,
+				UserText: "Fix the bug\nplease",
+				Blocks: []session.Block{
+					{Type: session.BlockThinking, Text: "secret plan"},
+					{Type: session.BlockText, Text: "Running tests"},
+					{Type: session.BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]any{"command": "go test ./..."}},
+					{Type: session.BlockToolResult, ToolID: "t1", Text: "1\n2\n3\n4\n5\n6\n7"},
+					{Type: session.BlockToolUse, ToolName: "Edit", ToolID: "t2", ToolInput: map[string]any{
+						"file_path":  "/test/main.go",
+						"old_string": "x := 1",
+						"new_string": "x := 2",
+					}},
+					{Type: session.BlockToolResult, ToolID: "t2", Text: "ok ```"},
+				},
+			},
+		},
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMarkdown(&b, markdownTestSession(), Options{}); err != nil {
+		t.Fatalf("WriteMarkdown error: %v", err)
+	}
+	md := b.String()
+
+	for _, want := range []string{
+		"# test-slug",
+		"## Turn 1",
+		"> Fix the bug\n> please",
+		"Running tests",
+		"```\nBash(go test ./...)\n  ⎿ 1\n",
+		"… +2 lines",
+		"```diff\n-x := 1\n+x := 2\n```",
+		"````\nUpdate(main.go)\n  ⎿ ok ```\n````",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("output missing %q\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "secret plan") {
+		t.Error("thinking should be omitted by default")
+	}
+}
+
+func TestWriteMarkdown_ThinkingAndFullResults(t *testing.T) {
+	var b strings.Builder
+	opts := Options{IncludeThinking: true, FullToolResults: true}
+	if err := WriteMarkdown(&b, markdownTestSession(), opts); err != nil {
+		t.Fatalf("WriteMarkdown error: %v", err)
+	}
+	md := b.String()
+
+	if !strings.Contains(md, "> secret plan") {
+		t.Error("expected thinking to be included")
+	}
+	if !strings.Contains(md, "  7\n") || strings.Contains(md, "+2 lines") {
+		t.Error("expected untruncated tool result")
+	}
+}
+
+func TestWriteMarkdown_Events(t *testing.T) {
+	sess := markdownTestSession()
+	sess.Turns[0].Blocks = append(sess.Turns[0].Blocks,
+		session.Block{Type: session.BlockCompaction, Detail: "auto", Text: "Summary:\n- fixed the bug"},
+		session.Block{Type: session.BlockAPIError, Text: "Overloaded"},
+	)
+
+	var b strings.Builder
+	writeMarkdownTurn(&b, sess.Turns[0], sess.CWD, Options{})
+	got := b.String()
+	for _, want := range []string{
+		"*◆ Context compacted (auto)*",
+		"<summary>Summary</summary>\n\nSummary:\n- fixed the bug",
+		"*✕ API error*\n\n```\nOverloaded\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestExportAnnotations(t *testing.T) {
+	sess := markdownTestSession()
+	notes := &session.Annotations{SessionID: sess.ID}
+	notes.Set(session.Annotation{Turn: 1, Note: "agent ignored test failure here"})
+	notes.Set(session.Annotation{Turn: 1, Block: 3})
+	opts := Options{Annotations: notes, Width: 80, Height: 24}
+
+	var md strings.Builder
+	if err := WriteMarkdown(&md, sess, opts); err != nil {
+		t.Fatalf("WriteMarkdown error: %v", err)
+	}
+	if !strings.Contains(md.String(), "**✦ agent ignored test failure here**") || !strings.Contains(md.String(), "**✦ block 3 bookmarked**") {
+		t.Errorf("Markdown should include the annotations, got:\n%s", md.String())
+	}
+
+	var html strings.Builder
+	if err := WriteHTML(&html, sess, opts); err != nil {
+		t.Fatalf("WriteHTML error: %v", err)
+	}
+	if !strings.Contains(html.String(), `<div class="note">✦ agent ignored test failure here</div>`) || !strings.Contains(html.String(), `<span class="mark">✦</span>`) {
+		t.Error("HTML should include the annotations and mark the turn in the index")
+	}
+
+	frame := stripANSI(renderFrame(sess, notes, 0, -1, opts.Width, opts.Height))
+	if !strings.Contains(frame, "✦ agent ignored test failure here") {
+		t.Errorf("recording frames should show the annotations, got:\n%s", frame)
+	}
+}
@@ -1,6 +1,10 @@
 package export
 
-import "time"
+import (
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
 
 // TimingMode controls how timing is applied to exported frames.
 type TimingMode string
@@ -12,13 +16,32 @@ const (
 	TimingInstant    TimingMode = "instant"     // No delays
 )
 
+// Granularity controls what each frame of a recording shows.
+type Granularity string
+
+const (
+	GranularityTurn  Granularity = "turn"  // One frame per turn
+	GranularityBlock Granularity = "block" // One frame per block, revealed in turn
+)
+
 // Options configures the export.
 type Options struct {
 	TimingMode TimingMode
 	Width      int
 	Height     int
 	Output     string
-	Format     string // "cast", "gif", "mp4"
+	Format     string // "cast", "gif", "apng", "mp4", "html", "md"
+
+	// Recordings
+	Granularity Granularity // frames per turn or per block (default: turn)
+	Animate     bool        // type prompts, stream text and spin while tools run
+
+	// Markdown export
+	IncludeThinking bool // include thinking blocks
+	FullToolResults bool // include tool results untruncated
+
+	// Bookmarks and notes shown with their turns, in every format
+	Annotations *session.Annotations
 }
 
 // DefaultOptions returns sensible defaults.
@@ -28,6 +51,29 @@ func DefaultOptions() Options {
 		Width:      120,
 		Height:     40,
 		Format:     "cast",
+
+		Granularity: GranularityTurn,
+	}
+}
+
+// StepDelay calculates the delay before revealing the next block of a turn,
+// given the time between the records holding the blocks.
+func (o Options) StepDelay(realDuration time.Duration) time.Duration {
+	switch o.TimingMode {
+	case TimingRealtime:
+		if realDuration > 0 {
+			return realDuration
+		}
+		return 200 * time.Millisecond
+	case TimingFast:
+		if realDuration > 0 {
+			return realDuration / 2
+		}
+		return 100 * time.Millisecond
+	case TimingInstant:
+		return 50 * time.Millisecond
+	default:
+		return 400 * time.Millisecond
 	}
 }
 
@@ -0,0 +1,254 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+// patchContext is the number of unchanged lines around each hunk, as in
+// git diff.
+const patchContext = 3
+
+// PatchOptions selects what a patch covers.
+type PatchOptions struct {
+	From, To int    // turn range, inclusive; 0 means the first/last turn
+	Root     string // directory paths are relative to (default: session CWD)
+}
+
+// Patch is a unified diff of the files a session changed.
+type Patch struct {
+	Files    []FilePatch
+	Warnings []string // changes that could not be folded in exactly
+}
+
+// FilePatch is the diff of one file.
+type FilePatch struct {
+	Path  string // relative to the patch root
+	New   bool   // the file was created in the range
+	Hunks []Hunk
+
+	// Approximate is set when the file's content before the range is
+	// unknown: hunks are built from the edited text alone, with
+	// placeholder line numbers and usually no context, so git apply needs
+	// --unidiff-zero to place them.
+	Approximate bool
+}
+
+// Hunk is one @@ section of a file diff. Line texts include their
+// newline; a missing one marks the end of a file without a final newline.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []replay.DiffOp
+}
+
+// BuildPatch folds every Edit and Write in a turn range into one patch,
+// using earlier Read results as the baseline. A Write to a file never read
+// in the session creates it (Claude Code refuses to overwrite files it has
+// not read).
+func BuildPatch(sess *session.Session, opts PatchOptions) (*Patch, error) {
+	from, to := opts.From, opts.To
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		to = len(sess.Turns)
+	}
+	if from < 1 || to > len(sess.Turns) || from > to {
+		return nil, fmt.Errorf("invalid turn range %d-%d (session has %d turns)", from, to, len(sess.Turns))
+	}
+	root := opts.Root
+	if root == "" {
+		root = sess.CWD
+	}
+
+	p := &Patch{}
+	for _, h := range session.FileHistories(sess) {
+		first, last := -1, -1
+		for i, c := range h.Changes {
+			if c.Turn < from || c.Turn > to || c.Op == session.FileRead || c.IsError {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+		if first < 0 {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, h.Path)
+		if err != nil || !filepath.IsLocal(rel) {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("%s: outside %s, skipped", h.Path, root))
+			continue
+		}
+		fp := FilePatch{Path: filepath.ToSlash(rel)}
+
+		start, end := h.Changes[first], h.Changes[last]
+		base, baseKnown := start.Before, start.BeforeKnown
+		if first == 0 && start.Op == session.FileWrite {
+			base, baseKnown, fp.New = "", true, true
+		}
+
+		if baseKnown && end.AfterKnown {
+			if base == end.After {
+				continue
+			}
+			fp.Hunks = unifiedHunks(base, end.After, patchContext)
+		} else {
+			fp.Approximate = true
+			fp.Hunks = p.editHunks(h, first, last)
+			if len(fp.Hunks) == 0 {
+				continue
+			}
+		}
+		p.Files = append(p.Files, fp)
+	}
+	return p, nil
+}
+
+// editHunks builds hunks from the Edit calls of changes[first:last+1],
+// warning about what they cannot express.
+func (p *Patch) editHunks(h session.FileHistory, first, last int) []Hunk {
+	var hunks []Hunk
+	var turns []string
+	for _, c := range h.Changes[first : last+1] {
+		if c.Op == session.FileRead || c.IsError {
+			continue
+		}
+		turns = append(turns, fmt.Sprint(c.Turn))
+		if c.Op == session.FileWrite || len(c.Edits) == 0 {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("%s: %s in turn %d replaces content whose baseline is unknown, skipped", h.Path, c.Tool, c.Turn))
+			continue
+		}
+		for _, e := range c.Edits {
+			if e.ReplaceAll {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("%s: replace_all edit in turn %d is included once", h.Path, c.Turn))
+			}
+			hunks = append(hunks, unifiedHunks(wholeLines(e.Old), wholeLines(e.New), len(e.Old)+len(e.New))...)
+		}
+	}
+	if len(hunks) > 0 {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("%s: baseline unknown (no complete Read before turn %s); hunks come from the edited text only and need git apply --unidiff-zero, which may still fail", h.Path, turns[0]))
+	}
+	return hunks
+}
+
+// wholeLines ends s with a newline, as edits usually cover whole lines.
+func wholeLines(s string) string {
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		return s + "\n"
+	}
+	return s
+}
+
+// unifiedHunks diffs two texts into hunks with the given context.
+func unifiedHunks(oldStr, newStr string, context int) []Hunk {
+	ops := replay.DiffLines(splitAfterLines(oldStr), splitAfterLines(newStr))
+
+	// Each op's position in the old and new file, 0-based
+	oldPos := make([]int, len(ops))
+	newPos := make([]int, len(ops))
+	o, n := 0, 0
+	for i, op := range ops {
+		oldPos[i], newPos[i] = o, n
+		if op.Kind != '+' {
+			o++
+		}
+		if op.Kind != '-' {
+			n++
+		}
+	}
+
+	var hunks []Hunk
+	for i := 0; i < len(ops); {
+		if ops[i].Kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are close enough to share context
+		start := max(0, i-context)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].Kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(len(ops), end+context)
+
+		h := Hunk{OldStart: oldPos[start] + 1, NewStart: newPos[start] + 1, Lines: ops[start:end]}
+		for _, op := range h.Lines {
+			if op.Kind != '+' {
+				h.OldLines++
+			}
+			if op.Kind != '-' {
+				h.NewLines++
+			}
+		}
+		// An empty side starts at the line before the hunk
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
+
+// splitAfterLines splits text into lines that keep their newline.
+func splitAfterLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// WriteTo writes the patch in git's unified diff format.
+func (p *Patch) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range p.Files {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", f.Path, f.Path)
+		if f.New {
+			b.WriteString("new file mode 100644\n")
+			b.WriteString("--- /dev/null\n")
+		} else {
+			fmt.Fprintf(&b, "--- a/%s\n", f.Path)
+		}
+		fmt.Fprintf(&b, "+++ b/%s\n", f.Path)
+
+		for _, h := range f.Hunks {
+			fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+			for _, op := range h.Lines {
+				b.WriteByte(op.Kind)
+				b.WriteString(op.Text)
+				if !strings.HasSuffix(op.Text, "\n") {
+					b.WriteString("\n\\ No newline at end of file\n")
+				}
+			}
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
@@ -0,0 +1,202 @@
+package export
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+)
+
+func patchTestSession() *session.Session {
+	tool := func(id, name string, input map[string]any) session.Block {
+		return session.Block{Type: session.BlockToolUse, ToolName: name, ToolID: id, ToolInput: input}
+	}
+	ok := func(id, text string) session.Block {
+		return session.Block{Type: session.BlockToolResult, ToolID: id, Text: text}
+	}
+	return &session.Session{
+		CWD: "/repo",
+		Turns: []session.Turn{
+			{Number: 1, Blocks: []session.Block{
+				tool("r1", "Read", map[string]any{"file_path": "/repo/main.go"}),
+				ok("r1", "     1→package main\n     2→\n     3→func main() {\n     4→\tprintln(1)\n     5→}\n"),
+				tool("e1", "Edit", map[string]any{"file_path": "/repo/main.go", "old_string": "\tprintln(1)", "new_string": "\tprintln(2)"}),
+				ok("e1", "ok"),
+			}},
+			{Number: 2, Blocks: []session.Block{
+				tool("w1", "Write", map[string]any{"file_path": "/repo/docs/new.md", "content": "# New\nno newline"}),
+				ok("w1", "ok"),
+				tool("e2", "Edit", map[string]any{"file_path": "/repo/unread.go", "old_string": "old line", "new_string": "new line"}),
+				ok("e2", "ok"),
+			}},
+			{Number: 3, Blocks: []session.Block{
+				tool("e3", "Edit", map[string]any{"file_path": "/repo/main.go", "old_string": "}", "new_string": "}\n\nfunc f() {}"}),
+				ok("e3", "ok"),
+				tool("w2", "Write", map[string]any{"file_path": "/tmp/outside.txt", "content": "x"}),
+				ok("w2", "ok"),
+			}},
+		},
+	}
+}
+
+func TestBuildPatch(t *testing.T) {
+	p, err := BuildPatch(patchTestSession(), PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	p.WriteTo(&b)
+
+	want := `diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++no newline
+\ No newline at end of file
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,5 +1,7 @@
+ package main
+ 
+ func main() {
+-	println(1)
++	println(2)
+ }
++
++func f() {}
+diff --git a/unread.go b/unread.go
+--- a/unread.go
++++ b/unread.go
+@@ -1 +1 @@
+-old line
++new line
+`
+	if b.String() != want {
+		t.Errorf("patch:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	warnings := strings.Join(p.Warnings, "\n")
+	if !strings.Contains(warnings, "/repo/unread.go: baseline unknown") {
+		t.Errorf("expected unknown-baseline warning, got:\n%s", warnings)
+	}
+	if !strings.Contains(warnings, "/tmp/outside.txt: outside /repo") {
+		t.Errorf("expected outside-root warning, got:\n%s", warnings)
+	}
+}
+
+func TestBuildPatch_TurnRange(t *testing.T) {
+	p, err := BuildPatch(patchTestSession(), PatchOptions{From: 3, To: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Files) != 1 || p.Files[0].Path != "main.go" {
+		t.Fatalf("files = %+v", p.Files)
+	}
+	// The baseline is the content after turn 1's edit
+	h := p.Files[0].Hunks[0]
+	if h.OldStart != 3 || h.Lines[0].Text != "func main() {\n" || h.Lines[1].Text != "\tprintln(2)\n" {
+		t.Errorf("hunk = %+v", h)
+	}
+
+	if _, err := BuildPatch(patchTestSession(), PatchOptions{From: 2, To: 9}); err == nil {
+		t.Error("expected an error for a range past the last turn")
+	}
+}
+
+func TestBuildPatch_NoFinalNewline(t *testing.T) {
+	sess := &session.Session{CWD: "/repo", Turns: []session.Turn{{Number: 1, Blocks: []session.Block{
+		{Type: session.BlockToolUse, ToolName: "Read", ToolID: "r1", ToolInput: map[string]any{"file_path": "/repo/notes.txt"}},
+		{Type: session.BlockToolResult, ToolID: "r1", Text: "     1→one\n     2→two"},
+		{Type: session.BlockToolUse, ToolName: "Edit", ToolID: "e1", ToolInput: map[string]any{"file_path": "/repo/notes.txt", "old_string": "one", "new_string": "uno"}},
+		{Type: session.BlockToolResult, ToolID: "e1", Text: "ok"},
+	}}}}
+	p, err := BuildPatch(sess, PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	p.WriteTo(&b)
+
+	want := `diff --git a/notes.txt b/notes.txt
+--- a/notes.txt
++++ b/notes.txt
+@@ -1,2 +1,2 @@
+-one
++uno
+ two
+\ No newline at end of file
+`
+	if b.String() != want {
+		t.Errorf("patch:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestUnifiedHunks_SplitsDistantChanges(t *testing.T) {
+	var oldText, newText strings.Builder
+	for i := 0; i < 20; i++ {
+		line := string(rune('a'+i)) + "\n"
+		oldText.WriteString(line)
+		if i == 1 || i == 18 {
+			line = "X\n"
+		}
+		newText.WriteString(line)
+	}
+	hunks := unifiedHunks(oldText.String(), newText.String(), 3)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2", len(hunks))
+	}
+	if hunks[0].OldStart != 1 || hunks[0].OldLines != 5 || hunks[1].OldStart != 16 || hunks[1].OldLines != 5 {
+		t.Errorf("hunks = %+v / %+v", hunks[0], hunks[1])
+	}
+}
+
+func TestBuildPatch_GitApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	sess := patchTestSession()
+	sess.CWD = dir
+	for i := range sess.Turns {
+		for j, b := range sess.Turns[i].Blocks {
+			if p, ok := b.ToolInput["file_path"].(string); ok && strings.HasPrefix(p, "/repo/") {
+				sess.Turns[i].Blocks[j].ToolInput["file_path"] = filepath.Join(dir, strings.TrimPrefix(p, "/repo/"))
+			}
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(1)\n}\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "unread.go"), []byte("package x\n\nold line\n"), 0o644)
+
+	p, err := BuildPatch(sess, PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patchPath := filepath.Join(t.TempDir(), "session.patch")
+	f, _ := os.Create(patchPath)
+	p.WriteTo(f)
+	f.Close()
+
+	cmd := exec.Command("git", "apply", "--unidiff-zero", patchPath)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply: %v\n%s", err, out)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if string(got) != "package main\n\nfunc main() {\n\tprintln(2)\n}\n\nfunc f() {}\n" {
+		t.Errorf("main.go after apply:\n%s", got)
+	}
+	got, _ = os.ReadFile(filepath.Join(dir, "unread.go"))
+	if string(got) != "package x\n\nnew line\n" {
+		t.Errorf("unread.go after apply:\n%s", got)
+	}
+	got, _ = os.ReadFile(filepath.Join(dir, "docs/new.md"))
+	if string(got) != "# New\nno newline" {
+		t.Errorf("new.md after apply: %q", got)
+	}
+}
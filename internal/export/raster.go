@@ -0,0 +1,443 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// fontSize is the pixel size of the font rasterized frames are drawn with.
+const fontSize = 14
+
+// cell is one character cell of a rendered screen.
+type cell struct {
+	r         rune // 0 for the right half of a wide character
+	fg, bg    color.RGBA
+	bold      bool
+	underline bool
+}
+
+// screen is a frame laid out on a terminal grid, as a terminal showing it
+// would: rows past the bottom are dropped, columns past the right edge cut.
+type screen struct {
+	cols, rows int
+	cells      []cell // row-major
+}
+
+// at returns the cell at column x of row y.
+func (s *screen) at(x, y int) *cell {
+	return &s.cells[y*s.cols+x]
+}
+
+// sgrState is the graphic rendition set by the escape sequences read so far.
+type sgrState struct {
+	fg, bg                 color.RGBA
+	bold, faint, underline bool
+	reverse                bool
+}
+
+// cellColors returns the colors a character drawn with st gets.
+func (st sgrState) cellColors() (fg, bg color.RGBA) {
+	fg, bg = st.fg, st.bg
+	if st.reverse {
+		fg, bg = bg, fg
+	}
+	if st.faint {
+		fg = blend(bg, fg, 128)
+	}
+	return fg, bg
+}
+
+// parseScreen lays out frame, lines of text with ANSI escape sequences, on a
+// cols×rows grid. SGR colors (16, 256 and true colors), bold, faint,
+// underline and reverse are kept; other sequences are skipped.
+func parseScreen(frame string, cols, rows int) *screen {
+	def := sgrState{fg: defaultFg, bg: defaultBg}
+	s := &screen{cols: cols, rows: rows, cells: make([]cell, cols*rows)}
+	for i := range s.cells {
+		s.cells[i] = cell{r: ' ', fg: def.fg, bg: def.bg}
+	}
+
+	for y, line := range strings.Split(frame, "\n") {
+		if y >= rows {
+			break
+		}
+		st := def
+		x := 0
+		for i := 0; i < len(line); {
+			if line[i] == '\033' {
+				i += parseEscape(line[i:], &st, def)
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+
+			if r == '\t' {
+				for next := (x/8 + 1) * 8; x < next && x < cols; x++ {
+					fg, bg := st.cellColors()
+					*s.at(x, y) = cell{r: ' ', fg: fg, bg: bg}
+				}
+				continue
+			}
+			w := runewidth.RuneWidth(r)
+			if w == 0 || x+w > cols {
+				continue
+			}
+			fg, bg := st.cellColors()
+			*s.at(x, y) = cell{r: r, fg: fg, bg: bg, bold: st.bold, underline: st.underline}
+			if w == 2 {
+				*s.at(x+1, y) = cell{fg: fg, bg: bg}
+			}
+			x += w
+		}
+	}
+	return s
+}
+
+// parseEscape applies the escape sequence at the start of s to st and
+// returns its length. Only SGR sequences change st; OSC sequences such as
+// hyperlinks are skipped up to their terminator.
+func parseEscape(s string, st *sgrState, def sgrState) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		end := 2
+		for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+			end++
+		}
+		if end == len(s) {
+			return end
+		}
+		if s[end] == 'm' {
+			applySGR(s[2:end], st, def)
+		}
+		return end + 1
+	case ']':
+		for end := 2; end < len(s); end++ {
+			if s[end] == '\a' {
+				return end + 1
+			}
+			if s[end] == '\033' && end+1 < len(s) && s[end+1] == '\\' {
+				return end + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
+
+// applySGR applies the semicolon-separated parameters of an SGR sequence.
+func applySGR(params string, st *sgrState, def sgrState) {
+	var codes []int
+	for _, p := range strings.Split(params, ";") {
+		n, _ := strconv.Atoi(p) // empty parameters mean 0
+		codes = append(codes, n)
+	}
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			*st = def
+		case c == 1:
+			st.bold = true
+		case c == 2:
+			st.faint = true
+		case c == 4:
+			st.underline = true
+		case c == 7:
+			st.reverse = true
+		case c == 22:
+			st.bold, st.faint = false, false
+		case c == 24:
+			st.underline = false
+		case c == 27:
+			st.reverse = false
+		case c >= 30 && c <= 37:
+			st.fg = ansiColor(c - 30)
+		case c >= 90 && c <= 97:
+			st.fg = ansiColor(c - 90 + 8)
+		case c >= 40 && c <= 47:
+			st.bg = ansiColor(c - 40)
+		case c >= 100 && c <= 107:
+			st.bg = ansiColor(c - 100 + 8)
+		case c == 39:
+			st.fg = def.fg
+		case c == 49:
+			st.bg = def.bg
+		case c == 38 || c == 48:
+			col, n := extendedColor(codes[i+1:])
+			i += n
+			if n == 0 {
+				continue
+			}
+			if c == 38 {
+				st.fg = col
+			} else {
+				st.bg = col
+			}
+		}
+	}
+}
+
+// extendedColor reads the "5;n" or "2;r;g;b" parameters following 38 or 48
+// and returns the color and how many parameters it used.
+func extendedColor(codes []int) (color.RGBA, int) {
+	switch {
+	case len(codes) >= 2 && codes[0] == 5:
+		return xtermColor(codes[1]), 2
+	case len(codes) >= 4 && codes[0] == 2:
+		return color.RGBA{uint8(codes[1]), uint8(codes[2]), uint8(codes[3]), 0xff}, 4
+	}
+	return color.RGBA{}, 0
+}
+
+// ansiPalette holds the 16 basic terminal colors.
+var ansiPalette = [16]color.RGBA{
+	{0x15, 0x16, 0x1e, 0xff}, {0xf7, 0x76, 0x8e, 0xff}, {0x9e, 0xce, 0x6a, 0xff}, {0xe0, 0xaf, 0x68, 0xff},
+	{0x7a, 0xa2, 0xf7, 0xff}, {0xbb, 0x9a, 0xf7, 0xff}, {0x7d, 0xcf, 0xff, 0xff}, {0xa9, 0xb1, 0xd6, 0xff},
+	{0x41, 0x48, 0x68, 0xff}, {0xf7, 0x76, 0x8e, 0xff}, {0x9e, 0xce, 0x6a, 0xff}, {0xe0, 0xaf, 0x68, 0xff},
+	{0x7a, 0xa2, 0xf7, 0xff}, {0xbb, 0x9a, 0xf7, 0xff}, {0x7d, 0xcf, 0xff, 0xff}, {0xc0, 0xca, 0xf5, 0xff},
+}
+
+// ansiColor returns basic color n (0-15).
+func ansiColor(n int) color.RGBA {
+	return ansiPalette[n&15]
+}
+
+// xtermColor returns color n of the xterm 256-color palette.
+func xtermColor(n int) color.RGBA {
+	switch {
+	case n < 16:
+		return ansiColor(n)
+	case n < 232:
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + 40*v)
+		}
+		return color.RGBA{level(n / 36), level(n / 6 % 6), level(n % 6), 0xff}
+	case n < 256:
+		v := uint8(8 + 10*(n-232))
+		return color.RGBA{v, v, v, 0xff}
+	}
+	return defaultFg
+}
+
+// Text and background colors of cells without SGR colors.
+var (
+	defaultFg = themeColor(theme.ColorText)
+	defaultBg = themeColor(theme.ColorBg)
+)
+
+// themeColor converts a "#RRGGBB" theme color.
+func themeColor(c lipgloss.Color) color.RGBA {
+	var r, g, b uint8
+	fmt.Sscanf(string(c), "#%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{r, g, b, 0xff}
+}
+
+// blend mixes b over a with the given alpha (0-255).
+func blend(a, b color.RGBA, alpha uint8) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8((int(x)*(255-int(alpha)) + int(y)*int(alpha) + 127) / 255)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+// glyphFallbacks replaces characters Go Mono has no glyph for with the
+// closest one it has.
+var glyphFallbacks = map[rune]rune{
+	'⎿': '└', '⤷': '└', '╭': '┌', '╮': '┐', '╰': '└', '╯': '┘',
+	'▶': '►', '▸': '►', '◀': '◄', '◆': '♦', '❯': '›',
+	'✕': '×', '✗': '×', '⋯': '…', '⇄': '↔', '⊘': 'ø', '⑂': 'Y', '⚑': '►',
+	'✢': '*', '✳': '*', '✶': '*', '✻': '*', '✽': '*', '★': '*', '✦': '*',
+	'▇': '█', '▏': '▌', '▎': '▌',
+}
+
+// glyphKey identifies a cached glyph mask.
+type glyphKey struct {
+	r    rune
+	bold bool
+}
+
+// rasterizer draws screens with the bundled Go Mono font.
+type rasterizer struct {
+	regular, bold font.Face
+	cellW, cellH  int
+	ascent        int
+	glyphs        map[glyphKey]*image.Alpha
+}
+
+// newRasterizer loads the font at fontSize pixels.
+func newRasterizer() (*rasterizer, error) {
+	opts := &opentype.FaceOptions{Size: fontSize, DPI: 72, Hinting: font.HintingFull}
+	var faces [2]font.Face
+	for i, data := range [][]byte{gomono.TTF, gomonobold.TTF} {
+		f, err := opentype.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing font: %w", err)
+		}
+		if faces[i], err = opentype.NewFace(f, opts); err != nil {
+			return nil, fmt.Errorf("loading font: %w", err)
+		}
+	}
+
+	metrics := faces[0].Metrics()
+	advance, _ := faces[0].GlyphAdvance('M')
+	return &rasterizer{
+		regular: faces[0],
+		bold:    faces[1],
+		cellW:   advance.Ceil(),
+		cellH:   metrics.Height.Ceil(),
+		ascent:  metrics.Ascent.Ceil(),
+		glyphs:  map[glyphKey]*image.Alpha{},
+	}, nil
+}
+
+// size returns the pixel size of a cols×rows screen.
+func (r *rasterizer) size(cols, rows int) image.Rectangle {
+	return image.Rect(0, 0, cols*r.cellW, rows*r.cellH)
+}
+
+// drawCells draws the cells of s in the cell rectangle area onto dst.
+func (r *rasterizer) drawCells(dst *image.RGBA, s *screen, area image.Rectangle) {
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			r.drawCell(dst, s, x, y)
+		}
+	}
+}
+
+// drawCell paints the background of cell (x, y) and its glyph, which may
+// spill into the next cell for wide characters.
+func (r *rasterizer) drawCell(dst *image.RGBA, s *screen, x, y int) {
+	c := s.at(x, y)
+	px, py := x*r.cellW, y*r.cellH
+	fill(dst, image.Rect(px, py, px+r.cellW, py+r.cellH), c.bg)
+	if c.r == 0 {
+		// The glyph of a wide character was drawn by its left half; draw
+		// it again over the background just painted
+		if x > 0 {
+			left := s.at(x-1, y)
+			r.drawGlyph(dst, left, px-r.cellW, py, image.Rect(px, py, px+r.cellW, py+r.cellH))
+		}
+		return
+	}
+	width := r.cellW
+	if x+1 < s.cols && s.at(x+1, y).r == 0 {
+		width *= 2
+	}
+	r.drawGlyph(dst, c, px, py, image.Rect(px, py, px+width, py+r.cellH))
+	if c.underline {
+		fill(dst, image.Rect(px, py+r.cellH-2, px+width, py+r.cellH-1), c.fg)
+	}
+}
+
+// drawGlyph draws c's glyph for the cell at (px, py), clipped to clip.
+func (r *rasterizer) drawGlyph(dst *image.RGBA, c *cell, px, py int, clip image.Rectangle) {
+	if c.r == ' ' {
+		return
+	}
+	mask := r.glyph(c.r, c.bold)
+	clip = clip.Intersect(mask.Bounds().Add(image.Pt(px, py))).Intersect(dst.Bounds())
+	for y := clip.Min.Y; y < clip.Max.Y; y++ {
+		for x := clip.Min.X; x < clip.Max.X; x++ {
+			a := mask.AlphaAt(x-px, y-py).A
+			if a == 0 {
+				continue
+			}
+			i := dst.PixOffset(x, y)
+			bg := color.RGBA{dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], 0xff}
+			out := blend(bg, c.fg, a)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = out.R, out.G, out.B, 0xff
+		}
+	}
+}
+
+// glyph returns the coverage mask of a character, relative to the top left
+// of its cell. Coverage is rounded to four levels so frames use few colors
+// and fit GIF palettes.
+func (r *rasterizer) glyph(ch rune, bold bool) *image.Alpha {
+	key := glyphKey{ch, bold}
+	if mask, ok := r.glyphs[key]; ok {
+		return mask
+	}
+
+	face := r.regular
+	if bold {
+		face = r.bold
+	}
+	if fallback, ok := glyphFallbacks[ch]; ok {
+		ch = fallback
+	}
+
+	mask := image.NewAlpha(image.Rect(0, 0, 2*r.cellW, r.cellH))
+	if _, ok := face.GlyphAdvance(ch); ok {
+		d := font.Drawer{Dst: mask, Src: image.Opaque, Face: face, Dot: fixed.P(0, r.ascent)}
+		d.DrawString(string(ch))
+		for i, a := range mask.Pix {
+			mask.Pix[i] = uint8((int(a) + 42) / 85 * 85)
+		}
+	} else {
+		// Missing glyph: an outlined box
+		w := r.cellW * runewidth.RuneWidth(ch)
+		box := image.Rect(1, 2, w-1, r.cellH-2)
+		for x := box.Min.X; x < box.Max.X; x++ {
+			mask.SetAlpha(x, box.Min.Y, color.Alpha{0xff})
+			mask.SetAlpha(x, box.Max.Y-1, color.Alpha{0xff})
+		}
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			mask.SetAlpha(box.Min.X, y, color.Alpha{0xff})
+			mask.SetAlpha(box.Max.X-1, y, color.Alpha{0xff})
+		}
+	}
+	r.glyphs[key] = mask
+	return mask
+}
+
+// fill paints rect with c.
+func fill(dst *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(dst.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		i := dst.PixOffset(rect.Min.X, y)
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = c.R, c.G, c.B, 0xff
+			i += 4
+		}
+	}
+}
+
+// changedCells returns the smallest cell rectangle holding every cell that
+// differs between prev and next, which have the same size.
+func changedCells(prev, next *screen) image.Rectangle {
+	var changed image.Rectangle
+	for y := 0; y < next.rows; y++ {
+		for x := 0; x < next.cols; x++ {
+			if *prev.at(x, y) != *next.at(x, y) {
+				changed = changed.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	// Wide characters spill into their right neighbor; redraw it with them
+	if !changed.Empty() {
+		changed.Min.X = max(changed.Min.X-1, 0)
+		changed.Max.X = min(changed.Max.X+1, next.cols)
+	}
+	return changed
+}
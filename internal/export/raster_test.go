@@ -0,0 +1,119 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParseScreen(t *testing.T) {
+	frame := "\x1b[1;38;2;255;0;0mred\x1b[0m plain\n" +
+		"\x1b[48;5;196mX\x1b[49m中\tY\n" +
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\ cut off at the edge\n" +
+		"dropped"
+	s := parseScreen(frame, 12, 3)
+
+	red := s.at(0, 0)
+	if red.r != 'r' || !red.bold || red.fg != (color.RGBA{255, 0, 0, 255}) || red.bg != defaultBg {
+		t.Errorf("cell (0,0) = %+v, want bold red 'r'", *red)
+	}
+	if plain := s.at(4, 0); plain.r != 'p' || plain.bold || plain.fg != defaultFg {
+		t.Errorf("cell (4,0) = %+v, want plain 'p' after reset", *plain)
+	}
+
+	if x := s.at(0, 1); x.bg != xtermColor(196) || x.bg != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("cell (0,1) background = %v, want 256-color red", x.bg)
+	}
+	if wide, right := s.at(1, 1), s.at(2, 1); wide.r != '中' || right.r != 0 || wide.bg != defaultBg {
+		t.Errorf("wide character cells = %q %q, want '中' and a continuation", wide.r, right.r)
+	}
+	if y := s.at(8, 1); y.r != 'Y' {
+		t.Errorf("cell (8,1) = %q, want 'Y' after the tab stop", y.r)
+	}
+
+	if got := rowText(s, 2); got != "link cut off" {
+		t.Errorf("row 2 = %q, want the hyperlink text cut at the edge", got)
+	}
+	if s.rows != 3 || len(s.cells) != 36 {
+		t.Errorf("screen is %d rows, %d cells; want 3 rows, 36 cells", s.rows, len(s.cells))
+	}
+}
+
+func TestApplySGR_ReverseAndFaint(t *testing.T) {
+	def := sgrState{fg: defaultFg, bg: defaultBg}
+	st := def
+	applySGR("7", &st, def)
+	if fg, bg := st.cellColors(); fg != defaultBg || bg != defaultFg {
+		t.Errorf("reverse colors = %v on %v, want swapped defaults", fg, bg)
+	}
+	applySGR("27;2", &st, def)
+	if fg, _ := st.cellColors(); fg == defaultFg || fg == defaultBg {
+		t.Errorf("faint color = %v, want between text and background", fg)
+	}
+	applySGR("22", &st, def)
+	if fg, _ := st.cellColors(); fg != defaultFg {
+		t.Errorf("color after 22 = %v, want default", fg)
+	}
+}
+
+func TestChangedCells(t *testing.T) {
+	prev := parseScreen("header\nsame\nold", 10, 4)
+	next := parseScreen("header\nsame\nnew!", 10, 4)
+	if got, want := changedCells(prev, next), image.Rect(0, 2, 5, 3); got != want {
+		t.Errorf("changedCells = %v, want %v", got, want)
+	}
+	if got := changedCells(prev, prev); !got.Empty() {
+		t.Errorf("changedCells of identical screens = %v, want empty", got)
+	}
+}
+
+func TestRasterizer_DrawsCells(t *testing.T) {
+	r, err := newRasterizer()
+	if err != nil {
+		t.Fatalf("newRasterizer: %v", err)
+	}
+	if r.cellW <= 0 || r.cellH <= r.cellW {
+		t.Fatalf("cell size = %dx%d, want taller than wide", r.cellW, r.cellH)
+	}
+
+	s := parseScreen("\x1b[38;2;255;255;255;48;2;0;0;255mW⎿\x1b[0m", 4, 1)
+	img := image.NewRGBA(r.size(4, 1))
+	r.drawCells(img, s, image.Rect(0, 0, 4, 1))
+
+	// The first cell has a blue background and white ink, the last the
+	// default background only
+	colors := map[color.RGBA]bool{}
+	for y := 0; y < r.cellH; y++ {
+		for x := 0; x < r.cellW; x++ {
+			colors[img.RGBAAt(x, y)] = true
+		}
+	}
+	if !colors[color.RGBA{0, 0, 255, 255}] || !colors[color.RGBA{255, 255, 255, 255}] {
+		t.Errorf("first cell colors = %v, want blue background and white glyph", colors)
+	}
+	if got := img.RGBAAt(3*r.cellW+1, 1); got != defaultBg {
+		t.Errorf("last cell = %v, want default background", got)
+	}
+
+	// Missing glyphs fall back to similar ones, not boxes
+	if _, ok := r.regular.GlyphAdvance('⎿'); ok {
+		t.Skip("font has '⎿'")
+	}
+	if mask := r.glyph('⎿', false); mask.AlphaAt(r.cellW/2, 1).A != 0 && mask.AlphaAt(1, 2).A != 0 {
+		t.Errorf("'⎿' is drawn as a missing-glyph box")
+	}
+}
+
+// rowText returns the characters of row y, trailing spaces trimmed.
+func rowText(s *screen, y int) string {
+	var text []rune
+	for x := 0; x < s.cols; x++ {
+		if r := s.at(x, y).r; r != 0 {
+			text = append(text, r)
+		}
+	}
+	for len(text) > 0 && text[len(text)-1] == ' ' {
+		text = text[:len(text)-1]
+	}
+	return string(text)
+}
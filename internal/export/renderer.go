@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/components"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
@@ -11,45 +12,86 @@ import (
 
 // RenderFrame renders a complete TUI frame for a given turn as a string.
 func RenderFrame(sess *session.Session, turnIndex int, width, height int) string {
+	return renderFrame(sess, nil, turnIndex, -1, width, height)
+}
+
+// RenderStepFrame renders the frame of a turn with only its first visible
+// blocks revealed, scrolled down to the newest one.
+func RenderStepFrame(sess *session.Session, turnIndex, visible int, width, height int) string {
+	return renderFrame(sess, nil, turnIndex, visible, width, height)
+}
+
+// renderFrame renders a turn whole (visible < 0) from the top, or its first
+// visible blocks from the bottom, with its annotations from notes.
+func renderFrame(sess *session.Session, notes *session.Annotations, turnIndex, visible int, width, height int) string {
 	if turnIndex < 0 || turnIndex >= len(sess.Turns) {
 		return ""
 	}
+	turn := replay.PartialTurn(sess.Turns[turnIndex], visible)
+	return renderTurnFrame(sess, notes, turnIndex, turn, "", visible >= 0, width, height)
+}
 
-	turn := sess.Turns[turnIndex]
+// renderTurnFrame renders turn, a possibly altered copy of turn turnIndex,
+// followed by footer lines. When tail is set and the content does not fit,
+// its end is shown rather than its start.
+func renderTurnFrame(sess *session.Session, notes *session.Annotations, turnIndex int, turn session.Turn, footer string, tail bool, width, height int) string {
+	content := replay.RenderTurn(turn, false, width, sess.CWD)
+	if footer != "" {
+		content += "\n" + footer
+	}
+	return composeFrame(sess, notes, turnIndex, turn, content, tail, width, height)
+}
 
+// frameChrome returns the header of a frame and the number of lines the
+// header, timeline and status bar take.
+func frameChrome(sess *session.Session, width int) (string, int) {
 	slug := sess.Slug
 	if slug == "" && len(sess.ID) > 8 {
 		slug = sess.ID[:8]
 	}
-
-	// Header
 	header := components.RenderHeader(slug, sess.CWD, sess.GitBranch, width)
+	statusLines := 2
+	return header, strings.Count(header, "\n") + 1 + statusLines
+}
 
-	// Content
-	content := replay.RenderTurn(turn, false, width, sess.CWD)
+// composeFrame lays out rendered content between the header and the
+// timeline and status bar of turn turnIndex, padded or cut to height. The
+// turn's annotations from notes are shown above the content.
+func composeFrame(sess *session.Session, notes *session.Annotations, turnIndex int, turn session.Turn, content string, tail bool, width, height int) string {
+	header, chromeLines := frameChrome(sess, width)
+	if banner := replay.RenderAnnotations(notes.ForTurn(turn.Number), width); banner != "" {
+		content = banner + "\n\n" + content
+	}
 
 	// Ensure content fills available space
 	contentLines := strings.Split(content, "\n")
-	headerLines := strings.Count(header, "\n") + 1
-	statusLines := 2
-	availableLines := height - headerLines - statusLines
+	availableLines := height - chromeLines
 	if len(contentLines) < availableLines {
 		for i := len(contentLines); i < availableLines; i++ {
 			contentLines = append(contentLines, "")
 		}
 	} else if len(contentLines) > availableLines {
-		contentLines = contentLines[:availableLines]
+		if tail {
+			contentLines = contentLines[len(contentLines)-availableLines:]
+		} else {
+			contentLines = contentLines[:availableLines]
+		}
 	}
 	content = strings.Join(contentLines, "\n")
 
+	// Lines wider than the terminal would wrap and push the rows below
+	// out of place
+	content = lipgloss.NewStyle().MaxWidth(width).Render(content)
+
 	// Timeline + Status
-	timeline := components.RenderTimeline(turnIndex+1, len(sess.Turns), width)
+	timeline := components.RenderTimeline(turnIndex+1, len(sess.Turns), width, components.TurnEvents(sess.Turns), notes.Turns())
 	status := components.RenderStatusBar(
 		turnIndex+1,
 		len(sess.Turns),
 		turn.Model,
 		turn.Duration,
 		turn.Timestamp,
+		turn.TotalUsage(),
 		width,
 	)
 
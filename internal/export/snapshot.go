@@ -0,0 +1,170 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
+)
+
+// Cell geometry of SVG snapshots, in pixels. Viewers pick their own
+// monospace font, so text runs are stretched to their cells.
+const (
+	svgCellWidth  = 0.6 * fontSize
+	svgCellHeight = 1.2 * fontSize
+	svgBaseline   = 0.95 * fontSize
+)
+
+// RenderSnapshot renders turns first to last (0-indexed) below each other
+// as a still frame, with the timeline and status bar of the last one. When
+// expanded is set, tool results and thinking are shown in full. With a
+// height of 0, the frame is cropped to the height of its content.
+func RenderSnapshot(sess *session.Session, first, last int, expanded bool, width, height int) string {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
+
+	var parts []string
+	for i := first; i <= last; i++ {
+		parts = append(parts, replay.RenderTurn(sess.Turns[i], expanded, width, sess.CWD))
+	}
+	content := strings.Join(parts, "\n")
+
+	if height <= 0 {
+		_, chromeLines := frameChrome(sess, width)
+		height = strings.Count(content, "\n") + 1 + chromeLines
+	}
+	return composeFrame(sess, nil, last, sess.Turns[last], content, false, width, height)
+}
+
+// SaveSnapshot writes frame, a screen width cells wide, as an SVG or PNG
+// image depending on the extension of path.
+func SaveSnapshot(path, frame string, width int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		err = WritePNG(f, frame, width)
+	} else {
+		err = WriteSVG(f, frame, width)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WritePNG rasterizes frame, a screen width cells wide with one row per
+// line, with the same font as GIF recordings.
+func WritePNG(w io.Writer, frame string, width int) error {
+	r, err := newRasterizer()
+	if err != nil {
+		return err
+	}
+	rows := strings.Count(frame, "\n") + 1
+	s := parseScreen(frame, width, rows)
+	img := image.NewRGBA(r.size(width, rows))
+	r.drawCells(img, s, image.Rect(0, 0, width, rows))
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("encoding PNG: %w", err)
+	}
+	return nil
+}
+
+// WriteSVG writes frame, a screen width cells wide with one row per line,
+// as an SVG image keeping the true colors of its escape sequences. Text
+// stays selectable.
+func WriteSVG(w io.Writer, frame string, width int) error {
+	rows := strings.Count(frame, "\n") + 1
+	s := parseScreen(frame, width, rows)
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]g" height="%[2]g" viewBox="0 0 %[1]g %[2]g">`+"\n",
+		float64(width)*svgCellWidth, float64(rows)*svgCellHeight)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(defaultBg))
+
+	// Backgrounds, one rectangle per run of cells of the same color
+	bw.WriteString(`<g shape-rendering="crispEdges">` + "\n")
+	for y := 0; y < rows; y++ {
+		for x := 0; x < width; {
+			bg := s.at(x, y).bg
+			end := x + 1
+			for end < width && s.at(end, y).bg == bg {
+				end++
+			}
+			if bg != defaultBg {
+				fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`+"\n",
+					float64(x)*svgCellWidth, float64(y)*svgCellHeight, float64(end-x)*svgCellWidth, svgCellHeight, hexColor(bg))
+			}
+			x = end
+		}
+	}
+	bw.WriteString("</g>\n")
+
+	// Text, one element per run of cells of the same style
+	fmt.Fprintf(bw, `<g font-family="'SF Mono', Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" xml:space="preserve">`+"\n", fontSize)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < width; {
+			start := s.at(x, y)
+			end := x + 1
+			for end < width && sameTextStyle(s.at(end, y), start) {
+				end++
+			}
+			writeSVGText(bw, s, x, end, y)
+			x = end
+		}
+	}
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+// sameTextStyle reports whether c is drawn like run, the first cell of a
+// text run. The halves of wide characters join any run.
+func sameTextStyle(c, run *cell) bool {
+	return c.r == 0 || (c.fg == run.fg && c.bold == run.bold && c.underline == run.underline)
+}
+
+// writeSVGText writes the text of cells x to end of row y, unless blank.
+func writeSVGText(w io.Writer, s *screen, x, end, y int) {
+	var text strings.Builder
+	for i := x; i < end; i++ {
+		if r := s.at(i, y).r; r != 0 {
+			text.WriteRune(r)
+		}
+	}
+	trimmed := strings.TrimRight(text.String(), " ")
+	if trimmed == "" {
+		return
+	}
+	cells := end - x - (len([]rune(text.String())) - len([]rune(trimmed)))
+
+	c := s.at(x, y)
+	fmt.Fprintf(w, `<text x="%g" y="%g" fill="%s" textLength="%g" lengthAdjust="spacingAndGlyphs"`,
+		float64(x)*svgCellWidth, float64(y)*svgCellHeight+svgBaseline, hexColor(c.fg), float64(cells)*svgCellWidth)
+	if c.bold {
+		io.WriteString(w, ` font-weight="bold"`)
+	}
+	if c.underline {
+		io.WriteString(w, ` text-decoration="underline"`)
+	}
+	io.WriteString(w, ">")
+	xml.EscapeText(w, []byte(trimmed))
+	io.WriteString(w, "</text>\n")
+}
+
+// hexColor formats c as #rrggbb.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderSnapshot_Crop(t *testing.T) {
+	sess := imageTestSession()
+
+	full := RenderSnapshot(sess, 0, 0, false, 60, 30)
+	if n := strings.Count(full, "\n") + 1; n != 30 {
+		t.Errorf("snapshot has %d lines, want --height 30", n)
+	}
+
+	cropped := RenderSnapshot(sess, 0, 0, false, 60, 0)
+	n := strings.Count(cropped, "\n") + 1
+	if n >= 30 || n < 5 {
+		t.Errorf("cropped snapshot has %d lines, want the content only", n)
+	}
+	if text := stripANSI(cropped); !strings.Contains(text, "Hello") || !strings.Contains(text, "Hi there!") {
+		t.Errorf("cropped snapshot misses the turn:\n%s", text)
+	}
+
+	both := stripANSI(RenderSnapshot(sess, 0, 1, false, 60, 0))
+	if !strings.Contains(both, "Hi there!") || !strings.Contains(both, "List the files") || !strings.Contains(both, "Turn 2/2") {
+		t.Errorf("range snapshot should show both turns and the last one's status:\n%s", both)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	frame := "\x1b[1;38;2;212;165;116mclaude\x1b[0m <b> & \x1b[48;2;34;90;52m+ added\x1b[0m\nplain 中文"
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, frame, 30); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	svg := buf.String()
+
+	// Well-formed XML, with the text runs escaped
+	var texts []string
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			inText = tok.Name.Local == "text"
+		case xml.CharData:
+			if inText {
+				texts = append(texts, string(tok))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+	if got := strings.Join(texts, "|"); got != "claude| <b> & + added|plain 中文" {
+		t.Errorf("text runs = %q", got)
+	}
+
+	for _, want := range []string{`fill="#d4a574"`, `font-weight="bold"`, `fill="#225a34"`, `width="252"`, `height="33.6"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestSaveSnapshot_PNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "turn.png")
+	if err := SaveSnapshot(path, "one\ntwo\nthree", 20); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	r, _ := newRasterizer()
+	if got, want := img.Bounds(), r.size(20, 3); got != want {
+		t.Errorf("PNG covers %v, want %v", got, want)
+	}
+}
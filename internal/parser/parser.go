@@ -10,6 +10,7 @@ import (
 
 // ParseFile reads a JSONL session file and returns all records.
 // Progress records and file-history-snapshot records are filtered out.
+// Sidechain (subagent) records are kept; callers check IsSidechain.
 func ParseFile(path string) ([]Record, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -26,28 +27,9 @@ func Parse(r io.Reader) ([]Record, error) {
 	scanner.Buffer(make([]byte, 0, 4*1024*1024), 16*1024*1024) // up to 16MB per line
 
 	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var rec Record
-		if err := json.Unmarshal(line, &rec); err != nil {
-			continue // skip malformed lines
-		}
-
-		// Filter out noise records
-		switch rec.Type {
-		case RecordTypeProgress, RecordTypeSnapshot:
-			continue
-		}
-
-		// Skip sidechain records
-		if rec.IsSidechain {
-			continue
+		if rec, ok := parseLine(scanner.Bytes()); ok {
+			records = append(records, rec)
 		}
-
-		records = append(records, rec)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -57,6 +39,25 @@ func Parse(r io.Reader) ([]Record, error) {
 	return records, nil
 }
 
+// parseLine decodes one JSONL line, reporting false for empty, malformed
+// and noise (progress, snapshot) lines.
+func parseLine(line []byte) (Record, bool) {
+	var rec Record
+	if len(bytes.TrimSpace(line)) == 0 {
+		return rec, false
+	}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return rec, false // skip malformed lines
+	}
+
+	// Filter out noise records
+	switch rec.Type {
+	case RecordTypeProgress, RecordTypeSnapshot:
+		return rec, false
+	}
+	return rec, true
+}
+
 // QuickScan reads just enough of a session file to extract metadata
 // without parsing the entire file. Returns slug, model, first timestamp,
 // last timestamp, and approximate turn count.
@@ -76,6 +77,8 @@ func QuickScan(path string) (slug, model string, firstTime, lastTime string, tur
 		Timestamp string `json:"timestamp"`
 		Subtype   string `json:"subtype"`
 		IsMeta    bool   `json:"isMeta"`
+		Summary   bool   `json:"isCompactSummary"`
+		Sidechain bool   `json:"isSidechain"`
 		Message   *struct {
 			Role    string          `json:"role"`
 			Model   string          `json:"model"`
@@ -94,6 +97,11 @@ func QuickScan(path string) (slug, model string, firstTime, lastTime string, tur
 			continue
 		}
 
+		// Subagent records don't count towards the session's own turns
+		if rec.Sidechain {
+			continue
+		}
+
 		if rec.Timestamp != "" {
 			if firstTime == "" {
 				firstTime = rec.Timestamp
@@ -106,10 +114,17 @@ func QuickScan(path string) (slug, model string, firstTime, lastTime string, tur
 		}
 
 		if rec.Type == "user" && rec.Message != nil && rec.Message.Role == "user" {
-			// Skip meta messages (expanded skill prompts)
-			if rec.IsMeta {
+			// Skip meta messages (expanded skill prompts), compaction
+			// summaries and interruption markers
+			if rec.IsMeta || rec.Summary {
 				continue
 			}
+			if bytes.Contains(rec.Message.Content, []byte("[Request interrupted by user")) {
+				msg := UserMessage{Content: rec.Message.Content}
+				if msg.IsInterruption() {
+					continue
+				}
+			}
 			if len(rec.Message.Content) > 0 {
 				switch rec.Message.Content[0] {
 				case '"':
@@ -78,7 +78,7 @@ func TestParse_FiltersProgressAndSnapshot(t *testing.T) {
 	}
 }
 
-func TestParse_FiltersSidechain(t *testing.T) {
+func TestParse_KeepsSidechain(t *testing.T) {
 	input := `{"type":"user","parentUuid":null,"uuid":"a","sessionId":"s","timestamp":"2026-02-13T12:18:22.000Z","message":{"role":"user","content":"main"},"isSidechain":false}
 {"type":"user","parentUuid":null,"uuid":"b","sessionId":"s","timestamp":"2026-02-13T12:18:22.000Z","message":{"role":"user","content":"side"},"isSidechain":true}`
 
@@ -86,8 +86,11 @@ func TestParse_FiltersSidechain(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(records) != 1 {
-		t.Fatalf("expected 1 record after sidechain filter, got %d", len(records))
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].IsSidechain || !records[1].IsSidechain {
+		t.Errorf("IsSidechain flags: got %v, %v", records[0].IsSidechain, records[1].IsSidechain)
 	}
 }
 
@@ -502,6 +505,25 @@ func TestQuickScan_SkipsMetaMessages(t *testing.T) {
 	}
 }
 
+func TestQuickScan_SkipsInterruptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "interrupted.jsonl")
+
+	content := `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"isSidechain":false}
+{"type":"user","parentUuid":"u1","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"role":"user","content":[{"type":"text","text":"[Request interrupted by user]"}]},"isSidechain":false}
+{"type":"user","parentUuid":"u2","uuid":"u3","sessionId":"s1","timestamp":"2026-02-13T12:01:00.000Z","message":{"role":"user","content":"why did it print [Request interrupted by user] here?"},"isSidechain":false}
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	_, _, _, _, turnCount, err := QuickScan(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if turnCount != 2 {
+		t.Errorf("turnCount: got %d, want 2 (only the marker itself should be skipped)", turnCount)
+	}
+}
+
 func TestQuickScan_SkipsBashOutput(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "bash-output.jsonl")
@@ -617,3 +639,28 @@ func TestQuickScan_MalformedLines(t *testing.T) {
 		t.Errorf("turnCount: got %d, want 1", turnCount)
 	}
 }
+
+func TestQuickScan_SkipsSidechain(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sidechain.jsonl")
+
+	content := `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"isSidechain":false}
+{"type":"user","parentUuid":null,"uuid":"sc1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"role":"user","content":"Search the codebase"},"isSidechain":true}
+{"type":"assistant","parentUuid":"sc1","uuid":"sc2","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","message":{"model":"claude-haiku-4-5","id":"msg_1","role":"assistant","content":[{"type":"text","text":"found it"}]},"isSidechain":true}
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	_, model, _, lastTime, turnCount, err := QuickScan(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if turnCount != 1 {
+		t.Errorf("turnCount: got %d, want 1 (sidechain prompts should not count as turns)", turnCount)
+	}
+	if model != "" {
+		t.Errorf("model: got %q, want empty (subagent model should be ignored)", model)
+	}
+	if lastTime != "2026-02-13T12:00:00.000Z" {
+		t.Errorf("lastTime: got %q", lastTime)
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"encoding/json"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -15,6 +16,7 @@ const (
 	RecordTypeSystem   RecordType = "system"
 	RecordTypeProgress RecordType = "progress"
 	RecordTypeSnapshot RecordType = "file-history-snapshot"
+	RecordTypeAttachment RecordType = "attachment"
 )
 
 // Record is a single line from a Claude Code JSONL session file.
@@ -34,9 +36,30 @@ type Record struct {
 	// User fields
 	Message json.RawMessage `json:"message"`
 
+	// Compaction summary (user record following a compact_boundary)
+	IsCompactSummary bool `json:"isCompactSummary"`
+
+	// Assistant message synthesized from a failed API request
+	IsAPIErrorMessage bool `json:"isApiErrorMessage"`
+
 	// System fields
-	Subtype    string  `json:"subtype"`
-	DurationMs float64 `json:"durationMs"`
+	Subtype         string           `json:"subtype"`
+	DurationMs      float64          `json:"durationMs"`
+	Level           string           `json:"level"`
+	Content         json.RawMessage  `json:"content"` // message text
+	CompactMetadata *CompactMetadata `json:"compactMetadata"`
+	Error           json.RawMessage  `json:"error"` // api_error: error object
+	RetryAttempt    int              `json:"retryAttempt"`
+	MaxRetries      int              `json:"maxRetries"`
+
+	// Stop hook summary (system stop_hook_summary records)
+	HookCount             int             `json:"hookCount"`
+	HookErrors            json.RawMessage `json:"hookErrors"`
+	PreventedContinuation bool            `json:"preventedContinuation"`
+	StopReason            string          `json:"stopReason"`
+
+	// Attachment records (hook output, file references, reminders)
+	Attachment json.RawMessage `json:"attachment"`
 
 	// Thinking metadata (on user records)
 	ThinkingMetadata *ThinkingMetadata `json:"thinkingMetadata"`
@@ -46,6 +69,24 @@ type ThinkingMetadata struct {
 	MaxThinkingTokens int `json:"maxThinkingTokens"`
 }
 
+// CompactMetadata describes a context compaction.
+type CompactMetadata struct {
+	Trigger   string `json:"trigger"`   // "auto" or "manual"
+	PreTokens int    `json:"preTokens"` // context size before compacting
+}
+
+// Attachment is the parsed form of an attachment record's attachment field.
+// Only hook attachments are used; their fields vary with the hook outcome.
+type Attachment struct {
+	Type          string          `json:"type"` // e.g. "hook_success", "hook_blocking_error"
+	HookName      string          `json:"hookName"`
+	HookEvent     string          `json:"hookEvent"`
+	Content       json.RawMessage `json:"content"`
+	Stdout        string          `json:"stdout"`
+	Stderr        string          `json:"stderr"`
+	BlockingError json.RawMessage `json:"blockingError"`
+}
+
 // UserMessage is the parsed form of a user record's message field.
 type UserMessage struct {
 	Role    string          `json:"role"`
@@ -114,6 +155,24 @@ func (r *Record) ParseAssistantMessage() (*AssistantMessage, error) {
 	return &msg, nil
 }
 
+// ParseAttachment extracts the Attachment from an attachment record.
+func (r *Record) ParseAttachment() (*Attachment, error) {
+	var a Attachment
+	if err := json.Unmarshal(r.Attachment, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SystemText returns the text content of a system record.
+func (r *Record) SystemText() string {
+	var text string
+	if err := json.Unmarshal(r.Content, &text); err != nil {
+		return ""
+	}
+	return text
+}
+
 // UserText returns the user's text content. If the message content is a plain
 // string it returns that; otherwise it tries to extract text from an array of
 // content blocks (e.g. text+image messages).
@@ -167,6 +226,12 @@ func (msg *UserMessage) ParseToolResults() ([]ToolResult, error) {
 	return results, nil
 }
 
+// IsInterruption returns true if the message is the marker Claude Code
+// writes when the user interrupts a response or a tool call.
+func (msg *UserMessage) IsInterruption() bool {
+	return strings.HasPrefix(msg.UserText(), "[Request interrupted by user")
+}
+
 var commandNameRe = regexp.MustCompile(`<command-name>(/[^<]+)</command-name>`)
 
 // CommandName extracts a slash command name from a command message.
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Tailer reads records appended to a JSONL file that is still being
+// written. A trailing line without a newline is held back until it is
+// complete, so records are never parsed from a half-written line.
+type Tailer struct {
+	path    string
+	offset  int64  // bytes consumed, including partial
+	partial []byte // incomplete last line
+}
+
+// NewTailer creates a tailer that reads path from the beginning.
+func NewTailer(path string) *Tailer {
+	return &Tailer{path: path}
+}
+
+// Next returns the records completed since the previous call.
+func (t *Tailer) Next() ([]Record, error) {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < t.offset {
+		return nil, fmt.Errorf("%s was truncated", t.path)
+	}
+	if fi.Size() == t.offset {
+		return nil, nil
+	}
+
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, fi.Size()-t.offset))
+	if err != nil {
+		return nil, err
+	}
+	t.offset += int64(len(data))
+
+	data = append(t.partial, data...)
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		t.partial = data
+		return nil, nil
+	}
+	t.partial = append([]byte(nil), data[end+1:]...)
+
+	var records []Record
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		if rec, ok := parseLine(line); ok {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTailer_HoldsBackPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.jsonl")
+	first := `{"type":"user","uuid":"u1","message":{"role":"user","content":"hello"}}`
+	second := `{"type":"assistant","uuid":"a1","message":{"role":"assistant","content":[]}}`
+
+	os.WriteFile(path, []byte(first+"\n"+second[:20]), 0644)
+	tailer := NewTailer(path)
+
+	records, err := tailer.Next()
+	if err != nil {
+		t.Fatalf("Next error: %v", err)
+	}
+	if len(records) != 1 || records[0].UUID != "u1" {
+		t.Fatalf("expected only the complete record, got %+v", records)
+	}
+
+	// Nothing new
+	if records, _ := tailer.Next(); len(records) != 0 {
+		t.Fatalf("expected no records, got %d", len(records))
+	}
+
+	// Finish the line
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(second[20:] + "\n")
+	f.Close()
+
+	records, err = tailer.Next()
+	if err != nil {
+		t.Fatalf("Next error: %v", err)
+	}
+	if len(records) != 1 || records[0].UUID != "a1" {
+		t.Fatalf("expected completed record, got %+v", records)
+	}
+}
+
+func TestTailer_Truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.jsonl")
+	os.WriteFile(path, []byte(`{"type":"user","uuid":"u1"}`+"\n"), 0644)
+
+	tailer := NewTailer(path)
+	tailer.Next()
+
+	os.WriteFile(path, nil, 0644)
+	if _, err := tailer.Next(); err == nil {
+		t.Error("expected error for truncated file")
+	}
+}
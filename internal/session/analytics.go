@@ -0,0 +1,238 @@
+package session
+
+import (
+	"sort"
+	"time"
+)
+
+// Analytics aggregates activity across sessions.
+type Analytics struct {
+	Sessions        int            `json:"sessions"`
+	Turns           int            `json:"turns"`
+	TotalDuration   time.Duration  `json:"total_duration_ns"`
+	TurnsPerSession TurnStats      `json:"turns_per_session"`
+	SessionsPerDay  []DayCount     `json:"sessions_per_day"`
+	Models          []ModelCount   `json:"models"`
+	Tools           []ToolStats    `json:"tools"`
+	Projects        []ProjectStats `json:"projects"`
+	Usage           UsageTotals    `json:"usage"`
+}
+
+// TurnStats summarizes the number of turns per session.
+type TurnStats struct {
+	Min    int     `json:"min"`
+	Median int     `json:"median"`
+	Mean   float64 `json:"mean"`
+	Max    int     `json:"max"`
+}
+
+// DayCount is the number of sessions started on a day (YYYY-MM-DD, local time).
+type DayCount struct {
+	Date     string `json:"date"`
+	Sessions int    `json:"sessions"`
+}
+
+// ModelCount is the number of turns answered by a model.
+type ModelCount struct {
+	Model string `json:"model"`
+	Turns int    `json:"turns"`
+}
+
+// ToolStats counts calls and failed results of a tool, including calls
+// made by subagents.
+type ToolStats struct {
+	Name      string  `json:"name"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// ProjectStats is the activity of one project.
+type ProjectStats struct {
+	Name     string        `json:"name"`
+	Sessions int           `json:"sessions"`
+	Turns    int           `json:"turns"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// UsageTotals is the token usage of all sessions with a cost estimate.
+type UsageTotals struct {
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	Cost                float64 `json:"estimated_cost_usd"`
+	CostComplete        bool    `json:"cost_complete"` // false if some models have no price
+}
+
+// Analyze loads every session of src and aggregates them. Sessions that
+// fail to load are skipped.
+func Analyze(src SessionSource, prices PriceTable) (*Analytics, error) {
+	projects, err := src.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	b := newAnalyticsBuilder(prices)
+	for _, p := range projects {
+		sessions, err := src.ListSessions(p.DirPath)
+		if err != nil {
+			continue
+		}
+		for _, info := range sessions {
+			sess, err := loadSessionInfo(src, info)
+			if err != nil {
+				continue
+			}
+			b.addSession(p.Name, sess)
+		}
+	}
+	return b.finish(), nil
+}
+
+// analyticsBuilder accumulates sessions into Analytics.
+type analyticsBuilder struct {
+	prices        PriceTable
+	a             Analytics
+	turnCounts    []int
+	days          map[string]int
+	models        map[string]int
+	tools         map[string]*ToolStats
+	projects      map[string]*ProjectStats
+	projectsOrder []string
+	usage         Usage
+}
+
+func newAnalyticsBuilder(prices PriceTable) *analyticsBuilder {
+	return &analyticsBuilder{
+		prices:   prices,
+		a:        Analytics{Usage: UsageTotals{CostComplete: true}},
+		days:     map[string]int{},
+		models:   map[string]int{},
+		tools:    map[string]*ToolStats{},
+		projects: map[string]*ProjectStats{},
+	}
+}
+
+func (b *analyticsBuilder) addSession(project string, sess *Session) {
+	if len(sess.Turns) == 0 {
+		return
+	}
+	b.a.Sessions++
+	b.a.Turns += len(sess.Turns)
+	b.turnCounts = append(b.turnCounts, len(sess.Turns))
+
+	if !sess.StartTime.IsZero() {
+		b.days[sess.StartTime.Local().Format("2006-01-02")]++
+	}
+
+	ps, ok := b.projects[project]
+	if !ok {
+		ps = &ProjectStats{Name: project}
+		b.projects[project] = ps
+		b.projectsOrder = append(b.projectsOrder, project)
+	}
+	ps.Sessions++
+	ps.Turns += len(sess.Turns)
+
+	for _, t := range sess.Turns {
+		b.a.TotalDuration += t.Duration
+		ps.Duration += t.Duration
+		if t.Model != "" {
+			b.models[t.Model]++
+		}
+		b.addTools(t)
+	}
+
+	b.usage = b.usage.Add(sess.TotalUsage())
+	if b.prices != nil {
+		cost, ok := b.prices.SessionCost(sess)
+		b.a.Usage.Cost += cost
+		b.a.Usage.CostComplete = b.a.Usage.CostComplete && ok
+	}
+}
+
+// addTools counts the tool calls of a turn and its subagents.
+func (b *analyticsBuilder) addTools(t Turn) {
+	names := map[string]string{}
+	for _, block := range t.Blocks {
+		switch block.Type {
+		case BlockToolUse:
+			names[block.ToolID] = block.ToolName
+			b.tool(block.ToolName).Calls++
+			for _, sub := range block.Sidechain {
+				b.addTools(sub)
+			}
+		case BlockToolResult:
+			if name, ok := names[block.ToolID]; ok && block.IsError {
+				b.tool(name).Errors++
+			}
+		}
+	}
+}
+
+func (b *analyticsBuilder) tool(name string) *ToolStats {
+	ts, ok := b.tools[name]
+	if !ok {
+		ts = &ToolStats{Name: name}
+		b.tools[name] = ts
+	}
+	return ts
+}
+
+func (b *analyticsBuilder) finish() *Analytics {
+	a := b.a
+
+	if n := len(b.turnCounts); n > 0 {
+		sort.Ints(b.turnCounts)
+		a.TurnsPerSession = TurnStats{
+			Min:    b.turnCounts[0],
+			Median: b.turnCounts[n/2],
+			Mean:   float64(a.Turns) / float64(n),
+			Max:    b.turnCounts[n-1],
+		}
+	}
+
+	for date, n := range b.days {
+		a.SessionsPerDay = append(a.SessionsPerDay, DayCount{Date: date, Sessions: n})
+	}
+	sort.Slice(a.SessionsPerDay, func(i, j int) bool {
+		return a.SessionsPerDay[i].Date < a.SessionsPerDay[j].Date
+	})
+
+	for model, n := range b.models {
+		a.Models = append(a.Models, ModelCount{Model: model, Turns: n})
+	}
+	sort.Slice(a.Models, func(i, j int) bool {
+		if a.Models[i].Turns != a.Models[j].Turns {
+			return a.Models[i].Turns > a.Models[j].Turns
+		}
+		return a.Models[i].Model < a.Models[j].Model
+	})
+
+	for _, ts := range b.tools {
+		if ts.Calls > 0 {
+			ts.ErrorRate = float64(ts.Errors) / float64(ts.Calls)
+		}
+		a.Tools = append(a.Tools, *ts)
+	}
+	sort.Slice(a.Tools, func(i, j int) bool {
+		if a.Tools[i].Calls != a.Tools[j].Calls {
+			return a.Tools[i].Calls > a.Tools[j].Calls
+		}
+		return a.Tools[i].Name < a.Tools[j].Name
+	})
+
+	for _, name := range b.projectsOrder {
+		a.Projects = append(a.Projects, *b.projects[name])
+	}
+	sort.SliceStable(a.Projects, func(i, j int) bool {
+		return a.Projects[i].Turns > a.Projects[j].Turns
+	})
+
+	a.Usage.InputTokens = b.usage.InputTokens
+	a.Usage.OutputTokens = b.usage.OutputTokens
+	a.Usage.CacheCreationTokens = b.usage.CacheCreationTokens
+	a.Usage.CacheReadTokens = b.usage.CacheReadTokens
+	return &a
+}
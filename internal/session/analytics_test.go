@@ -0,0 +1,66 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAnalyticsBuilder_Aggregates(t *testing.T) {
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.Local)
+	b := newAnalyticsBuilder(PriceTable{"opus": {Input: 1_000_000}})
+
+	sess := searchTestSession()
+	sess.StartTime = start
+	for i := range sess.Turns {
+		sess.Turns[i].Model = "claude-opus-4-6"
+		sess.Turns[i].Duration = time.Second
+		sess.Turns[i].Usage = Usage{InputTokens: 1}
+	}
+	b.addSession("proj-a", sess)
+	b.addSession("proj-b", &Session{
+		StartTime: start.Add(24 * time.Hour),
+		Turns:     []Turn{{Number: 1, Model: "claude-haiku-4-5", Duration: 3 * time.Second, Usage: Usage{OutputTokens: 1}}},
+	})
+	b.addSession("proj-b", &Session{}) // no turns: ignored
+
+	a := b.finish()
+
+	if a.Sessions != 2 || a.Turns != 3 || a.TotalDuration != 5*time.Second {
+		t.Errorf("totals: %d sessions, %d turns, %v", a.Sessions, a.Turns, a.TotalDuration)
+	}
+	if a.TurnsPerSession.Min != 1 || a.TurnsPerSession.Max != 2 || a.TurnsPerSession.Mean != 1.5 {
+		t.Errorf("turns per session: %+v", a.TurnsPerSession)
+	}
+	if len(a.SessionsPerDay) != 2 || a.SessionsPerDay[0].Date != "2026-02-13" {
+		t.Errorf("sessions per day: %+v", a.SessionsPerDay)
+	}
+	if len(a.Models) != 2 || a.Models[0].Model != "claude-opus-4-6" || a.Models[0].Turns != 2 {
+		t.Errorf("models: %+v", a.Models)
+	}
+	if len(a.Tools) != 2 || a.Tools[0].Name != "Bash" || a.Tools[0].Calls != 2 || a.Tools[0].Errors != 1 || a.Tools[0].ErrorRate != 0.5 {
+		t.Errorf("tools: %+v", a.Tools)
+	}
+	if a.Projects[0].Name != "proj-a" || a.Projects[0].Turns != 2 || a.Projects[1].Duration != 3*time.Second {
+		t.Errorf("projects: %+v", a.Projects)
+	}
+	if a.Usage.InputTokens != 2 || a.Usage.OutputTokens != 1 || a.Usage.Cost != 2 || a.Usage.CostComplete {
+		t.Errorf("usage: %+v (haiku has no price, so cost is incomplete)", a.Usage)
+	}
+}
+
+func TestAnalyze_LocalSource(t *testing.T) {
+	dir := t.TempDir()
+	projectsDir := filepath.Join(dir, "projects", "-Users-test-proj")
+	os.MkdirAll(projectsDir, 0755)
+	os.WriteFile(filepath.Join(projectsDir, "abcd-1234.jsonl"), []byte(indexTestSession), 0644)
+
+	a, err := Analyze(&LocalSource{ClaudeDir: dir}, DefaultPrices())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Sessions != 1 || a.Turns != 1 || len(a.Projects) != 1 || a.Projects[0].Name != "proj" {
+		t.Errorf("unexpected analytics: %+v", a)
+	}
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Annotation is a bookmark on a turn, or on one block of it, with an
+// optional note.
+type Annotation struct {
+	Turn    int       `json:"turn"`            // 1-based turn number
+	Block   int       `json:"block,omitempty"` // 1-based block index, 0 for the whole turn
+	Note    string    `json:"note,omitempty"`  // empty for a plain bookmark
+	Created time.Time `json:"created"`
+}
+
+// Annotations are the annotations of one session, ordered by turn and block.
+type Annotations struct {
+	SessionID string       `json:"session_id"`
+	Items     []Annotation `json:"annotations"`
+}
+
+// ForTurn returns the annotations of a turn, whole-turn ones first.
+func (a *Annotations) ForTurn(turn int) []Annotation {
+	if a == nil {
+		return nil
+	}
+	var items []Annotation
+	for _, item := range a.Items {
+		if item.Turn == turn {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Turns returns the numbers of the annotated turns.
+func (a *Annotations) Turns() map[int]bool {
+	turns := map[int]bool{}
+	if a == nil {
+		return turns
+	}
+	for _, item := range a.Items {
+		turns[item.Turn] = true
+	}
+	return turns
+}
+
+// Find returns the index of the annotation of a turn and block, or -1.
+func (a *Annotations) Find(turn, block int) int {
+	for i, item := range a.Items {
+		if item.Turn == turn && item.Block == block {
+			return i
+		}
+	}
+	return -1
+}
+
+// Set adds an annotation, replacing the one on the same turn and block.
+func (a *Annotations) Set(item Annotation) {
+	if i := a.Find(item.Turn, item.Block); i >= 0 {
+		a.Items[i] = item
+		return
+	}
+	a.Items = append(a.Items, item)
+	sort.SliceStable(a.Items, func(i, j int) bool {
+		if a.Items[i].Turn != a.Items[j].Turn {
+			return a.Items[i].Turn < a.Items[j].Turn
+		}
+		return a.Items[i].Block < a.Items[j].Block
+	})
+}
+
+// Remove deletes the annotation of a turn and block, reporting whether
+// there was one.
+func (a *Annotations) Remove(turn, block int) bool {
+	i := a.Find(turn, block)
+	if i < 0 {
+		return false
+	}
+	a.Items = append(a.Items[:i], a.Items[i+1:]...)
+	return true
+}
+
+// AnnotationStore keeps the annotations of sessions, next to them or in a
+// local directory.
+type AnnotationStore interface {
+	// LoadAnnotations returns the annotations of a session, empty if it
+	// has none.
+	LoadAnnotations(sessionID string) (*Annotations, error)
+
+	// SaveAnnotations stores the annotations of a session.
+	SaveAnnotations(a *Annotations) error
+}
+
+// AnnotationStoreFor returns where the annotations of sessions from src are
+// kept: the source itself if it can store them, or the default directory.
+func AnnotationStoreFor(src SessionSource) AnnotationStore {
+	if store, ok := src.(AnnotationStore); ok {
+		return store
+	}
+	return &FileAnnotationStore{Dir: DefaultAnnotationDir()}
+}
+
+// FileAnnotationStore keeps annotations in a directory, one
+// <session-id>.json file per session.
+type FileAnnotationStore struct {
+	Dir string
+}
+
+// DefaultAnnotationDir returns the directory annotations of local sessions
+// are kept in, under the user config directory.
+func DefaultAnnotationDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-replay", "annotations")
+}
+
+func (s *FileAnnotationStore) path(sessionID string) string {
+	return filepath.Join(s.Dir, sessionID+".json")
+}
+
+func (s *FileAnnotationStore) LoadAnnotations(sessionID string) (*Annotations, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Annotations{SessionID: sessionID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading annotations: %w", err)
+	}
+	return decodeAnnotations(sessionID, data)
+}
+
+func (s *FileAnnotationStore) SaveAnnotations(a *Annotations) error {
+	if s.Dir == "" {
+		return fmt.Errorf("no directory for annotations")
+	}
+	data, err := encodeAnnotations(a)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating annotation directory: %w", err)
+	}
+	// Write to a temporary file first so a crash cannot truncate the notes
+	tmp := s.path(a.SessionID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing annotations: %w", err)
+	}
+	if err := os.Rename(tmp, s.path(a.SessionID)); err != nil {
+		return fmt.Errorf("writing annotations: %w", err)
+	}
+	return nil
+}
+
+func decodeAnnotations(sessionID string, data []byte) (*Annotations, error) {
+	a := &Annotations{}
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, fmt.Errorf("parsing annotations of %s: %w", sessionID, err)
+	}
+	a.SessionID = sessionID
+	return a, nil
+}
+
+func encodeAnnotations(a *Annotations) ([]byte, error) {
+	if a.Items == nil {
+		a.Items = []Annotation{}
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding annotations: %w", err)
+	}
+	return append(data, '\n'), nil
+}
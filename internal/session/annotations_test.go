@@ -0,0 +1,111 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestAnnotations_SetAndRemove(t *testing.T) {
+	a := &Annotations{SessionID: "s"}
+	a.Set(Annotation{Turn: 3, Note: "third"})
+	a.Set(Annotation{Turn: 1, Block: 2, Note: "block"})
+	a.Set(Annotation{Turn: 1})
+	a.Set(Annotation{Turn: 3, Note: "edited"})
+
+	if len(a.Items) != 3 {
+		t.Fatalf("got %d annotations, want 3: %+v", len(a.Items), a.Items)
+	}
+	if a.Items[0].Block != 0 || a.Items[1].Block != 2 || a.Items[2].Note != "edited" {
+		t.Errorf("annotations not ordered by turn and block, or not replaced: %+v", a.Items)
+	}
+	if got := a.ForTurn(1); len(got) != 2 {
+		t.Errorf("ForTurn(1) = %+v, want 2 annotations", got)
+	}
+	if turns := a.Turns(); !turns[1] || !turns[3] || turns[2] {
+		t.Errorf("Turns() = %v", turns)
+	}
+
+	if !a.Remove(1, 2) || a.Remove(1, 2) {
+		t.Error("Remove should delete the annotation once")
+	}
+	var none *Annotations
+	if none.ForTurn(1) != nil || len(none.Turns()) != 0 {
+		t.Error("nil annotations should have none")
+	}
+}
+
+func TestFileAnnotationStore(t *testing.T) {
+	store := &FileAnnotationStore{Dir: t.TempDir() + "/annotations"}
+
+	a, err := store.LoadAnnotations("abc")
+	if err != nil || len(a.Items) != 0 {
+		t.Fatalf("LoadAnnotations of a new session = %+v, %v", a, err)
+	}
+	a.Set(Annotation{Turn: 2, Note: "agent ignored test failure here"})
+	if err := store.SaveAnnotations(a); err != nil {
+		t.Fatalf("SaveAnnotations: %v", err)
+	}
+
+	got, err := store.LoadAnnotations("abc")
+	if err != nil {
+		t.Fatalf("LoadAnnotations: %v", err)
+	}
+	if got.SessionID != "abc" || len(got.Items) != 1 || got.Items[0].Note != "agent ignored test failure here" {
+		t.Errorf("loaded %+v", got)
+	}
+}
+
+func TestGitSource_Annotations(t *testing.T) {
+	repo := setupTestGitRepo(t)
+	src := &GitSource{RepoPath: repo}
+	if _, ok := AnnotationStoreFor(src).(*GitSource); !ok {
+		t.Fatal("git sessions should keep their annotations on the branch")
+	}
+
+	id := "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	a, err := src.LoadAnnotations(id)
+	if err != nil || len(a.Items) != 0 {
+		t.Fatalf("LoadAnnotations = %+v, %v", a, err)
+	}
+	a.Set(Annotation{Turn: 1, Block: 1, Note: "look here"})
+	if err := src.SaveAnnotations(a); err != nil {
+		t.Fatalf("SaveAnnotations: %v", err)
+	}
+
+	got, err := src.LoadAnnotations(id)
+	if err != nil || len(got.Items) != 1 || got.Items[0].Note != "look here" {
+		t.Fatalf("LoadAnnotations after save = %+v, %v", got, err)
+	}
+
+	// Saving the same annotations again makes no commit
+	commits := func() string {
+		out, _ := exec.Command("git", "-C", repo, "rev-list", "--count", gitBranch).Output()
+		return strings.TrimSpace(string(out))
+	}
+	before := commits()
+	if err := src.SaveAnnotations(got); err != nil {
+		t.Fatalf("SaveAnnotations: %v", err)
+	}
+	if after := commits(); after != before {
+		t.Errorf("unchanged save made a commit: %s commits, want %s", after, before)
+	}
+
+	// The rest of the branch is kept, and the checkout is left alone
+	sessions, err := src.ListSessions("")
+	if err != nil || len(sessions) != 2 {
+		t.Errorf("ListSessions after annotating = %d sessions, %v", len(sessions), err)
+	}
+	status, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if err != nil || len(status) != 0 {
+		t.Errorf("worktree changed: %q, %v", status, err)
+	}
+	head, _ := exec.Command("git", "-C", repo, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if strings.TrimSpace(string(head)) != "main" {
+		t.Errorf("HEAD = %s, want main", head)
+	}
+	if _, err := os.Stat(repo + "/sessions"); err == nil {
+		t.Error("sessions should not be written to the worktree")
+	}
+}
@@ -0,0 +1,212 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// Fork is a point where the conversation branches: the user rewound or
+// edited a prompt, so several prompts continue from the same message.
+type Fork struct {
+	Turn     int      // number of the turn started by the selected branch, 0 if not shown
+	Branches []Branch // in the order they were written
+	Selected int      // index of the branch being replayed
+
+	parent string // node the branches continue from
+}
+
+// Branch is one alternative at a fork.
+type Branch struct {
+	UUID     string // record that starts the branch
+	UserText string // its prompt
+}
+
+// recordNode identifies a record in the message tree. Records without a
+// UUID get a synthetic one from their position.
+func recordNode(rec parser.Record, i int) string {
+	if rec.UUID != "" {
+		return rec.UUID
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// isPrompt reports whether a record is a message typed by the user, the
+// only kind of record a rewind branches at.
+func isPrompt(rec parser.Record) bool {
+	if rec.Type != parser.RecordTypeUser || rec.IsMeta || rec.IsCompactSummary {
+		return false
+	}
+	msg, err := rec.ParseUserMessage()
+	if err != nil {
+		return false
+	}
+	return !msg.IsToolResults() && !msg.IsBashOutput() && !msg.IsInterruption()
+}
+
+// selectBranches returns the records of the conversation along the chosen
+// branches, in file order, and the forks met along the way. choices maps a
+// fork's parent node to the UUID of the branch to follow; forks without a
+// choice follow the branch holding the most recently written record, which
+// is the one Claude Code continues from.
+func selectBranches(records []parser.Record, choices map[string]string) ([]parser.Record, []Fork) {
+	ids := make([]string, len(records))
+	parents := make([]string, len(records))
+	index := map[string]int{}
+	for i, rec := range records {
+		ids[i] = recordNode(rec, i)
+		index[ids[i]] = i
+	}
+	for i, rec := range records {
+		if rec.ParentUUID != nil {
+			if _, ok := index[*rec.ParentUUID]; ok {
+				parents[i] = *rec.ParentUUID
+				continue
+			}
+		}
+		// No tree information, or a parent missing from the file (e.g.
+		// cut by compaction): follow file order
+		if i > 0 && (rec.UUID == "" || (rec.ParentUUID != nil && *rec.ParentUUID != "")) {
+			parents[i] = ids[i-1]
+		}
+	}
+
+	// latest[i] is the position of the last record in i's subtree
+	latest := make([]int, len(records))
+	for i := range latest {
+		latest[i] = i
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if p, ok := index[parents[i]]; ok && p < i {
+			latest[p] = max(latest[p], latest[i])
+		}
+	}
+
+	// Prompt children of each node ("" is the root)
+	prompts := map[string][]int{}
+	var order []string
+	for i, rec := range records {
+		if !isPrompt(rec) {
+			continue
+		}
+		p := parents[i]
+		if _, ok := prompts[p]; !ok {
+			order = append(order, p)
+		}
+		prompts[p] = append(prompts[p], i)
+	}
+
+	// Drop the subtrees of the branches not taken
+	dropped := make([]bool, len(records))
+	var forks []Fork
+	for _, p := range order {
+		alts := prompts[p]
+		if len(alts) < 2 {
+			continue
+		}
+		if i, ok := index[p]; ok && dropped[i] {
+			continue
+		}
+
+		f := Fork{parent: p}
+		chosen := alts[0]
+		for _, a := range alts {
+			if latest[a] > latest[chosen] {
+				chosen = a
+			}
+		}
+		for _, a := range alts {
+			if ids[a] == choices[p] {
+				chosen = a
+			}
+		}
+		for k, a := range alts {
+			msg, _ := records[a].ParseUserMessage()
+			f.Branches = append(f.Branches, Branch{UUID: ids[a], UserText: msg.UserText()})
+			if a == chosen {
+				f.Selected = k
+			} else {
+				dropSubtree(a, parents, ids, dropped)
+			}
+		}
+		forks = append(forks, f)
+	}
+
+	var path []parser.Record
+	for i, rec := range records {
+		if !dropped[i] {
+			path = append(path, rec)
+		}
+	}
+	return path, forks
+}
+
+// dropSubtree marks record i and its descendants.
+func dropSubtree(i int, parents, ids []string, dropped []bool) {
+	dropped[i] = true
+	inTree := map[string]bool{ids[i]: true}
+	for j := i + 1; j < len(parents); j++ {
+		if inTree[parents[j]] {
+			inTree[ids[j]] = true
+			dropped[j] = true
+		}
+	}
+}
+
+// WithBranch returns the session replayed along branch b of fork f, with
+// the other forks keeping their current selection.
+func (s *Session) WithBranch(f, b int) *Session {
+	if f < 0 || f >= len(s.Forks) || b < 0 || b >= len(s.Forks[f].Branches) {
+		return s
+	}
+	choices := map[string]string{}
+	for k, v := range s.choices {
+		choices[k] = v
+	}
+	for _, fork := range s.Forks {
+		choices[fork.parent] = fork.Branches[fork.Selected].UUID
+	}
+	choices[s.Forks[f].parent] = s.Forks[f].Branches[b].UUID
+	return s.withChoices(choices)
+}
+
+// KeepBranches returns s replayed along the branches picked with
+// WithBranch in prev, an earlier build of the same session: a followed
+// session is rebuilt from its file on every update.
+func (s *Session) KeepBranches(prev *Session) *Session {
+	if prev == nil || len(prev.choices) == 0 {
+		return s
+	}
+	return s.withChoices(prev.choices)
+}
+
+// withChoices rebuilds the turns of s along the given branches.
+func (s *Session) withChoices(choices map[string]string) *Session {
+	out := &Session{
+		ID:        s.ID,
+		Slug:      s.Slug,
+		Path:      s.Path,
+		Version:   s.Version,
+		GitBranch: s.GitBranch,
+		CWD:       s.CWD,
+		choices:   choices,
+	}
+	out.Turns = segmentTurns(s.records, out)
+	out.setTimes()
+	if s.redactor != nil {
+		// The records are raw: keep the other branch redacted as well
+		out, _ = s.redactor.Session(out)
+	}
+	return out
+}
+
+// ForkAt returns the index of the fork whose selected branch starts the
+// turn with the given number, or -1.
+func (s *Session) ForkAt(turn int) int {
+	for i, f := range s.Forks {
+		if f.Turn == turn {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRewoundSession writes a session where the user rewound to the
+// second prompt and asked again: "try A" was abandoned for "try B".
+func writeRewoundSession(t *testing.T) string {
+	t.Helper()
+	lines := []string{
+		`{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"set up"},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"done"}]},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"a1","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:00:10.000Z","message":{"role":"user","content":"try A"},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"u2","uuid":"a2","sessionId":"s1","timestamp":"2026-02-13T12:00:11.000Z","message":{"model":"claude-opus-4-6","id":"msg_2","role":"assistant","content":[{"type":"text","text":"A failed"}]},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"a2","uuid":"u3","sessionId":"s1","timestamp":"2026-02-13T12:00:20.000Z","message":{"role":"user","content":"fix A"},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"u3","uuid":"a3","sessionId":"s1","timestamp":"2026-02-13T12:00:21.000Z","message":{"model":"claude-opus-4-6","id":"msg_3","role":"assistant","content":[{"type":"text","text":"A fixed"}]},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"a1","uuid":"u4","sessionId":"s1","timestamp":"2026-02-13T12:01:00.000Z","message":{"role":"user","content":"try B"},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"u4","uuid":"a4","sessionId":"s1","timestamp":"2026-02-13T12:01:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_4","role":"assistant","content":[{"type":"text","text":"B works"}]},"isSidechain":false}`,
+	}
+	path := filepath.Join(t.TempDir(), "rewound.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	return path
+}
+
+func turnTexts(sess *Session) []string {
+	var texts []string
+	for _, turn := range sess.Turns {
+		texts = append(texts, turn.UserText)
+	}
+	return texts
+}
+
+func TestLoadSession_ReplaysLatestBranch(t *testing.T) {
+	sess, err := LoadSession(writeRewoundSession(t))
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+
+	if got := strings.Join(turnTexts(sess), "|"); got != "set up|try B" {
+		t.Errorf("turns = %q, want the abandoned branch left out", got)
+	}
+	if len(sess.Forks) != 1 {
+		t.Fatalf("expected 1 fork, got %d", len(sess.Forks))
+	}
+	f := sess.Forks[0]
+	if f.Turn != 2 || f.Selected != 1 || len(f.Branches) != 2 {
+		t.Errorf("fork = %+v, want turn 2 with branch 2 of 2 selected", f)
+	}
+	if f.Branches[0].UserText != "try A" || f.Branches[1].UserText != "try B" {
+		t.Errorf("branches = %+v", f.Branches)
+	}
+	if sess.ForkAt(2) != 0 || sess.ForkAt(1) != -1 {
+		t.Errorf("ForkAt(2) = %d, ForkAt(1) = %d", sess.ForkAt(2), sess.ForkAt(1))
+	}
+	if last := sess.Turns[1].Blocks; len(last) != 1 || last[0].Text != "B works" {
+		t.Errorf("turn 2 blocks = %+v", last)
+	}
+}
+
+func TestSession_WithBranch(t *testing.T) {
+	sess, err := LoadSession(writeRewoundSession(t))
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+
+	other := sess.WithBranch(0, 0)
+	if got := strings.Join(turnTexts(other), "|"); got != "set up|try A|fix A" {
+		t.Errorf("turns = %q", got)
+	}
+	if other.Forks[0].Selected != 0 || other.Forks[0].Turn != 2 {
+		t.Errorf("fork = %+v", other.Forks[0])
+	}
+	if other.ID != sess.ID || other.StartTime.IsZero() {
+		t.Errorf("session metadata not carried over: %+v", other)
+	}
+
+	// Switching back returns to the default path; the original is untouched
+	back := other.WithBranch(0, 1)
+	if got := strings.Join(turnTexts(back), "|"); got != "set up|try B" {
+		t.Errorf("turns after switching back = %q", got)
+	}
+	if len(sess.Turns) != 2 {
+		t.Errorf("original session changed: %d turns", len(sess.Turns))
+	}
+	if sess.WithBranch(1, 0) != sess || sess.WithBranch(0, 2) != sess {
+		t.Error("out of range branch should return the session unchanged")
+	}
+}
+
+func TestSession_KeepBranches(t *testing.T) {
+	sess, err := LoadSession(writeRewoundSession(t))
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+	rebuilt, _ := LoadSession(sess.Path)
+	if rebuilt.KeepBranches(sess) != rebuilt {
+		t.Error("without a picked branch the rebuilt session should be kept as is")
+	}
+
+	other := sess.WithBranch(0, 0)
+	kept := rebuilt.KeepBranches(other)
+	if got := strings.Join(turnTexts(kept), "|"); got != "set up|try A|fix A" {
+		t.Errorf("turns = %q, want the picked branch kept", got)
+	}
+	if kept.Forks[0].Selected != 0 {
+		t.Errorf("fork = %+v", kept.Forks[0])
+	}
+}
+
+func TestLoadSession_NoTreeFollowsFileOrder(t *testing.T) {
+	lines := []string{
+		`{"type":"user","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"one"},"isSidechain":false}`,
+		`{"type":"assistant","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"ok"}]},"isSidechain":false}`,
+		`{"type":"user","sessionId":"s1","timestamp":"2026-02-13T12:00:10.000Z","message":{"role":"user","content":"two"},"isSidechain":false}`,
+	}
+	path := filepath.Join(t.TempDir(), "flat.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+	if got := strings.Join(turnTexts(sess), "|"); got != "one|two" || len(sess.Forks) != 0 {
+		t.Errorf("turns = %q, forks = %d", got, len(sess.Forks))
+	}
+}
@@ -0,0 +1,225 @@
+package session
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TurnPair links a turn of session A to a turn of session B. An index of
+// -1 means the turn has no counterpart on that side.
+type TurnPair struct {
+	A, B int // 0-based turn indexes
+}
+
+// Comparison is the alignment and summary of two sessions.
+type Comparison struct {
+	Pairs []TurnPair
+	A, B  SessionSummary
+
+	// Files touched by only one side, and by both
+	FilesOnlyA, FilesOnlyB, FilesBoth []string
+}
+
+// SessionSummary holds the figures compared between two sessions.
+type SessionSummary struct {
+	Turns    int
+	Duration time.Duration
+	Usage    Usage
+	Files    []string // files written by Edit/Write calls, sorted
+}
+
+// Compare aligns the turns of two sessions by user text and summarizes
+// their differences.
+func Compare(a, b *Session) Comparison {
+	c := Comparison{
+		Pairs: AlignTurns(a.Turns, b.Turns),
+		A:     summarize(a),
+		B:     summarize(b),
+	}
+
+	inA := map[string]bool{}
+	for _, f := range c.A.Files {
+		inA[f] = true
+	}
+	inB := map[string]bool{}
+	for _, f := range c.B.Files {
+		inB[f] = true
+		if inA[f] {
+			c.FilesBoth = append(c.FilesBoth, f)
+		} else {
+			c.FilesOnlyB = append(c.FilesOnlyB, f)
+		}
+	}
+	for _, f := range c.A.Files {
+		if !inB[f] {
+			c.FilesOnlyA = append(c.FilesOnlyA, f)
+		}
+	}
+	return c
+}
+
+func summarize(sess *Session) SessionSummary {
+	s := SessionSummary{
+		Turns: len(sess.Turns),
+		Usage: sess.TotalUsage(),
+		Files: FilesTouched(sess.Turns),
+	}
+	for _, t := range sess.Turns {
+		s.Duration += t.Duration
+	}
+	return s
+}
+
+// AlignTurns pairs turns with the same user text, preserving order (a
+// longest common subsequence). Unmatched turns between two matches are
+// paired up side by side, so a rephrased prompt still lines up with its
+// counterpart; leftovers get a -1 partner.
+func AlignTurns(a, b []Turn) []TurnPair {
+	n, m := len(a), len(b)
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if SameUserText(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var pairs []TurnPair
+	var gapA, gapB []int
+	flush := func() {
+		for k := 0; k < max(len(gapA), len(gapB)); k++ {
+			p := TurnPair{A: -1, B: -1}
+			if k < len(gapA) {
+				p.A = gapA[k]
+			}
+			if k < len(gapB) {
+				p.B = gapB[k]
+			}
+			pairs = append(pairs, p)
+		}
+		gapA, gapB = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case SameUserText(a[i], b[j]):
+			flush()
+			pairs = append(pairs, TurnPair{A: i, B: j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			gapA = append(gapA, i)
+			i++
+		default:
+			gapB = append(gapB, j)
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		gapA = append(gapA, i)
+	}
+	for ; j < m; j++ {
+		gapB = append(gapB, j)
+	}
+	flush()
+	return pairs
+}
+
+// SameUserText reports whether two turns start with the same prompt,
+// ignoring case and whitespace differences.
+func SameUserText(a, b Turn) bool {
+	return normalizeText(a.UserText) == normalizeText(b.UserText)
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// ToolCall is one tool invocation, identified by tool name and its main
+// parameter (file path, command, pattern, ...).
+type ToolCall struct {
+	Name  string
+	Param string
+}
+
+// ToolCalls returns the tool calls of a turn in order.
+func ToolCalls(t Turn) []ToolCall {
+	var calls []ToolCall
+	for _, b := range t.Blocks {
+		if b.Type == BlockToolUse {
+			calls = append(calls, ToolCall{Name: b.ToolName, Param: toolKeyParam(b)})
+		}
+	}
+	return calls
+}
+
+// toolKeyParam returns the input parameter that identifies what a tool call
+// acted on.
+func toolKeyParam(b Block) string {
+	for _, key := range []string{"file_path", "notebook_path", "command", "pattern", "url", "query", "description"} {
+		if v, ok := b.ToolInput[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ToolDivergence returns the index of the first tool call that differs
+// between two turns, or -1 if their tool-call sequences are identical.
+func ToolDivergence(a, b Turn) int {
+	ca, cb := ToolCalls(a), ToolCalls(b)
+	for i := 0; i < min(len(ca), len(cb)); i++ {
+		if ca[i] != cb[i] {
+			return i
+		}
+	}
+	if len(ca) != len(cb) {
+		return min(len(ca), len(cb))
+	}
+	return -1
+}
+
+// FilesTouched returns the sorted set of files modified by Edit, Write and
+// NotebookEdit calls, including those of subagents.
+func FilesTouched(turns []Turn) []string {
+	seen := map[string]bool{}
+	var walk func([]Turn)
+	walk = func(turns []Turn) {
+		for _, t := range turns {
+			for _, b := range t.Blocks {
+				if b.Type != BlockToolUse {
+					continue
+				}
+				switch b.ToolName {
+				case "Edit", "MultiEdit", "Write":
+					if p, _ := b.ToolInput["file_path"].(string); p != "" {
+						seen[p] = true
+					}
+				case "NotebookEdit":
+					if p, _ := b.ToolInput["notebook_path"].(string); p != "" {
+						seen[p] = true
+					}
+				}
+				walk(b.Sidechain)
+			}
+		}
+	}
+	walk(turns)
+
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func turnsWithText(texts ...string) []Turn {
+	turns := make([]Turn, len(texts))
+	for i, text := range texts {
+		turns[i] = Turn{Number: i + 1, UserText: text}
+	}
+	return turns
+}
+
+func TestAlignTurns(t *testing.T) {
+	a := turnsWithText("Fix the bug", "run tests", "commit")
+	b := turnsWithText("fix the  bug", "add a test first", "Run tests", "also lint", "ship it")
+
+	got := AlignTurns(a, b)
+	want := []TurnPair{
+		{A: 0, B: 0},
+		{A: -1, B: 1},
+		{A: 1, B: 2},
+		{A: 2, B: 3}, // unmatched turns between matches line up
+		{A: -1, B: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AlignTurns:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestToolDivergence(t *testing.T) {
+	tool := func(name, path string) Block {
+		return Block{Type: BlockToolUse, ToolName: name, ToolInput: map[string]interface{}{"file_path": path}}
+	}
+	a := Turn{Blocks: []Block{tool("Read", "a.go"), tool("Edit", "a.go")}}
+	same := Turn{Blocks: []Block{{Type: BlockText, Text: "hi"}, tool("Read", "a.go"), tool("Edit", "a.go")}}
+	other := Turn{Blocks: []Block{tool("Read", "a.go"), tool("Edit", "b.go")}}
+	longer := Turn{Blocks: []Block{tool("Read", "a.go"), tool("Edit", "a.go"), tool("Bash", "")}}
+
+	if got := ToolDivergence(a, same); got != -1 {
+		t.Errorf("identical sequences: got %d", got)
+	}
+	if got := ToolDivergence(a, other); got != 1 {
+		t.Errorf("different file: got %d, want 1", got)
+	}
+	if got := ToolDivergence(a, longer); got != 2 {
+		t.Errorf("extra call: got %d, want 2", got)
+	}
+}
+
+func TestCompare_Summary(t *testing.T) {
+	write := func(path string) Block {
+		return Block{Type: BlockToolUse, ToolName: "Write", ToolInput: map[string]interface{}{"file_path": path}}
+	}
+	a := &Session{Turns: []Turn{
+		{UserText: "go", Duration: time.Second, Usage: Usage{OutputTokens: 10}, Blocks: []Block{write("/x.go"), write("/y.go")}},
+	}}
+	b := &Session{Turns: []Turn{
+		{UserText: "go", Duration: 2 * time.Second, Blocks: []Block{write("/y.go"), write("/z.go")}},
+		{UserText: "more"},
+	}}
+
+	c := Compare(a, b)
+	if c.A.Turns != 1 || c.B.Turns != 2 || c.B.Duration != 2*time.Second || c.A.Usage.OutputTokens != 10 {
+		t.Errorf("summaries: %+v / %+v", c.A, c.B)
+	}
+	if !reflect.DeepEqual(c.FilesOnlyA, []string{"/x.go"}) ||
+		!reflect.DeepEqual(c.FilesOnlyB, []string{"/z.go"}) ||
+		!reflect.DeepEqual(c.FilesBoth, []string{"/y.go"}) {
+		t.Errorf("files: only A %v, only B %v, both %v", c.FilesOnlyA, c.FilesOnlyB, c.FilesBoth)
+	}
+	if len(c.Pairs) != 2 || c.Pairs[1] != (TurnPair{A: -1, B: 1}) {
+		t.Errorf("pairs: %v", c.Pairs)
+	}
+}
@@ -0,0 +1,171 @@
+package session
+
+import "time"
+
+// DumpVersion is the version of the dump schema. It is raised when a field
+// is removed or changes meaning; fields may be added within a version, so
+// consumers should ignore fields they don't know.
+const DumpVersion = 1
+
+// SessionDump is the JSON form of a session written by the dump command:
+// the session as replayed, segmented into turns and blocks.
+type SessionDump struct {
+	Version       int        `json:"version"` // DumpVersion
+	ID            string     `json:"id"`
+	Slug          string     `json:"slug,omitempty"`
+	Path          string     `json:"path,omitempty"`
+	CWD           string     `json:"cwd,omitempty"`
+	GitBranch     string     `json:"git_branch,omitempty"`
+	ClientVersion string     `json:"client_version,omitempty"` // Claude Code version
+	Model         string     `json:"model,omitempty"`          // model of the first response
+	Models        []string   `json:"models"`                   // every model used, in order of first use
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"` // start of the last turn
+	Usage         UsageDump  `json:"usage"`    // all turns, subagents included
+	Forks         []ForkDump `json:"forks"`    // rewound prompts on the replayed branch
+	Turns         []TurnDump `json:"turns"`
+}
+
+// TurnDump is a prompt and everything up to the next one.
+type TurnDump struct {
+	Number     int         `json:"number"` // 1-based
+	UUID       string      `json:"uuid,omitempty"`
+	Prompt     string      `json:"prompt"`
+	Timestamp  time.Time   `json:"timestamp"`
+	DurationMS int64       `json:"duration_ms"` // 0 when not recorded
+	Model      string      `json:"model,omitempty"`
+	CWD        string      `json:"cwd,omitempty"`
+	GitBranch  string      `json:"git_branch,omitempty"`
+	Usage      UsageDump   `json:"usage"`       // this turn's API calls
+	TotalUsage UsageDump   `json:"total_usage"` // including subagents
+	Blocks     []BlockDump `json:"blocks"`
+}
+
+// BlockDump is one piece of a turn. Type is one of text, thinking,
+// tool_use, tool_result, compaction, api_error, hook and interruption.
+// Tool results carry the tool_id of the tool_use they answer.
+type BlockDump struct {
+	Type      string                 `json:"type"`
+	Text      string                 `json:"text,omitempty"` // text, thinking, tool result or compaction summary
+	ToolName  string                 `json:"tool_name,omitempty"`
+	ToolID    string                 `json:"tool_id,omitempty"`
+	ToolInput map[string]interface{} `json:"tool_input,omitempty"`
+	IsError   bool                   `json:"is_error,omitempty"`
+	Detail    string                 `json:"detail,omitempty"` // one-line description of events
+	Timestamp *time.Time             `json:"timestamp,omitempty"`
+	Sidechain []TurnDump             `json:"sidechain,omitempty"` // subagent transcript of a tool_use
+}
+
+// UsageDump holds token counts.
+type UsageDump struct {
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	CacheCreationTokens int `json:"cache_creation_tokens"`
+	CacheReadTokens     int `json:"cache_read_tokens"`
+}
+
+// ForkDump is a prompt that was rewound and sent again.
+type ForkDump struct {
+	Turn     int      `json:"turn"`     // turn started by the replayed branch, 0 if not shown
+	Selected int      `json:"selected"` // index of the replayed branch
+	Branches []string `json:"branches"` // prompt of each branch, in the order written
+}
+
+// blockTypeNames are the dump names of block types.
+var blockTypeNames = map[BlockType]string{
+	BlockText:         "text",
+	BlockThinking:     "thinking",
+	BlockToolUse:      "tool_use",
+	BlockToolResult:   "tool_result",
+	BlockCompaction:   "compaction",
+	BlockAPIError:     "api_error",
+	BlockHook:         "hook",
+	BlockInterruption: "interruption",
+}
+
+// Dump returns the JSON form of the session.
+func (s *Session) Dump() SessionDump {
+	d := SessionDump{
+		Version:       DumpVersion,
+		ID:            s.ID,
+		Slug:          s.Slug,
+		Path:          s.Path,
+		CWD:           s.CWD,
+		GitBranch:     s.GitBranch,
+		ClientVersion: s.Version,
+		Model:         s.Model,
+		StartTime:     s.StartTime,
+		EndTime:       s.EndTime,
+		Forks:         []ForkDump{},
+		Turns:         dumpTurns(s.Turns),
+	}
+	var total Usage
+	seen := map[string]bool{}
+	d.Models = []string{}
+	for _, t := range s.Turns {
+		total = total.Add(t.TotalUsage())
+		if t.Model != "" && !seen[t.Model] {
+			seen[t.Model] = true
+			d.Models = append(d.Models, t.Model)
+		}
+	}
+	d.Usage = dumpUsage(total)
+
+	for _, f := range s.Forks {
+		fork := ForkDump{Turn: f.Turn, Selected: f.Selected, Branches: []string{}}
+		for _, b := range f.Branches {
+			fork.Branches = append(fork.Branches, b.UserText)
+		}
+		d.Forks = append(d.Forks, fork)
+	}
+	return d
+}
+
+func dumpTurns(turns []Turn) []TurnDump {
+	out := make([]TurnDump, 0, len(turns))
+	for _, t := range turns {
+		td := TurnDump{
+			Number:     t.Number,
+			UUID:       t.UUID,
+			Prompt:     t.UserText,
+			Timestamp:  t.Timestamp,
+			DurationMS: t.Duration.Milliseconds(),
+			Model:      t.Model,
+			CWD:        t.CWD,
+			GitBranch:  t.GitBranch,
+			Usage:      dumpUsage(t.Usage),
+			TotalUsage: dumpUsage(t.TotalUsage()),
+			Blocks:     make([]BlockDump, 0, len(t.Blocks)),
+		}
+		for _, b := range t.Blocks {
+			bd := BlockDump{
+				Type:      blockTypeNames[b.Type],
+				Text:      b.Text,
+				ToolName:  b.ToolName,
+				ToolID:    b.ToolID,
+				ToolInput: b.ToolInput,
+				IsError:   b.IsError,
+				Detail:    b.Detail,
+			}
+			if !b.Timestamp.IsZero() {
+				ts := b.Timestamp
+				bd.Timestamp = &ts
+			}
+			if len(b.Sidechain) > 0 {
+				bd.Sidechain = dumpTurns(b.Sidechain)
+			}
+			td.Blocks = append(td.Blocks, bd)
+		}
+		out = append(out, td)
+	}
+	return out
+}
+
+func dumpUsage(u Usage) UsageDump {
+	return UsageDump{
+		InputTokens:         u.InputTokens,
+		OutputTokens:        u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens,
+	}
+}
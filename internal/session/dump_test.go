@@ -0,0 +1,85 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSession_Dump(t *testing.T) {
+	start := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	sess := &Session{
+		ID:        "abc",
+		Slug:      "test-slug",
+		Model:     "claude-opus-4-6",
+		StartTime: start,
+		EndTime:   start.Add(time.Minute),
+		Forks:     []Fork{{Turn: 2, Selected: 1, Branches: []Branch{{UserText: "first try"}, {UserText: "second try"}}}},
+		Turns: []Turn{
+			{
+				Number:    1,
+				UserText:  "run the tests",
+				Timestamp: start,
+				Duration:  1500 * time.Millisecond,
+				Model:     "claude-opus-4-6",
+				Usage:     Usage{InputTokens: 10, OutputTokens: 5},
+				Blocks: []Block{
+					{Type: BlockToolUse, ToolName: "Bash", ToolID: "t1", ToolInput: map[string]interface{}{"command": "go test"}, Timestamp: start.Add(time.Second)},
+					{Type: BlockToolResult, ToolID: "t1", Text: "FAIL", IsError: true},
+					{Type: BlockToolUse, ToolName: "Task", ToolID: "t2", Sidechain: []Turn{
+						{Number: 1, UserText: "investigate", Model: "claude-haiku-4-5", Usage: Usage{InputTokens: 100}},
+					}},
+				},
+			},
+			{Number: 2, UserText: "second try", Model: "claude-sonnet-4-5", Blocks: []Block{{Type: BlockInterruption, Detail: "interrupted"}}},
+		},
+	}
+
+	data, err := json.Marshal(sess.Dump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["version"] != float64(DumpVersion) || got["id"] != "abc" {
+		t.Errorf("header fields: %s", data)
+	}
+	if models := got["models"].([]interface{}); len(models) != 2 || models[1] != "claude-sonnet-4-5" {
+		t.Errorf("models = %v, want opus then sonnet", models)
+	}
+	if usage := got["usage"].(map[string]interface{}); usage["input_tokens"] != float64(110) {
+		t.Errorf("session usage = %v, want subagent tokens included", usage)
+	}
+	if fork := got["forks"].([]interface{})[0].(map[string]interface{}); fork["turn"] != float64(2) || len(fork["branches"].([]interface{})) != 2 {
+		t.Errorf("fork = %v", fork)
+	}
+
+	turn := got["turns"].([]interface{})[0].(map[string]interface{})
+	if turn["prompt"] != "run the tests" || turn["duration_ms"] != float64(1500) {
+		t.Errorf("turn = %v", turn)
+	}
+	if turn["usage"].(map[string]interface{})["input_tokens"] != float64(10) ||
+		turn["total_usage"].(map[string]interface{})["input_tokens"] != float64(110) {
+		t.Errorf("turn usage = %v / %v", turn["usage"], turn["total_usage"])
+	}
+
+	blocks := turn["blocks"].([]interface{})
+	use, result := blocks[0].(map[string]interface{}), blocks[1].(map[string]interface{})
+	if use["type"] != "tool_use" || use["tool_id"] != "t1" || use["tool_input"].(map[string]interface{})["command"] != "go test" || use["timestamp"] == nil {
+		t.Errorf("tool_use block = %v", use)
+	}
+	if result["type"] != "tool_result" || result["tool_id"] != "t1" || result["is_error"] != true || result["timestamp"] != nil {
+		t.Errorf("tool_result block = %v", result)
+	}
+	side := blocks[2].(map[string]interface{})["sidechain"].([]interface{})[0].(map[string]interface{})
+	if side["prompt"] != "investigate" || side["model"] != "claude-haiku-4-5" {
+		t.Errorf("sidechain turn = %v", side)
+	}
+	if !strings.Contains(string(data), `"type":"interruption"`) {
+		t.Errorf("event block type missing: %s", data)
+	}
+}
@@ -0,0 +1,199 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// IsEvent reports whether blocks of this type are events (compaction, API
+// errors, hooks, interruptions) rather than conversation content.
+func (t BlockType) IsEvent() bool {
+	return t >= BlockCompaction
+}
+
+// hookEventRe matches system messages reporting on a hook.
+var hookEventRe = regexp.MustCompile(`^(PreToolUse|PostToolUse|Notification|UserPromptSubmit|SessionStart|SessionEnd|Stop|SubagentStop|PreCompact)\b`)
+
+// recordEvent converts a system or attachment record into an event block.
+// It reports false for records that are not events worth showing.
+func recordEvent(rec parser.Record) (Block, bool) {
+	switch rec.Type {
+	case parser.RecordTypeSystem:
+		switch rec.Subtype {
+		case "compact_boundary":
+			b := Block{Type: BlockCompaction}
+			if md := rec.CompactMetadata; md != nil {
+				var parts []string
+				if md.Trigger != "" {
+					parts = append(parts, md.Trigger)
+				}
+				if md.PreTokens > 0 {
+					parts = append(parts, FormatTokens(md.PreTokens)+" tokens")
+				}
+				b.Detail = strings.Join(parts, " · ")
+			}
+			return b, true
+
+		case "api_error":
+			b := Block{Type: BlockAPIError, Text: errorMessage(rec.Error)}
+			if rec.MaxRetries > 0 {
+				b.Detail = fmt.Sprintf("retry %d/%d", rec.RetryAttempt, rec.MaxRetries)
+			}
+			return b, true
+
+		case "stop_hook_summary":
+			errs := hookErrors(rec.HookErrors)
+			if len(errs) == 0 && !rec.PreventedContinuation {
+				return Block{}, false
+			}
+			b := Block{Type: BlockHook, Detail: "Stop", Text: strings.Join(errs, "\n")}
+			if rec.PreventedContinuation {
+				b.Detail += " · blocked"
+				b.Text = strings.TrimSpace(rec.StopReason + "\n" + b.Text)
+			}
+			return b, true
+		}
+
+		if text := rec.SystemText(); hookEventRe.MatchString(text) {
+			return Block{Type: BlockHook, Detail: hookEventRe.FindString(text), Text: text}, true
+		}
+
+	case parser.RecordTypeAttachment:
+		a, err := rec.ParseAttachment()
+		if err != nil || !strings.HasPrefix(a.Type, "hook_") {
+			return Block{}, false
+		}
+		failed := strings.Contains(a.Type, "error")
+		var parts []string
+		for _, s := range []string{a.Stdout, a.Stderr, rawText(a.Content), rawText(a.BlockingError)} {
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 0 && !failed {
+			return Block{}, false
+		}
+		b := Block{Type: BlockHook, Detail: a.HookName, Text: strings.Join(parts, "\n")}
+		if b.Detail == "" {
+			b.Detail = a.HookEvent
+		}
+		if failed {
+			outcome := strings.ReplaceAll(strings.TrimPrefix(a.Type, "hook_"), "_", " ")
+			if b.Detail != "" {
+				outcome = b.Detail + " · " + outcome
+			}
+			b.Detail = outcome
+		}
+		return b, true
+	}
+	return Block{}, false
+}
+
+// compactPreamble opens every compaction summary.
+const compactPreamble = "This session is being continued from a previous conversation"
+
+// addCompactSummary puts the summary written after a compaction into the
+// compaction block it belongs to, the last one still without a summary.
+func addCompactSummary(blocks []Block, summary string) []Block {
+	if strings.HasPrefix(summary, compactPreamble) {
+		if _, rest, ok := strings.Cut(summary, "\n\n"); ok {
+			summary = rest
+		}
+	}
+	summary = strings.TrimSpace(summary)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].Type == BlockCompaction && blocks[i].Text == "" {
+			blocks[i].Text = summary
+			return blocks
+		}
+	}
+	return append(blocks, Block{Type: BlockCompaction, Text: summary})
+}
+
+// errorMessage extracts the message of an API error, which is logged as a
+// string or as a (possibly nested) error object.
+func errorMessage(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	if msg := findMessage(v); msg != "" {
+		return msg
+	}
+	if v == nil {
+		return ""
+	}
+	return string(raw)
+}
+
+func findMessage(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if msg, ok := v["message"].(string); ok && msg != "" {
+			return msg
+		}
+		for _, k := range []string{"error", "cause"} {
+			if msg := findMessage(v[k]); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
+
+// hookErrors reads the errors of a stop hook summary: strings or error
+// objects.
+func hookErrors(raw json.RawMessage) []string {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil
+	}
+	var errs []string
+	for _, item := range items {
+		if msg := strings.TrimSpace(errorMessage(item)); msg != "" {
+			errs = append(errs, msg)
+		}
+	}
+	return errs
+}
+
+// rawText reads a JSON value holding text: a string, an array of strings or
+// text blocks, or an object with the text in a field.
+func rawText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	return textOf(v)
+}
+
+func textOf(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var parts []string
+		for _, item := range v {
+			if s := textOf(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, "\n")
+	case map[string]interface{}:
+		for _, k := range []string{"text", "blockingError", "message", "content"} {
+			if s := textOf(v[k]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSession_Events(t *testing.T) {
+	lines := []string{
+		`{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"refactor it"},"isSidechain":false}`,
+		`{"type":"system","parentUuid":"u1","uuid":"e1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","subtype":"api_error","level":"error","error":{"status":529,"error":{"type":"overloaded_error","message":"Overloaded"}},"retryAttempt":1,"maxRetries":10,"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"e1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","isApiErrorMessage":true,"message":{"model":"<synthetic>","id":"msg_e","role":"assistant","content":[{"type":"text","text":"API Error: 500 internal error"}]},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"a1","uuid":"a2","sessionId":"s1","timestamp":"2026-02-13T12:00:03.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"Working on it"}]},"isSidechain":false}`,
+		`{"type":"system","parentUuid":null,"logicalParentUuid":"a2","uuid":"c1","sessionId":"s1","timestamp":"2026-02-13T12:00:04.000Z","subtype":"compact_boundary","content":"Conversation compacted","level":"info","compactMetadata":{"trigger":"auto","preTokens":175197},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"c1","uuid":"c2","sessionId":"s1","timestamp":"2026-02-13T12:00:05.000Z","isCompactSummary":true,"message":{"role":"user","content":"This session is being continued from a previous conversation that ran out of context.\n\nSummary:\n1. The user asked for a refactor."},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"c2","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:00:06.000Z","message":{"role":"user","content":[{"type":"text","text":"[Request interrupted by user]"}]},"isSidechain":false}`,
+		`{"type":"system","parentUuid":"u2","uuid":"h1","sessionId":"s1","timestamp":"2026-02-13T12:00:07.000Z","subtype":"stop_hook_summary","hookCount":1,"hookInfos":[{"command":"make lint"}],"hookErrors":["lint failed"],"preventedContinuation":false,"isSidechain":false}`,
+		`{"type":"attachment","parentUuid":"h1","uuid":"h2","sessionId":"s1","timestamp":"2026-02-13T12:00:08.000Z","attachment":{"type":"hook_success","hookName":"SessionStart:startup","content":""},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"h2","uuid":"u3","sessionId":"s1","timestamp":"2026-02-13T12:00:09.000Z","message":{"role":"user","content":"go on"},"isSidechain":false}`,
+	}
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+
+	if len(sess.Turns) != 2 {
+		t.Fatalf("the summary and the interruption are not prompts: expected 2 turns, got %d", len(sess.Turns))
+	}
+	if sess.Turns[0].Model != "claude-opus-4-6" {
+		t.Errorf("API errors should not set the turn model, got %q", sess.Turns[0].Model)
+	}
+
+	want := []Block{
+		{Type: BlockAPIError, Detail: "retry 1/10", Text: "Overloaded"},
+		{Type: BlockAPIError, Text: "API Error: 500 internal error"},
+		{Type: BlockText, Text: "Working on it"},
+		{Type: BlockCompaction, Detail: "auto · 175.2k tokens", Text: "Summary:\n1. The user asked for a refactor."},
+		{Type: BlockInterruption, Text: "[Request interrupted by user]"},
+		{Type: BlockHook, Detail: "Stop", Text: "lint failed"},
+	}
+	got := sess.Turns[0].Blocks
+	if len(got) != len(want) {
+		t.Fatalf("expected %d blocks, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Type != w.Type || got[i].Detail != w.Detail || got[i].Text != w.Text {
+			t.Errorf("block %d = {%d %q %q}, want {%d %q %q}", i, got[i].Type, got[i].Detail, got[i].Text, w.Type, w.Detail, w.Text)
+		}
+	}
+	for _, b := range got {
+		if b.Type.IsEvent() != (b.Type != BlockText) {
+			t.Errorf("IsEvent(%d) = %v", b.Type, b.Type.IsEvent())
+		}
+	}
+}
+
+func TestLoadSession_EventBeforeFirstTurn(t *testing.T) {
+	lines := []string{
+		`{"type":"system","parentUuid":null,"uuid":"c1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","subtype":"compact_boundary","content":"Conversation compacted","compactMetadata":{"trigger":"manual","preTokens":900},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"c1","uuid":"c2","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","isCompactSummary":true,"message":{"role":"user","content":"Earlier work"},"isSidechain":false}`,
+		`{"type":"user","parentUuid":"c2","uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","message":{"role":"user","content":"next step"},"isSidechain":false}`,
+	}
+	path := filepath.Join(t.TempDir(), "resumed.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+	if len(sess.Turns) != 1 || sess.Turns[0].UserText != "next step" {
+		t.Fatalf("turns = %+v", sess.Turns)
+	}
+	blocks := sess.Turns[0].Blocks
+	if len(blocks) != 1 || blocks[0].Type != BlockCompaction || blocks[0].Text != "Earlier work" || blocks[0].Detail != "manual · 900 tokens" {
+		t.Errorf("compaction before the first prompt should open the first turn, got %+v", blocks)
+	}
+}
@@ -0,0 +1,325 @@
+package session
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileOp is what a tool call did to a file.
+type FileOp string
+
+const (
+	FileRead  FileOp = "read"
+	FileEdit  FileOp = "edit"
+	FileWrite FileOp = "write"
+)
+
+// TextEdit is one old_string → new_string replacement of an Edit call.
+type TextEdit struct {
+	Old, New   string
+	ReplaceAll bool
+}
+
+// FileChange is one tool call on a file, with the file content around it
+// when it can be reconstructed.
+type FileChange struct {
+	Turn    int    // number of the top-level turn the call was made in
+	Tool    string // tool name: Read, Edit, MultiEdit, Write, NotebookEdit
+	Op      FileOp
+	Edits   []TextEdit // Edit and MultiEdit replacements
+	IsError bool       // the tool reported an error; the change was not applied
+
+	// Before and After are the complete file content around the call,
+	// valid when BeforeKnown and AfterKnown are set.
+	Before, After           string
+	BeforeKnown, AfterKnown bool
+}
+
+// FileHistory is every tool call on one file, in session order.
+type FileHistory struct {
+	Path    string
+	Changes []FileChange
+}
+
+// Turns returns the numbers of the turns that touched the file.
+func (h FileHistory) Turns() []int {
+	var turns []int
+	for _, c := range h.Changes {
+		if len(turns) == 0 || turns[len(turns)-1] != c.Turn {
+			turns = append(turns, c.Turn)
+		}
+	}
+	return turns
+}
+
+// Count returns the number of successful calls with the given operation.
+func (h FileHistory) Count(op FileOp) int {
+	n := 0
+	for _, c := range h.Changes {
+		if c.Op == op && !c.IsError {
+			n++
+		}
+	}
+	return n
+}
+
+// Modified reports whether any call changed the file.
+func (h FileHistory) Modified() bool {
+	return h.Count(FileEdit)+h.Count(FileWrite) > 0
+}
+
+// ContentAt returns the file content at the end of the given turn, and
+// whether it is known: the last full Read or Write up to that turn must be
+// followed only by edits that apply cleanly.
+func (h FileHistory) ContentAt(turn int) (string, bool) {
+	content, known := "", false
+	for _, c := range h.Changes {
+		if c.Turn > turn {
+			break
+		}
+		content, known = c.After, c.AfterKnown
+	}
+	return content, known
+}
+
+// Final returns the file content at the end of the session, if known.
+func (h FileHistory) Final() (string, bool) {
+	if len(h.Changes) == 0 {
+		return "", false
+	}
+	last := h.Changes[len(h.Changes)-1]
+	return last.After, last.AfterKnown
+}
+
+// FileHistories returns the history of every file read or modified by a
+// tool call in the session, including calls made by subagents, sorted by
+// path.
+func FileHistories(sess *Session) []FileHistory {
+	b := fileHistoryBuilder{files: map[string]*fileState{}}
+	for _, t := range sess.Turns {
+		b.addTurn(t, t.Number)
+	}
+
+	histories := make([]FileHistory, 0, len(b.files))
+	for path, st := range b.files {
+		histories = append(histories, FileHistory{Path: path, Changes: st.changes})
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].Path < histories[j].Path
+	})
+	return histories
+}
+
+// FindFileHistory returns the history whose path equals query or ends with
+// it (e.g. "ui/app.go"). When several paths end with query, it returns nil
+// and the candidates.
+func FindFileHistory(histories []FileHistory, query string) (*FileHistory, []string) {
+	var matches []string
+	var found *FileHistory
+	for i, h := range histories {
+		if h.Path == query {
+			return &histories[i], nil
+		}
+		if strings.HasSuffix(h.Path, "/"+strings.TrimPrefix(query, "/")) {
+			matches = append(matches, h.Path)
+			found = &histories[i]
+		}
+	}
+	if len(matches) == 1 {
+		return found, nil
+	}
+	return nil, matches
+}
+
+// fileState tracks the content of a file while replaying tool calls.
+type fileState struct {
+	content string
+	known   bool
+	changes []FileChange
+}
+
+type fileHistoryBuilder struct {
+	files map[string]*fileState
+}
+
+func (b *fileHistoryBuilder) state(path string) *fileState {
+	st, ok := b.files[path]
+	if !ok {
+		st = &fileState{}
+		b.files[path] = st
+	}
+	return st
+}
+
+// addTurn replays the file tool calls of a turn, attributing them (and
+// those of its subagents) to the top-level turn number.
+func (b *fileHistoryBuilder) addTurn(t Turn, number int) {
+	results := map[string]Block{}
+	for _, block := range t.Blocks {
+		if block.Type == BlockToolResult {
+			results[block.ToolID] = block
+		}
+	}
+
+	for _, block := range t.Blocks {
+		if block.Type != BlockToolUse {
+			continue
+		}
+		for _, sub := range block.Sidechain {
+			b.addTurn(sub, number)
+		}
+
+		path := toolFilePath(block)
+		if path == "" {
+			continue
+		}
+		result, hasResult := results[block.ToolID]
+		change := FileChange{
+			Turn:    number,
+			Tool:    block.ToolName,
+			IsError: hasResult && result.IsError,
+		}
+
+		switch block.ToolName {
+		case "Read":
+			change.Op = FileRead
+		case "Edit":
+			change.Op = FileEdit
+			change.Edits = []TextEdit{textEdit(block.ToolInput)}
+		case "MultiEdit":
+			change.Op = FileEdit
+			edits, _ := block.ToolInput["edits"].([]interface{})
+			for _, e := range edits {
+				if m, ok := e.(map[string]interface{}); ok {
+					change.Edits = append(change.Edits, textEdit(m))
+				}
+			}
+		case "Write":
+			change.Op = FileWrite
+		case "NotebookEdit":
+			change.Op = FileEdit
+		default:
+			continue
+		}
+
+		st := b.state(path)
+		change.Before, change.BeforeKnown = st.content, st.known
+		if !change.IsError {
+			st.apply(block, change, result.Text, hasResult)
+		}
+		change.After, change.AfterKnown = st.content, st.known
+		st.changes = append(st.changes, change)
+	}
+}
+
+func textEdit(input map[string]interface{}) TextEdit {
+	oldStr, _ := input["old_string"].(string)
+	newStr, _ := input["new_string"].(string)
+	all, _ := input["replace_all"].(bool)
+	return TextEdit{Old: oldStr, New: newStr, ReplaceAll: all}
+}
+
+// apply updates the tracked content with a successful tool call.
+func (st *fileState) apply(block Block, change FileChange, result string, hasResult bool) {
+	switch block.ToolName {
+	case "Read":
+		if !hasResult {
+			return
+		}
+		if content, ok := ReadResultContent(block.ToolInput, result); ok {
+			st.content, st.known = content, true
+		}
+
+	case "Write":
+		content, _ := block.ToolInput["content"].(string)
+		st.content, st.known = content, true
+
+	case "Edit", "MultiEdit":
+		if !st.known {
+			return
+		}
+		content := st.content
+		for _, e := range change.Edits {
+			switch {
+			case e.Old == "" && content == "":
+				content = e.New
+			case e.Old == "" || !strings.Contains(content, e.Old):
+				// The file changed outside the session
+				st.content, st.known = "", false
+				return
+			case e.ReplaceAll:
+				content = strings.ReplaceAll(content, e.Old, e.New)
+			default:
+				content = strings.Replace(content, e.Old, e.New, 1)
+			}
+		}
+		st.content = content
+
+	default:
+		st.content, st.known = "", false
+	}
+}
+
+var (
+	// readLinePrefix matches the line numbers Read prefixes to each line
+	// ("     1→text" or "     1\ttext").
+	readLinePrefix = regexp.MustCompile(`^ *(\d+)(?:→|\t)`)
+	// systemReminder matches notes appended to tool results.
+	systemReminder = regexp.MustCompile(`(?s)\n*<system-reminder>.*?</system-reminder>\s*`)
+)
+
+// readLineLimit is the number of lines Read returns by default; a result
+// this long may be truncated.
+const readLineLimit = 2000
+
+// ReadResultContent extracts the file content from a Read result, and
+// reports whether it is the whole file: reads with an offset or limit, and
+// results at the default line limit, are partial.
+func ReadResultContent(input map[string]interface{}, result string) (string, bool) {
+	if _, ok := input["offset"]; ok {
+		return "", false
+	}
+	if _, ok := input["limit"]; ok {
+		return "", false
+	}
+
+	result = systemReminder.ReplaceAllString(result, "")
+	if strings.TrimSpace(result) == "" {
+		return "", true
+	}
+
+	// The file ends with a newline if the result does
+	newline := strings.HasSuffix(result, "\n")
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	numbered := true
+	for i, line := range lines {
+		m := readLinePrefix.FindStringSubmatch(line)
+		if m == nil || m[1] != strconv.Itoa(i+1) {
+			numbered = false
+			break
+		}
+	}
+	if !numbered {
+		// Results recorded without line numbers are taken as they are
+		return result, true
+	}
+	if len(lines) >= readLineLimit {
+		return "", false
+	}
+	for i, line := range lines {
+		lines[i] = readLinePrefix.ReplaceAllString(line, "")
+	}
+	// or if the last numbered line is empty: Read numbers the empty string
+	// after the final newline
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		newline = len(lines) > 0
+	}
+	content := strings.Join(lines, "\n")
+	if newline {
+		content += "\n"
+	}
+	return content, true
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func fileTool(id, name string, input map[string]interface{}) Block {
+	return Block{Type: BlockToolUse, ToolName: name, ToolID: id, ToolInput: input}
+}
+
+func fileResult(id, text string, isError bool) Block {
+	return Block{Type: BlockToolResult, ToolID: id, Text: text, IsError: isError}
+}
+
+func TestFileHistories_ReconstructsContent(t *testing.T) {
+	sess := &Session{Turns: []Turn{
+		{Number: 1, Blocks: []Block{
+			fileTool("r1", "Read", map[string]interface{}{"file_path": "/p/main.go"}),
+			fileResult("r1", "     1→package main\n     2→\n     3→func main() {}\n", false),
+		}},
+		{Number: 2, Blocks: []Block{
+			fileTool("e1", "Edit", map[string]interface{}{"file_path": "/p/main.go", "old_string": "func main() {}", "new_string": "func main() {\n\trun()\n}"}),
+			fileResult("e1", "ok", false),
+			fileTool("e2", "Edit", map[string]interface{}{"file_path": "/p/main.go", "old_string": "missing", "new_string": "x"}),
+			fileResult("e2", "String to replace not found", true),
+		}},
+		{Number: 3, Blocks: []Block{
+			fileTool("w1", "Write", map[string]interface{}{"file_path": "/p/new.go", "content": "package p\n"}),
+			fileResult("w1", "ok", false),
+		}},
+	}}
+
+	histories := FileHistories(sess)
+	if len(histories) != 2 || histories[0].Path != "/p/main.go" || histories[1].Path != "/p/new.go" {
+		t.Fatalf("histories = %+v", histories)
+	}
+
+	mainGo := histories[0]
+	if got := mainGo.Turns(); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Turns() = %v", got)
+	}
+	if mainGo.Count(FileEdit) != 1 || mainGo.Count(FileRead) != 1 {
+		t.Errorf("counts: %d edits, %d reads", mainGo.Count(FileEdit), mainGo.Count(FileRead))
+	}
+	if !mainGo.Changes[2].IsError {
+		t.Error("failed edit should be marked")
+	}
+
+	if content, ok := mainGo.ContentAt(1); !ok || content != "package main\n\nfunc main() {}\n" {
+		t.Errorf("ContentAt(1) = %q, %v", content, ok)
+	}
+	want := "package main\n\nfunc main() {\n\trun()\n}\n"
+	if content, ok := mainGo.ContentAt(2); !ok || content != want {
+		t.Errorf("ContentAt(2) = %q, %v", content, ok)
+	}
+	if content, ok := mainGo.Final(); !ok || content != want {
+		t.Errorf("Final() = %q, %v", content, ok)
+	}
+	if _, ok := histories[1].ContentAt(2); ok {
+		t.Error("new.go should be unknown before it was written")
+	}
+}
+
+func TestFileHistories_UnknownBaseline(t *testing.T) {
+	sess := &Session{Turns: []Turn{
+		{Number: 1, Blocks: []Block{
+			fileTool("r1", "Read", map[string]interface{}{"file_path": "/p/a.go", "offset": 10.0}),
+			fileResult("r1", "    10→x", false),
+			fileTool("e1", "Edit", map[string]interface{}{"file_path": "/p/a.go", "old_string": "x", "new_string": "y"}),
+		}},
+	}}
+	h := FileHistories(sess)[0]
+	if _, ok := h.Final(); ok {
+		t.Error("an edit after a partial read should leave the content unknown")
+	}
+	if len(h.Changes[1].Edits) != 1 || h.Changes[1].Edits[0].New != "y" {
+		t.Errorf("edits = %+v", h.Changes[1].Edits)
+	}
+}
+
+func TestFileHistories_SubagentCallsUseParentTurn(t *testing.T) {
+	sess := &Session{Turns: []Turn{{Number: 4, Blocks: []Block{{
+		Type:     BlockToolUse,
+		ToolName: "Task",
+		ToolID:   "t1",
+		Sidechain: []Turn{{Number: 1, Blocks: []Block{
+			fileTool("w1", "Write", map[string]interface{}{"file_path": "/p/b.go", "content": "b"}),
+		}}},
+	}}}}}
+	h := FileHistories(sess)
+	if len(h) != 1 || h[0].Changes[0].Turn != 4 {
+		t.Errorf("histories = %+v", h)
+	}
+}
+
+func TestReadResultContent(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  map[string]interface{}
+		result string
+		want   string
+		ok     bool
+	}{
+		{"numbered", nil, "     1\tone\n     2\ttwo\n", "one\ntwo\n", true},
+		{"no final newline", nil, "     1\tone\n     2\ttwo", "one\ntwo", true},
+		{"empty last line", nil, "     1→one\n     2→", "one\n", true},
+		{"empty file", nil, "     1→", "", true},
+		{"reminder", nil, "     1→one\n     2→\n\n<system-reminder>\nbe careful\n</system-reminder>\n", "one\n", true},
+		{"plain", nil, "raw content", "raw content", true},
+		{"limit", map[string]interface{}{"limit": 5.0}, "     1→one", "", false},
+		{"truncated", nil, numberedLines(readLineLimit), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ReadResultContent(tt.input, tt.result)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%6d→line\n", i)
+	}
+	return b.String()
+}
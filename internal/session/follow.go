@@ -0,0 +1,39 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// Follower tracks a session file that is still being written. New records
+// are parsed incrementally and the turns are rebuilt, so the turn in
+// progress grows and new turns are appended as they arrive.
+type Follower struct {
+	path    string
+	tailer  *parser.Tailer
+	records []parser.Record
+}
+
+// NewFollower starts following the session file at path.
+func NewFollower(path string) (*Follower, error) {
+	if path == "" {
+		return nil, fmt.Errorf("follow requires a local session file")
+	}
+	return &Follower{path: path, tailer: parser.NewTailer(path)}, nil
+}
+
+// Poll reads records appended since the last call. It returns a freshly
+// built session when there were any, and nil otherwise. The first call
+// reads the whole file.
+func (f *Follower) Poll() (*Session, error) {
+	records, err := f.tailer.Next()
+	if err != nil {
+		return nil, fmt.Errorf("following session: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	f.records = append(f.records, records...)
+	return buildSession(f.path, f.records), nil
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// errObjectMissing is returned by catFile.read for objects not in the
+// repository.
+var errObjectMissing = errors.New("object not found")
+
+// objectReader reads git objects, named by hash or as <commit>:<path>.
+type objectReader interface {
+	read(object string) ([]byte, error)
+	close() error
+}
+
+// catFile is a long-lived `git cat-file --batch` process, so reading many
+// objects costs one process rather than one per object.
+type catFile struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   *bufio.Reader
+}
+
+func startCatFile(repo string) (*catFile, error) {
+	cmd := exec.Command("git", "-C", repo, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting git cat-file: %w", err)
+	}
+	return &catFile{cmd: cmd, stdin: stdin, out: bufio.NewReaderSize(stdout, 64*1024)}, nil
+}
+
+// read returns the content of an object, named by hash or as
+// <commit>:<path>.
+func (c *catFile) read(object string) ([]byte, error) {
+	if strings.Contains(object, "\n") {
+		return nil, fmt.Errorf("invalid object name %q", object)
+	}
+	if _, err := io.WriteString(c.stdin, object+"\n"); err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+
+	// "<hash> <type> <size>" or "<name> missing"
+	header, err := c.out.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) == 2 && (fields[1] == "missing" || fields[1] == "ambiguous") {
+		return nil, fmt.Errorf("%s: %w", object, errObjectMissing)
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("git cat-file: unexpected header %q", strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: unexpected header %q", strings.TrimSpace(header))
+	}
+
+	// The content is followed by a newline
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(c.out, data); err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	return data[:size], nil
+}
+
+func (c *catFile) close() error {
+	c.stdin.Close()
+	return c.cmd.Wait()
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Trailblaze-work/claude-replay/internal/parser"
@@ -32,12 +34,41 @@ type sessionMeta struct {
 }
 
 // GitSource implements SessionSource by reading from a claude-sessions git branch.
+// Objects are read through one long-lived git process, and the session
+// metadata is cached until the branch moves; Close stops the process.
 type GitSource struct {
 	RepoPath string
+
+	mu       sync.Mutex
+	objects  objectReader           // git cat-file process, nil until needed
+	listedAt string                 // branch commit metas was read at
+	metas    []sessionMeta          // cached .meta.json files of listedAt
+	metaBlob map[string]sessionMeta // parsed .meta.json files by blob hash
+}
+
+// Close stops the git process reading objects. The source can still be
+// used; the process is restarted when needed.
+func (s *GitSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		return nil
+	}
+	err := s.objects.close()
+	s.objects = nil
+	return err
 }
 
 func (s *GitSource) git(args ...string) ([]byte, error) {
+	return s.gitInput(nil, args...)
+}
+
+// gitInput runs git with stdin read from input.
+func (s *GitSource) gitInput(input []byte, args ...string) ([]byte, error) {
 	cmd := exec.Command("git", append([]string{"-C", s.RepoPath}, args...)...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -48,12 +79,7 @@ func (s *GitSource) git(args ...string) ([]byte, error) {
 }
 
 func (s *GitSource) ListProjects() ([]Project, error) {
-	// Verify the branch exists
-	if _, err := s.git("rev-parse", "--verify", gitBranch); err != nil {
-		return nil, fmt.Errorf("branch %q not found: %w", gitBranch, err)
-	}
-
-	// Count sessions from ls-tree
+	// Count sessions from the metadata, failing if there is no branch
 	metas, err := s.listMetaFiles()
 	if err != nil {
 		return nil, err
@@ -120,8 +146,7 @@ func (s *GitSource) ListSessions(_ string) ([]SessionInfo, error) {
 }
 
 func (s *GitSource) LoadSession(sessionID string) (*Session, error) {
-	objPath := fmt.Sprintf("%s:sessions/%s.jsonl.gz", gitBranch, sessionID)
-	data, err := s.git("show", objPath)
+	data, err := s.readFile("sessions/" + sessionID + ".jsonl.gz")
 	if err != nil {
 		return nil, fmt.Errorf("reading session %s from git: %w", sessionID, err)
 	}
@@ -144,14 +169,8 @@ func (s *GitSource) LoadSession(sessionID string) (*Session, error) {
 	}
 
 	sess := &Session{ID: sessionID}
-	turns := segmentTurns(records, sess)
-	sess.Turns = turns
-
-	if len(turns) > 0 {
-		sess.StartTime = turns[0].Timestamp
-		sess.EndTime = turns[len(turns)-1].Timestamp
-	}
-
+	sess.Turns = segmentTurns(records, sess)
+	sess.setTimes()
 	return sess, nil
 }
 
@@ -186,35 +205,224 @@ func (s *GitSource) FindSession(query string) (*SessionInfo, error) {
 }
 
 // listMetaFiles reads all .meta.json files from the claude-sessions branch.
+// They are cached by the commit the branch points to and only re-read when
+// it moves, and then only the ones that changed.
 func (s *GitSource) listMetaFiles() ([]sessionMeta, error) {
-	// List all files under sessions/
-	out, err := s.git("ls-tree", "--name-only", gitBranch, "sessions/")
+	commit, err := s.branchCommit()
 	if err != nil {
 		return nil, fmt.Errorf("listing sessions: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	var metas []sessionMeta
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if commit == s.listedAt {
+		return append([]sessionMeta(nil), s.metas...), nil
+	}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if !strings.HasSuffix(line, ".meta.json") {
+	// List all files under sessions/ with their blob hashes
+	out, err := s.git("ls-tree", "-z", commit, "sessions/")
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	var metas []sessionMeta
+	blobs := map[string]sessionMeta{}
+	for _, line := range strings.Split(string(out), "\x00") {
+		info, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 || fields[1] != "blob" || !strings.HasSuffix(path, ".meta.json") {
 			continue
 		}
+		blob := fields[2]
 
-		objPath := fmt.Sprintf("%s:%s", gitBranch, line)
-		data, err := s.git("show", objPath)
+		m, ok := s.metaBlob[blob]
+		if !ok {
+			data, err := s.readObjectLocked(blob)
+			if err != nil {
+				return nil, fmt.Errorf("listing sessions: %w", err)
+			}
+			if err := json.Unmarshal(data, &m); err != nil {
+				continue
+			}
+		}
+		blobs[blob] = m
+		metas = append(metas, m)
+	}
+
+	s.listedAt, s.metas, s.metaBlob = commit, metas, blobs
+	return append([]sessionMeta(nil), metas...), nil
+}
+
+// branchCommit returns the hash of the commit the claude-sessions branch
+// points to.
+func (s *GitSource) branchCommit() (string, error) {
+	out, err := s.git("rev-parse", "--verify", "--quiet", "refs/heads/"+gitBranch+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("branch %q not found", gitBranch)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readFile returns a file of the claude-sessions branch, by path from the
+// root of its tree, or an error wrapping errObjectMissing if there is none
+// or no branch.
+func (s *GitSource) readFile(path string) ([]byte, error) {
+	commit, err := s.branchCommit()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, errObjectMissing)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readObjectLocked(commit + ":" + path)
+}
+
+// readObjectLocked reads an object through the cat-file process, starting
+// it if needed. s.mu must be held.
+func (s *GitSource) readObjectLocked(object string) ([]byte, error) {
+	if s.objects == nil {
+		c, err := startCatFile(s.RepoPath)
 		if err != nil {
-			continue
+			return nil, err
 		}
+		s.objects = c
+	}
+	data, err := s.objects.read(object)
+	if err != nil && !errors.Is(err, errObjectMissing) {
+		// The process may be out of step or gone; start afresh next time
+		s.objects.close()
+		s.objects = nil
+	}
+	return data, err
+}
 
-		var m sessionMeta
-		if err := json.Unmarshal(data, &m); err != nil {
-			continue
+// annotationsPath is where the annotations of a session are kept on the
+// branch, next to its .meta.json.
+func annotationsPath(sessionID string) string {
+	return "sessions/" + sessionID + ".annotations.json"
+}
+
+func (s *GitSource) LoadAnnotations(sessionID string) (*Annotations, error) {
+	data, err := s.readFile(annotationsPath(sessionID))
+	if errors.Is(err, errObjectMissing) {
+		return &Annotations{SessionID: sessionID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading annotations from git: %w", err)
+	}
+	return decodeAnnotations(sessionID, data)
+}
+
+func (s *GitSource) SaveAnnotations(a *Annotations) error {
+	data, err := encodeAnnotations(a)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{annotationsPath(a.SessionID): data}
+	return s.commitFiles(files, "Annotate session "+a.SessionID)
+}
+
+// commitFiles commits files, by path from the root of the tree, on top of
+// the claude-sessions branch, creating it if needed. The commit is built
+// with plumbing commands, so the worktree, the index and the checked-out
+// branch are left alone.
+func (s *GitSource) commitFiles(files map[string][]byte, message string) error {
+	ref := "refs/heads/" + gitBranch
+	parent := ""
+	if out, err := s.git("rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+		parent = strings.TrimSpace(string(out))
+	}
+
+	blobs := map[string]string{}
+	for path, data := range files {
+		out, err := s.gitInput(data, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return fmt.Errorf("storing %s: %w", path, err)
 		}
+		blobs[path] = strings.TrimSpace(string(out))
+	}
 
-		metas = append(metas, m)
+	base := ""
+	if parent != "" {
+		base = parent + "^{tree}"
+	}
+	tree, err := s.writeTree(base, blobs)
+	if err != nil {
+		return err
+	}
+	// Files written again unchanged make no commit
+	if parent != "" {
+		if out, err := s.git("rev-parse", base); err == nil && strings.TrimSpace(string(out)) == tree {
+			return nil
+		}
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	out, err := s.git(args...)
+	if err != nil {
+		return fmt.Errorf("committing to %s: %w", gitBranch, err)
 	}
+	commit := strings.TrimSpace(string(out))
 
-	return metas, nil
+	// Fail rather than drop a commit made to the branch in the meantime
+	if _, err := s.git("update-ref", "-m", message, ref, commit, parent); err != nil {
+		return fmt.Errorf("updating %s: %w", gitBranch, err)
+	}
+	return nil
+}
+
+// writeTree writes the tree base (empty if "") with the blobs added or
+// replaced, by path relative to it, and returns the new tree's hash.
+func (s *GitSource) writeTree(base string, blobs map[string]string) (string, error) {
+	entries := map[string]string{} // name -> mktree line
+	subtrees := map[string]string{}
+	if base != "" {
+		out, err := s.git("ls-tree", "-z", base)
+		if err != nil {
+			return "", fmt.Errorf("reading tree: %w", err)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+			info, name, ok := strings.Cut(line, "\t")
+			if !ok {
+				continue
+			}
+			entries[name] = line
+			if fields := strings.Fields(info); len(fields) == 3 && fields[1] == "tree" {
+				subtrees[name] = fields[2]
+			}
+		}
+	}
+
+	nested := map[string]map[string]string{}
+	for path, blob := range blobs {
+		dir, rest, ok := strings.Cut(path, "/")
+		if !ok {
+			entries[path] = "100644 blob " + blob + "\t" + path
+			continue
+		}
+		if nested[dir] == nil {
+			nested[dir] = map[string]string{}
+		}
+		nested[dir][rest] = blob
+	}
+	for dir, files := range nested {
+		tree, err := s.writeTree(subtrees[dir], files)
+		if err != nil {
+			return "", err
+		}
+		entries[dir] = "040000 tree " + tree + "\t" + dir
+	}
+
+	var input bytes.Buffer
+	for _, line := range entries {
+		input.WriteString(line)
+		input.WriteByte(0)
+	}
+	out, err := s.gitInput(input.Bytes(), "mktree", "-z")
+	if err != nil {
+		return "", fmt.Errorf("writing tree: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
 }
@@ -11,22 +11,30 @@ import (
 	"time"
 )
 
+// setGitIdentity sets the author and committer git uses for the rest of the
+// test, in this process and the git commands it runs.
+func setGitIdentity(t *testing.T) {
+	t.Helper()
+	for _, v := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(v, "Test")
+	}
+	for _, v := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(v, "test@test.com")
+	}
+}
+
 // setupTestGitRepo creates a temporary git repo with a claude-sessions branch
-// containing test session data.
+// containing test session data. The git identity is set for the rest of
+// the test, so tests can commit to the branch.
 func setupTestGitRepo(t *testing.T) string {
 	t.Helper()
 
 	dir := t.TempDir()
+	setGitIdentity(t)
 
 	run := func(args ...string) {
 		t.Helper()
 		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
-		cmd.Env = append(os.Environ(),
-			"GIT_AUTHOR_NAME=Test",
-			"GIT_AUTHOR_EMAIL=test@test.com",
-			"GIT_COMMITTER_NAME=Test",
-			"GIT_COMMITTER_EMAIL=test@test.com",
-		)
 		out, err := cmd.CombinedOutput()
 		if err != nil {
 			t.Fatalf("git %v failed: %v\n%s", args, err, out)
@@ -348,3 +356,71 @@ func TestGitSource_NoBranch(t *testing.T) {
 		t.Fatal("expected error when claude-sessions branch does not exist")
 	}
 }
+
+// countingReader counts the objects read through it.
+type countingReader struct {
+	objectReader
+	reads int
+}
+
+func (r *countingReader) read(object string) ([]byte, error) {
+	r.reads++
+	return r.objectReader.read(object)
+}
+
+func TestGitSource_ListCache(t *testing.T) {
+	repo := setupTestGitRepo(t)
+	c, err := startCatFile(repo)
+	if err != nil {
+		t.Fatalf("startCatFile: %v", err)
+	}
+	objects := &countingReader{objectReader: c}
+	src := &GitSource{RepoPath: repo, objects: objects}
+	t.Cleanup(func() { src.Close() })
+
+	sessions, err := src.ListSessions("")
+	if err != nil || len(sessions) != 2 || objects.reads != 2 {
+		t.Fatalf("ListSessions = %d sessions, %v, after %d reads", len(sessions), err, objects.reads)
+	}
+
+	// While the branch stays put, the cached metadata is used, and callers
+	// get their own copy of it
+	metas, _ := src.listMetaFiles()
+	for i := range metas {
+		metas[i].Slug = "changed"
+	}
+	if _, err := src.FindSession("second-session"); err != nil {
+		t.Errorf("changing a listing changed the cache: %v", err)
+	}
+	info, err := src.FindSession("test-session")
+	if err != nil || objects.reads != 2 {
+		t.Fatalf("FindSession = %v after %d reads, want the cached listing", err, objects.reads)
+	}
+
+	// Once it moves, only the files that changed are read again
+	a := &Annotations{SessionID: info.ID}
+	a.Set(Annotation{Turn: 1})
+	if err := src.SaveAnnotations(a); err != nil {
+		t.Fatalf("SaveAnnotations: %v", err)
+	}
+	objects.reads = 0
+	published := &Session{ID: "33333333-4444-5555-6666-777777777777", Slug: "third", Path: filepath.Join(t.TempDir(), "third.jsonl")}
+	os.WriteFile(published.Path, []byte("{}\n"), 0644)
+	if _, err := src.Publish([]*Session{published}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if sessions, err := src.ListSessions(""); err != nil || len(sessions) != 3 || objects.reads != 1 {
+		t.Errorf("ListSessions after the branch moved = %d sessions, %v, after %d reads, want 3 after 1", len(sessions), err, objects.reads)
+	}
+
+	// A missing object leaves the cat-file process usable
+	if _, err := src.LoadSession("nonexistent-id"); err == nil {
+		t.Fatal("expected error for nonexistent session")
+	}
+	if sess, err := src.LoadSession(info.ID); err != nil || len(sess.Turns) == 0 {
+		t.Errorf("LoadSession after a missing one = %v, %v", sess, err)
+	}
+	if got, err := src.LoadAnnotations(info.ID); err != nil || len(got.Items) != 1 {
+		t.Errorf("LoadAnnotations = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,246 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// indexVersion is bumped whenever the index format or the metadata
+// extracted from session files changes, invalidating existing indexes.
+const indexVersion = 2
+
+// Index is a persistent cache of session metadata, keyed by file path and
+// invalidated per file when its modification time or size changes.
+// A nil *Index is valid and simply scans every file.
+type Index struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]indexEntry
+	dirty   bool
+}
+
+// indexEntry is the cached metadata for one session file.
+type indexEntry struct {
+	ModTime   int64     `json:"mod_time"` // UnixNano
+	Size      int64     `json:"size"`
+	Slug      string    `json:"slug,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	TurnCount int       `json:"turn_count"`
+	FirstTime time.Time `json:"first_time"`
+	LastTime  time.Time `json:"last_time"`
+}
+
+// indexFile is the on-disk form of an Index.
+type indexFile struct {
+	Version  int                   `json:"version"`
+	Sessions map[string]indexEntry `json:"sessions"`
+}
+
+// DefaultIndexPath returns the index location in the user cache directory
+// (e.g. ~/.cache/claude-replay/index.json), falling back to the claude
+// directory when no cache directory is available.
+func DefaultIndexPath(claudeDir string) string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "claude-replay", "index.json")
+	}
+	return filepath.Join(claudeDir, "claude-replay-index.json")
+}
+
+// OpenIndex loads the index stored at path. A missing, unreadable or
+// outdated index file yields an empty index that is rebuilt on use.
+func OpenIndex(path string) *Index {
+	ix := &Index{path: path, entries: map[string]indexEntry{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ix
+	}
+	var f indexFile
+	if err := json.Unmarshal(data, &f); err != nil || f.Version != indexVersion {
+		return ix
+	}
+	if f.Sessions != nil {
+		ix.entries = f.Sessions
+	}
+	return ix
+}
+
+// Path returns where the index is stored.
+func (ix *Index) Path() string {
+	if ix == nil {
+		return ""
+	}
+	return ix.path
+}
+
+// Len returns the number of indexed session files.
+func (ix *Index) Len() int {
+	if ix == nil {
+		return 0
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.entries)
+}
+
+// Reset drops all entries so the next listing rescans every file.
+func (ix *Index) Reset() {
+	if ix == nil {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.entries = map[string]indexEntry{}
+	ix.dirty = true
+}
+
+// Save writes the index to disk if it changed since it was loaded.
+func (ix *Index) Save() error {
+	if ix == nil {
+		return nil
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if !ix.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(indexFile{Version: indexVersion, Sessions: ix.entries})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+
+	// Write atomically so a concurrent reader never sees a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(ix.path), ".index-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), ix.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	ix.dirty = false
+	return nil
+}
+
+// sessionInfo returns metadata for a session file, served from the index
+// when the file is unchanged and scanned (and cached) otherwise.
+func (ix *Index) sessionInfo(path string, fi os.FileInfo) (SessionInfo, error) {
+	if ix != nil {
+		ix.mu.Lock()
+		e, ok := ix.entries[path]
+		ix.mu.Unlock()
+		if ok && e.ModTime == fi.ModTime().UnixNano() && e.Size == fi.Size() {
+			return e.info(path), nil
+		}
+	}
+
+	si, err := scanSessionInfo(path, fi.Size())
+	if err != nil {
+		return si, err
+	}
+
+	if ix != nil {
+		ix.mu.Lock()
+		ix.entries[path] = indexEntry{
+			ModTime:   fi.ModTime().UnixNano(),
+			Size:      fi.Size(),
+			Slug:      si.Slug,
+			Model:     si.Model,
+			TurnCount: si.TurnCount,
+			FirstTime: si.FirstTime,
+			LastTime:  si.LastTime,
+		}
+		ix.dirty = true
+		ix.mu.Unlock()
+	}
+	return si, nil
+}
+
+// isEmpty reports whether a session file is indexed, unchanged, as having
+// no turns. Files missing from the index are not scanned.
+func (ix *Index) isEmpty(path string, fi os.FileInfo) bool {
+	if ix == nil {
+		return false
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	e, ok := ix.entries[path]
+	return ok && e.ModTime == fi.ModTime().UnixNano() && e.Size == fi.Size() && e.TurnCount == 0
+}
+
+// prune drops entries for files in dir that are not in seen.
+func (ix *Index) prune(dir string, seen map[string]bool) {
+	if ix == nil {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for path := range ix.entries {
+		if filepath.Dir(path) == dir && !seen[path] {
+			delete(ix.entries, path)
+			ix.dirty = true
+		}
+	}
+}
+
+func (e indexEntry) info(path string) SessionInfo {
+	return SessionInfo{
+		ID:        sessionIDFromPath(path),
+		Path:      path,
+		Slug:      e.Slug,
+		Model:     e.Model,
+		TurnCount: e.TurnCount,
+		FirstTime: e.FirstTime,
+		LastTime:  e.LastTime,
+		FileSize:  e.Size,
+	}
+}
+
+// scanSessionInfo reads session metadata from a JSONL file with QuickScan.
+func scanSessionInfo(path string, size int64) (SessionInfo, error) {
+	si := SessionInfo{
+		ID:       sessionIDFromPath(path),
+		Path:     path,
+		FileSize: size,
+	}
+
+	slug, model, firstTime, lastTime, turnCount, err := parser.QuickScan(path)
+	if err != nil {
+		return si, err
+	}
+	si.Slug = slug
+	si.Model = model
+	si.TurnCount = turnCount
+
+	if firstTime != "" {
+		if t, err := time.Parse(time.RFC3339Nano, firstTime); err == nil {
+			si.FirstTime = t
+		}
+	}
+	if lastTime != "" {
+		if t, err := time.Parse(time.RFC3339Nano, lastTime); err == nil {
+			si.LastTime = t
+		}
+	}
+
+	return si, nil
+}
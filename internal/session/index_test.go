@@ -0,0 +1,156 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const indexTestSession = `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"slug":"indexed-slug","isSidechain":false}
+{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"hi"}]},"isSidechain":false}
+`
+
+func setupIndexTestDir(t *testing.T) (claudeDir, projDir string) {
+	t.Helper()
+	claudeDir = t.TempDir()
+	projDir = filepath.Join(claudeDir, "projects", "-Users-test-proj")
+	os.MkdirAll(projDir, 0755)
+	os.WriteFile(filepath.Join(projDir, "abcd-1234.jsonl"), []byte(indexTestSession), 0644)
+	return claudeDir, projDir
+}
+
+func TestIndex_ReusesUnchangedFiles(t *testing.T) {
+	_, projDir := setupIndexTestDir(t)
+	ix := OpenIndex(filepath.Join(t.TempDir(), "index.json"))
+
+	sessions, err := discoverSessions(projDir, ix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Slug != "indexed-slug" {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+
+	// Tamper with the cached entry: an unchanged file must be served from it
+	path := filepath.Join(projDir, "abcd-1234.jsonl")
+	e := ix.entries[path]
+	e.Slug = "from-cache"
+	ix.entries[path] = e
+
+	sessions, _ = discoverSessions(projDir, ix)
+	if sessions[0].Slug != "from-cache" {
+		t.Errorf("expected cached slug, got %q", sessions[0].Slug)
+	}
+
+	// Growing the file invalidates the entry
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"type":"user","parentUuid":"a1","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:01:00.000Z","message":{"role":"user","content":"again"},"isSidechain":false}` + "\n")
+	f.Close()
+
+	sessions, _ = discoverSessions(projDir, ix)
+	if sessions[0].Slug != "indexed-slug" || sessions[0].TurnCount != 2 {
+		t.Errorf("expected rescanned metadata, got %+v", sessions[0])
+	}
+}
+
+func TestIndex_PrunesDeletedFiles(t *testing.T) {
+	_, projDir := setupIndexTestDir(t)
+	ix := OpenIndex(filepath.Join(t.TempDir(), "index.json"))
+
+	discoverSessions(projDir, ix)
+	if ix.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", ix.Len())
+	}
+
+	os.Remove(filepath.Join(projDir, "abcd-1234.jsonl"))
+	discoverSessions(projDir, ix)
+	if ix.Len() != 0 {
+		t.Errorf("expected deleted file to be pruned, got %d entries", ix.Len())
+	}
+}
+
+func TestIndex_SaveAndReopen(t *testing.T) {
+	_, projDir := setupIndexTestDir(t)
+	indexPath := filepath.Join(t.TempDir(), "cache", "index.json")
+
+	ix := OpenIndex(indexPath)
+	discoverSessions(projDir, ix)
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	reopened := OpenIndex(indexPath)
+	if reopened.Len() != 1 {
+		t.Fatalf("expected 1 entry after reopen, got %d", reopened.Len())
+	}
+	e := reopened.entries[filepath.Join(projDir, "abcd-1234.jsonl")]
+	if e.Slug != "indexed-slug" || e.TurnCount != 1 || e.FirstTime.IsZero() {
+		t.Errorf("unexpected entry after reopen: %+v", e)
+	}
+}
+
+func TestIndex_IgnoresOutdatedVersion(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	os.WriteFile(indexPath, []byte(`{"version":0,"sessions":{"/x.jsonl":{"size":1}}}`), 0644)
+
+	if ix := OpenIndex(indexPath); ix.Len() != 0 {
+		t.Errorf("expected outdated index to be discarded, got %d entries", ix.Len())
+	}
+}
+
+func TestLocalSource_FindSessionBySlugWithIndex(t *testing.T) {
+	claudeDir, _ := setupIndexTestDir(t)
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	src := &LocalSource{ClaudeDir: claudeDir, Index: OpenIndex(indexPath)}
+
+	info, err := src.FindSession("indexed-slug")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ID != "abcd-1234" {
+		t.Errorf("ID: got %q, want %q", info.ID, "abcd-1234")
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("expected index to be saved: %v", err)
+	}
+
+	sess, err := src.LoadSession("indexed-slug")
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+	if len(sess.Turns) != 1 {
+		t.Errorf("expected 1 turn, got %d", len(sess.Turns))
+	}
+
+	if _, err := src.FindSession("missing"); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+func TestLocalSource_ListProjectsSkipsScan(t *testing.T) {
+	claudeDir, projDir := setupIndexTestDir(t)
+	os.WriteFile(filepath.Join(projDir, "empty.jsonl"), nil, 0644)
+	ix := OpenIndex(filepath.Join(t.TempDir(), "index.json"))
+	src := &LocalSource{ClaudeDir: claudeDir, Index: ix}
+
+	// A cold index counts files without scanning them
+	projects, err := src.ListProjects()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Sessions != 2 {
+		t.Fatalf("unexpected projects: %+v", projects)
+	}
+	if ix.Len() != 0 {
+		t.Errorf("expected no files scanned, got %d entries", ix.Len())
+	}
+
+	// Once listed, files without turns drop out of the count
+	if _, err := src.ListSessions(projDir); err != nil {
+		t.Fatalf("ListSessions error: %v", err)
+	}
+	projects, _ = src.ListProjects()
+	if projects[0].Sessions != 1 {
+		t.Errorf("expected 1 session after indexing, got %d", projects[0].Sessions)
+	}
+}
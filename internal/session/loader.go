@@ -35,6 +35,13 @@ type SessionInfo struct {
 
 // DiscoverProjects finds all Claude Code projects in the given claude directory.
 func DiscoverProjects(claudeDir string) ([]Project, error) {
+	return discoverProjects(claudeDir, nil)
+}
+
+// discoverProjects finds all projects. With an index, session counts leave
+// out files already indexed as having no turns, like ListSessions does;
+// files not indexed yet are counted without being scanned.
+func discoverProjects(claudeDir string, ix *Index) ([]Project, error) {
 	projectsDir := filepath.Join(claudeDir, "projects")
 
 	entries, err := os.ReadDir(projectsDir)
@@ -49,7 +56,7 @@ func DiscoverProjects(claudeDir string) ([]Project, error) {
 		}
 
 		dirPath := filepath.Join(projectsDir, entry.Name())
-		sessions, lastUsed := countSessions(dirPath)
+		sessions, lastUsed := countSessions(dirPath, ix)
 		if sessions == 0 {
 			continue
 		}
@@ -74,52 +81,40 @@ func DiscoverProjects(claudeDir string) ([]Project, error) {
 
 // DiscoverSessions finds all session files in a project directory.
 func DiscoverSessions(projectDir string) ([]SessionInfo, error) {
+	return discoverSessions(projectDir, nil)
+}
+
+// discoverSessions finds all sessions in a project directory, reading
+// metadata through ix (which may be nil).
+func discoverSessions(projectDir string, ix *Index) ([]SessionInfo, error) {
 	entries, err := os.ReadDir(projectDir)
 	if err != nil {
 		return nil, fmt.Errorf("reading project directory: %w", err)
 	}
 
 	var sessions []SessionInfo
+	seen := map[string]bool{}
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
 			continue
 		}
 
 		path := filepath.Join(projectDir, entry.Name())
-		id := strings.TrimSuffix(entry.Name(), ".jsonl")
 
 		info, err := entry.Info()
 		if err != nil {
 			continue
 		}
+		seen[path] = true
 
-		slug, model, firstTime, lastTime, turnCount, err := parser.QuickScan(path)
-		if err != nil || turnCount == 0 {
+		si, err := ix.sessionInfo(path, info)
+		if err != nil || si.TurnCount == 0 {
 			continue
 		}
 
-		si := SessionInfo{
-			ID:        id,
-			Path:      path,
-			Slug:      slug,
-			Model:     model,
-			TurnCount: turnCount,
-			FileSize:  info.Size(),
-		}
-
-		if firstTime != "" {
-			if t, err := time.Parse(time.RFC3339Nano, firstTime); err == nil {
-				si.FirstTime = t
-			}
-		}
-		if lastTime != "" {
-			if t, err := time.Parse(time.RFC3339Nano, lastTime); err == nil {
-				si.LastTime = t
-			}
-		}
-
 		sessions = append(sessions, si)
 	}
+	ix.prune(projectDir, seen)
 
 	// Sort by last time (most recent first)
 	sort.Slice(sessions, func(i, j int) bool {
@@ -131,32 +126,23 @@ func DiscoverSessions(projectDir string) ([]SessionInfo, error) {
 
 // FindSessionByID searches all projects for a session with the given ID or slug.
 func FindSessionByID(claudeDir, query string) (string, error) {
-	projectsDir := filepath.Join(claudeDir, "projects")
+	path, err := findSessionFile(claudeDir, query)
+	if err != nil || path != "" {
+		return path, err
+	}
 
-	// First try direct UUID match
+	projectsDir := filepath.Join(claudeDir, "projects")
 	entries, err := os.ReadDir(projectsDir)
 	if err != nil {
 		return "", err
 	}
 
-	// Try as a full path
-	if _, err := os.Stat(query); err == nil && strings.HasSuffix(query, ".jsonl") {
-		return query, nil
-	}
-
+	// Try slug match (slower - needs to scan file content)
 	for _, projEntry := range entries {
 		if !projEntry.IsDir() {
 			continue
 		}
 		projDir := filepath.Join(projectsDir, projEntry.Name())
-
-		// Try exact UUID match
-		candidate := filepath.Join(projDir, query+".jsonl")
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate, nil
-		}
-
-		// Try prefix UUID match and slug match
 		sessEntries, err := os.ReadDir(projDir)
 		if err != nil {
 			continue
@@ -165,22 +151,46 @@ func FindSessionByID(claudeDir, query string) (string, error) {
 			if sessEntry.IsDir() || !strings.HasSuffix(sessEntry.Name(), ".jsonl") {
 				continue
 			}
-			id := strings.TrimSuffix(sessEntry.Name(), ".jsonl")
 			path := filepath.Join(projDir, sessEntry.Name())
-
-			// Prefix match on UUID
-			if strings.HasPrefix(id, query) {
+			slug, _, _, _, _, err := parser.QuickScan(path)
+			if err == nil && slug == query {
 				return path, nil
 			}
 		}
 	}
 
-	// Try slug match (slower - needs to scan file content)
+	return "", fmt.Errorf("session not found: %s", query)
+}
+
+// findSessionFile resolves a file path, full UUID or UUID prefix to a
+// session file. It returns an empty path if nothing matched, leaving slug
+// lookups (which need file contents) to the caller.
+func findSessionFile(claudeDir, query string) (string, error) {
+	projectsDir := filepath.Join(claudeDir, "projects")
+
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Try as a full path
+	if _, err := os.Stat(query); err == nil && strings.HasSuffix(query, ".jsonl") {
+		return query, nil
+	}
+
 	for _, projEntry := range entries {
 		if !projEntry.IsDir() {
 			continue
 		}
 		projDir := filepath.Join(projectsDir, projEntry.Name())
+
+		// Try exact UUID match
+		candidate := filepath.Join(projDir, query+".jsonl")
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+
+		// Try prefix UUID match
 		sessEntries, err := os.ReadDir(projDir)
 		if err != nil {
 			continue
@@ -189,18 +199,20 @@ func FindSessionByID(claudeDir, query string) (string, error) {
 			if sessEntry.IsDir() || !strings.HasSuffix(sessEntry.Name(), ".jsonl") {
 				continue
 			}
+			id := strings.TrimSuffix(sessEntry.Name(), ".jsonl")
 			path := filepath.Join(projDir, sessEntry.Name())
-			slug, _, _, _, _, err := parser.QuickScan(path)
-			if err == nil && slug == query {
+
+			// Prefix match on UUID
+			if strings.HasPrefix(id, query) {
 				return path, nil
 			}
 		}
 	}
 
-	return "", fmt.Errorf("session not found: %s", query)
+	return "", nil
 }
 
-func countSessions(dirPath string) (int, time.Time) {
+func countSessions(dirPath string, ix *Index) (int, time.Time) {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
 		return 0, time.Time{}
@@ -209,13 +221,20 @@ func countSessions(dirPath string) (int, time.Time) {
 	count := 0
 	var latest time.Time
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".jsonl") {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
 			count++
-			if info, err := entry.Info(); err == nil {
-				if info.ModTime().After(latest) {
-					latest = info.ModTime()
-				}
-			}
+			continue
+		}
+		if ix.isEmpty(filepath.Join(dirPath, entry.Name()), info) {
+			continue
+		}
+		count++
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
 		}
 	}
 	return count, latest
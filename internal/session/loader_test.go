@@ -152,7 +152,7 @@ func TestFindSessionByID(t *testing.T) {
 
 func TestCountSessions_Empty(t *testing.T) {
 	dir := t.TempDir()
-	count, latest := countSessions(dir)
+	count, latest := countSessions(dir, nil)
 	if count != 0 {
 		t.Errorf("count: got %d, want 0", count)
 	}
@@ -172,7 +172,7 @@ func TestCountSessions_MixedFiles(t *testing.T) {
 	// Create a subdirectory (should be ignored)
 	os.MkdirAll(filepath.Join(dir, "subdir"), 0755)
 
-	count, _ := countSessions(dir)
+	count, _ := countSessions(dir, nil)
 	if count != 2 {
 		t.Errorf("count: got %d, want 2", count)
 	}
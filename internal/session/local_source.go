@@ -1,63 +1,109 @@
 package session
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
-	"time"
-
-	"github.com/Trailblaze-work/claude-replay/internal/parser"
+	"sync"
 )
 
 // LocalSource implements SessionSource using the local filesystem (~/.claude).
 type LocalSource struct {
 	ClaudeDir string
+	Index     *Index // optional metadata cache; nil scans every file
+
+	saveWarning sync.Once
 }
 
 func (s *LocalSource) ListProjects() ([]Project, error) {
-	return DiscoverProjects(s.ClaudeDir)
+	projects, err := discoverProjects(s.ClaudeDir, s.Index)
+	s.saveIndex()
+	return projects, err
 }
 
 func (s *LocalSource) ListSessions(projectDirPath string) ([]SessionInfo, error) {
-	return DiscoverSessions(projectDirPath)
+	sessions, err := discoverSessions(projectDirPath, s.Index)
+	s.saveIndex()
+	return sessions, err
 }
 
 func (s *LocalSource) LoadSession(sessionID string) (*Session, error) {
-	path, err := FindSessionByID(s.ClaudeDir, sessionID)
+	if s.Index == nil {
+		path, err := FindSessionByID(s.ClaudeDir, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return LoadSession(path)
+	}
+
+	info, err := s.FindSession(sessionID)
 	if err != nil {
 		return nil, err
 	}
-	return LoadSession(path)
+	return LoadSession(info.Path)
 }
 
 func (s *LocalSource) FindSession(query string) (*SessionInfo, error) {
-	path, err := FindSessionByID(s.ClaudeDir, query)
+	if s.Index == nil {
+		path, err := FindSessionByID(s.ClaudeDir, query)
+		if err != nil {
+			return nil, err
+		}
+		return s.statSessionInfo(path), nil
+	}
+
+	path, err := findSessionFile(s.ClaudeDir, query)
 	if err != nil {
 		return nil, err
 	}
+	if path != "" {
+		return s.statSessionInfo(path), nil
+	}
 
-	id := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	// Slug match through the index instead of scanning every file
+	projects, err := s.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		sessions, err := s.ListSessions(p.DirPath)
+		if err != nil {
+			continue
+		}
+		for i := range sessions {
+			if sessions[i].Slug == query {
+				return &sessions[i], nil
+			}
+		}
+	}
 
-	// Quick scan for metadata
-	slug, model, firstTime, lastTime, turnCount, _ := parser.QuickScan(path)
+	return nil, fmt.Errorf("session not found: %s", query)
+}
 
-	info := &SessionInfo{
-		ID:        id,
-		Path:      path,
-		Slug:      slug,
-		Model:     model,
-		TurnCount: turnCount,
+// statSessionInfo returns metadata for a session file, via the index when
+// one is configured. Scan errors leave the metadata fields empty.
+func (s *LocalSource) statSessionInfo(path string) *SessionInfo {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return &SessionInfo{ID: sessionIDFromPath(path), Path: path}
 	}
+	info, _ := s.Index.sessionInfo(path, fi)
+	s.saveIndex()
+	return &info
+}
 
-	if firstTime != "" {
-		if t, err := time.Parse(time.RFC3339Nano, firstTime); err == nil {
-			info.FirstTime = t
-		}
-	}
-	if lastTime != "" {
-		if t, err := time.Parse(time.RFC3339Nano, lastTime); err == nil {
-			info.LastTime = t
-		}
+// saveIndex writes the index back to disk. A failure only costs the next
+// run a rescan, so it is reported once on stderr rather than failing the
+// listing that triggered it.
+func (s *LocalSource) saveIndex() {
+	if err := s.Index.Save(); err != nil {
+		s.saveWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "warning: saving session index %s: %v\n", s.Index.Path(), err)
+		})
 	}
+}
 
-	return info, nil
+func sessionIDFromPath(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".jsonl")
 }
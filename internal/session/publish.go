@@ -0,0 +1,135 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PublishedSession describes a session written to the claude-sessions
+// branch by Publish.
+type PublishedSession struct {
+	ID             string
+	Slug           string
+	Turns          int
+	CompressedSize int64
+}
+
+// Publish commits local sessions to the claude-sessions branch, creating
+// it if needed: each session file gzipped as sessions/<id>.jsonl.gz, next
+// to a sessions/<id>.meta.json describing it. Sessions published before are
+// replaced. All sessions go in one commit, made with plumbing commands
+// that leave the worktree and index alone.
+func (s *GitSource) Publish(sessions []*Session) ([]PublishedSession, error) {
+	files := map[string][]byte{}
+	var published []PublishedSession
+	for _, sess := range sessions {
+		if sess.ID == "" {
+			return nil, fmt.Errorf("session %s has no ID", sess.Path)
+		}
+		data, err := os.ReadFile(sess.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading session %s: %w", sess.ID, err)
+		}
+		var gz bytes.Buffer
+		w := gzip.NewWriter(&gz)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("compressing session %s: %w", sess.ID, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("compressing session %s: %w", sess.ID, err)
+		}
+
+		meta, err := json.MarshalIndent(newSessionMeta(sess, int64(gz.Len())), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding metadata of %s: %w", sess.ID, err)
+		}
+		files["sessions/"+sess.ID+".jsonl.gz"] = gz.Bytes()
+		files["sessions/"+sess.ID+".meta.json"] = append(meta, '\n')
+		published = append(published, PublishedSession{
+			ID:             sess.ID,
+			Slug:           sess.Slug,
+			Turns:          len(sess.Turns),
+			CompressedSize: int64(gz.Len()),
+		})
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("Publish %d sessions", len(sessions))
+	if len(sessions) == 1 {
+		message = "Publish session " + sessions[0].ID
+	}
+	if err := s.commitFiles(files, message); err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// newSessionMeta describes a session the way .meta.json files do.
+func newSessionMeta(sess *Session, compressedSize int64) sessionMeta {
+	m := sessionMeta{
+		SessionID:      sess.ID,
+		Slug:           sess.Slug,
+		Models:         []string{},
+		ClientVersion:  sess.Version,
+		GitBranch:      sess.GitBranch,
+		UserTurns:      len(sess.Turns),
+		ToolsUsed:      map[string]int{},
+		CompressedSize: compressedSize,
+	}
+	if !sess.StartTime.IsZero() {
+		m.Started = sess.StartTime.Format(time.RFC3339Nano)
+	}
+
+	// Last written record, the session's end as far as the file knows
+	last := sess.EndTime
+	for _, r := range sess.records {
+		if r.Timestamp.After(last) {
+			last = r.Timestamp
+		}
+	}
+	if !last.IsZero() {
+		m.LastUpdated = last.Format(time.RFC3339Nano)
+	}
+
+	seen := map[string]bool{}
+	for _, t := range sess.Turns {
+		if t.Model != "" && !seen[t.Model] {
+			seen[t.Model] = true
+			m.Models = append(m.Models, t.Model)
+		}
+		if hasResponse(t) {
+			m.AssistantTurns++
+		}
+		countTools(t, m.ToolsUsed)
+	}
+	return m
+}
+
+// hasResponse reports whether the assistant answered in a turn.
+func hasResponse(t Turn) bool {
+	for _, b := range t.Blocks {
+		switch b.Type {
+		case BlockText, BlockThinking, BlockToolUse:
+			return true
+		}
+	}
+	return false
+}
+
+// countTools adds the tool calls of a turn, subagents included, to counts.
+func countTools(t Turn, counts map[string]int) {
+	for _, b := range t.Blocks {
+		if b.Type == BlockToolUse {
+			counts[b.ToolName]++
+		}
+		for _, st := range b.Sidechain {
+			countTools(st, counts)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitSource_Publish(t *testing.T) {
+	setGitIdentity(t)
+	repo := t.TempDir()
+	for _, args := range [][]string{{"init", "-b", "main"}, {"commit", "--allow-empty", "-m", "initial"}} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	lines := []string{
+		`{"type":"user","uuid":"u1","sessionId":"pub-1","slug":"publish-me","version":"2.1.0","timestamp":"2026-03-01T09:00:00.000Z","message":{"role":"user","content":"run the tests"}}`,
+		`{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"pub-1","timestamp":"2026-03-01T09:00:01.000Z","message":{"model":"claude-sonnet-4-6","id":"m1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}`,
+		`{"type":"user","parentUuid":"a1","uuid":"r1","sessionId":"pub-1","timestamp":"2026-03-01T09:00:05.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"assistant","parentUuid":"r1","uuid":"a2","sessionId":"pub-1","timestamp":"2026-03-01T09:00:06.000Z","message":{"model":"claude-sonnet-4-6","id":"m2","role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go vet ./..."}}]}}`,
+		`{"type":"user","parentUuid":"a2","uuid":"u2","sessionId":"pub-1","timestamp":"2026-03-01T09:01:00.000Z","message":{"role":"user","content":"thanks"}}`,
+		`{"type":"assistant","parentUuid":"u2","uuid":"a3","sessionId":"pub-1","timestamp":"2026-03-01T09:01:02.000Z","message":{"model":"claude-opus-4-6","id":"m3","role":"assistant","content":[{"type":"text","text":"done"}]}}`,
+	}
+	path := filepath.Join(t.TempDir(), "pub-1.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+
+	src := &GitSource{RepoPath: repo}
+	published, err := src.Publish([]*Session{sess})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(published) != 1 || published[0].ID != "pub-1" || published[0].CompressedSize == 0 {
+		t.Errorf("published = %+v", published)
+	}
+
+	metas, err := src.listMetaFiles()
+	if err != nil || len(metas) != 1 {
+		t.Fatalf("listMetaFiles = %+v, %v", metas, err)
+	}
+	m := metas[0]
+	if m.Slug != "publish-me" || m.UserTurns != 2 || m.AssistantTurns != 2 || m.ClientVersion != "2.1.0" {
+		t.Errorf("meta = %+v", m)
+	}
+	if strings.Join(m.Models, ",") != "claude-sonnet-4-6,claude-opus-4-6" {
+		t.Errorf("models = %v", m.Models)
+	}
+	if m.ToolsUsed["Bash"] != 2 || m.CompressedSize != published[0].CompressedSize {
+		t.Errorf("tools = %v, compressed size = %d", m.ToolsUsed, m.CompressedSize)
+	}
+	if m.Started != "2026-03-01T09:00:00Z" || m.LastUpdated != "2026-03-01T09:01:02Z" {
+		t.Errorf("started %s, last updated %s", m.Started, m.LastUpdated)
+	}
+
+	loaded, err := src.LoadSession("pub-1")
+	if err != nil || len(loaded.Turns) != 2 {
+		t.Fatalf("LoadSession from the branch = %v, %v", loaded, err)
+	}
+
+	// Publishing again replaces the session rather than adding one, and
+	// makes no commit when nothing changed
+	if _, err := src.Publish([]*Session{sess}); err != nil {
+		t.Fatalf("Publish again: %v", err)
+	}
+	if sessions, err := src.ListSessions(""); err != nil || len(sessions) != 1 {
+		t.Errorf("ListSessions after republishing = %d sessions, %v", len(sessions), err)
+	}
+	count, _ := exec.Command("git", "-C", repo, "rev-list", "--count", gitBranch).Output()
+	if strings.TrimSpace(string(count)) != "1" {
+		t.Errorf("branch has %s commits, want 1", count)
+	}
+
+	status, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if err != nil || len(status) != 0 {
+		t.Errorf("worktree changed: %q, %v", status, err)
+	}
+	head, _ := exec.Command("git", "-C", repo, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if strings.TrimSpace(string(head)) != "main" {
+		t.Errorf("HEAD = %s, want main", head)
+	}
+}
@@ -0,0 +1,410 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RedactRule replaces matches of a pattern. When Group is set only that
+// capture group is replaced, e.g. the value of KEY=value.
+type RedactRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Group   int
+}
+
+// builtinRedactRules detect common credential formats.
+var builtinRedactRules = []RedactRule{
+	{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)},
+	{Name: "aws-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{Name: "anthropic-key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_\-]{20,}`)},
+	{Name: "openai-key", Pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`)},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}`)},
+	{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}\b`)},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+	{Name: "url-password", Pattern: regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.\-]*://[^/\s:@]+:([^/\s@]+)@`), Group: 1},
+	{Name: "secret-assignment", Pattern: regexp.MustCompile(`(?i)\b[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_KEY|PRIVATE_KEY)[A-Z0-9_]*["']?\s*[=:]\s*["']?([^\s"',;]{6,})`), Group: 1},
+}
+
+// entropyCandidate matches runs of characters that random tokens are made of.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_\-]{24,}={0,2}`)
+
+const (
+	// minSecretEntropy is the Shannon entropy (bits per character) above
+	// which a mixed-case alphanumeric run is treated as a secret. Hex
+	// strings such as commit SHAs stay below it.
+	minSecretEntropy = 4.2
+	highEntropyName  = "high-entropy"
+)
+
+// Redactor removes secrets from sessions before they are rendered or
+// exported.
+type Redactor struct {
+	rules       []RedactRule
+	paths       []string // path rules: "dir/" prefixes or glob patterns
+	highEntropy bool
+}
+
+// NewRedactor returns a redactor with the built-in detectors.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		rules:       append([]RedactRule(nil), builtinRedactRules...),
+		highEntropy: true,
+	}
+}
+
+// AddRule adds a user-supplied regex rule.
+func (r *Redactor) AddRule(name, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("redaction rule %s: %w", name, err)
+	}
+	r.rules = append(r.rules, RedactRule{Name: name, Pattern: re})
+	return nil
+}
+
+// AddPathRule hides the content of files matching rule: a directory
+// ending in "/" (matching any path containing it) or a glob matched
+// against the full path and the base name (e.g. "*.env").
+func (r *Redactor) AddPathRule(rule string) {
+	r.paths = append(r.paths, rule)
+}
+
+// redactConfig is the JSON form of user redaction rules.
+type redactConfig struct {
+	Patterns []struct {
+		Name  string `json:"name"`
+		Regex string `json:"regex"`
+	} `json:"patterns"`
+	Paths       []string `json:"paths"`
+	HighEntropy *bool    `json:"high_entropy,omitempty"`
+}
+
+// DefaultRedactRulesPath returns where user redaction rules are looked up
+// (e.g. ~/.config/claude-replay/redact.json).
+func DefaultRedactRulesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-replay", "redact.json")
+}
+
+// LoadRedactor returns a redactor with the built-in detectors plus the
+// rules in path:
+//
+//	{"patterns": [{"name": "internal-host", "regex": "\\w+\\.corp\\.example"}],
+//	 "paths": ["secrets/", "*.env"]}
+//
+// A missing file is not an error unless required is set.
+func LoadRedactor(path string, required bool) (*Redactor, error) {
+	r := NewRedactor()
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return r, nil
+		}
+		return nil, fmt.Errorf("reading redaction rules: %w", err)
+	}
+	var cfg redactConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing redaction rules %s: %w", path, err)
+	}
+	for i, p := range cfg.Patterns {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if err := r.AddRule(name, p.Regex); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range cfg.Paths {
+		r.AddPathRule(p)
+	}
+	if cfg.HighEntropy != nil {
+		r.highEntropy = *cfg.HighEntropy
+	}
+	return r, nil
+}
+
+// RedactionReport counts redactions by rule name.
+type RedactionReport map[string]int
+
+// Total returns the number of redactions.
+func (rep RedactionReport) Total() int {
+	n := 0
+	for _, c := range rep {
+		n += c
+	}
+	return n
+}
+
+// String lists the counts, e.g. "aws-key 2, path:secrets/ 1".
+func (rep RedactionReport) String() string {
+	names := make([]string, 0, len(rep))
+	for name := range rep {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, rep[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func redactedMarker(name string) string {
+	return "[REDACTED:" + name + "]"
+}
+
+// Text redacts a string, recording what was found in rep.
+func (r *Redactor) Text(s string, rep RedactionReport) string {
+	if s == "" {
+		return s
+	}
+	for _, rule := range r.rules {
+		s = redactRule(s, rule, rep)
+	}
+	if r.highEntropy {
+		s = entropyCandidate.ReplaceAllStringFunc(s, func(m string) string {
+			if !looksRandom(m) {
+				return m
+			}
+			rep[highEntropyName]++
+			return redactedMarker(highEntropyName)
+		})
+	}
+	return s
+}
+
+func redactRule(s string, rule RedactRule, rep RedactionReport) string {
+	marker := redactedMarker(rule.Name)
+	matches := rule.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if rule.Group > 0 {
+			start, end = m[2*rule.Group], m[2*rule.Group+1]
+			if start < 0 {
+				continue
+			}
+		}
+		// Leave earlier redactions alone
+		if strings.HasPrefix(s[start:end], "[REDACTED:") {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(marker)
+		last = end
+		rep[rule.Name]++
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// looksRandom reports whether a token has the character mix and entropy
+// of a generated secret rather than an identifier or path.
+func looksRandom(s string) bool {
+	var upper, lower, digit bool
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	return shannonEntropy(strings.TrimRight(s, "=")) >= minSecretEntropy
+}
+
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// matchPathRule returns the path rule hiding file p, if any.
+func (r *Redactor) matchPathRule(p string) (string, bool) {
+	if p == "" {
+		return "", false
+	}
+	slashed := filepath.ToSlash(p)
+	for _, rule := range r.paths {
+		if strings.HasSuffix(rule, "/") {
+			dir := strings.TrimPrefix(rule, "./")
+			if strings.HasPrefix(slashed, dir) || strings.Contains(slashed, "/"+dir) {
+				return rule, true
+			}
+			continue
+		}
+		if ok, _ := path.Match(rule, slashed); ok {
+			return rule, true
+		}
+		if ok, _ := path.Match(rule, path.Base(slashed)); ok {
+			return rule, true
+		}
+	}
+	return "", false
+}
+
+// Session returns a redacted copy of sess; sess itself is not modified.
+func (r *Redactor) Session(sess *Session) (*Session, RedactionReport) {
+	rep := RedactionReport{}
+	out := *sess
+	out.Turns = r.turns(sess.Turns, rep)
+	out.Forks = r.forks(sess.Forks, rep)
+	out.redactor = r
+	return &out, rep
+}
+
+// forks redacts the prompts of the branches at each fork.
+func (r *Redactor) forks(forks []Fork, rep RedactionReport) []Fork {
+	if forks == nil {
+		return nil
+	}
+	out := make([]Fork, len(forks))
+	for i, f := range forks {
+		f.Branches = append([]Branch(nil), f.Branches...)
+		for j := range f.Branches {
+			f.Branches[j].UserText = r.Text(f.Branches[j].UserText, rep)
+		}
+		out[i] = f
+	}
+	return out
+}
+
+func (r *Redactor) turns(turns []Turn, rep RedactionReport) []Turn {
+	if turns == nil {
+		return nil
+	}
+	out := make([]Turn, len(turns))
+	for i, t := range turns {
+		t.UserText = r.Text(t.UserText, rep)
+		t.Blocks = r.blocks(t.Blocks, rep)
+		out[i] = t
+	}
+	return out
+}
+
+func (r *Redactor) blocks(blocks []Block, rep RedactionReport) []Block {
+	// Tool calls on files hidden by a path rule, by tool ID
+	hidden := map[string]string{}
+
+	out := make([]Block, len(blocks))
+	for i, b := range blocks {
+		switch b.Type {
+		case BlockToolUse:
+			if rule, ok := r.matchPathRule(toolFilePath(b)); ok {
+				hidden[b.ToolID] = rule
+			}
+			b.ToolInput = r.toolInput(b.ToolInput, hidden[b.ToolID], rep)
+			if b.ToolInput != nil {
+				if raw, err := json.Marshal(b.ToolInput); err == nil {
+					b.RawInput = string(raw)
+				}
+			} else {
+				b.RawInput = r.Text(b.RawInput, rep)
+			}
+			b.Sidechain = r.turns(b.Sidechain, rep)
+
+		case BlockToolResult:
+			if rule, ok := hidden[b.ToolID]; ok && b.Text != "" {
+				b.Text = redactedMarker("path " + rule)
+				rep["path:"+rule]++
+			} else {
+				b.Text = r.Text(b.Text, rep)
+			}
+
+		default:
+			b.Text = r.Text(b.Text, rep)
+			b.Detail = r.Text(b.Detail, rep)
+		}
+		out[i] = b
+	}
+	return out
+}
+
+// fileContentKeys are tool input fields holding file content, blanked when
+// the file is hidden by a path rule.
+var fileContentKeys = map[string]bool{
+	"content":    true,
+	"old_string": true,
+	"new_string": true,
+	"new_source": true,
+	"edits":      true,
+}
+
+func (r *Redactor) toolInput(input map[string]interface{}, pathRule string, rep RedactionReport) map[string]interface{} {
+	if input == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(input))
+	for k, v := range input {
+		if pathRule != "" && fileContentKeys[k] {
+			out[k] = redactedMarker("path " + pathRule)
+			rep["path:"+pathRule]++
+			continue
+		}
+		out[k] = r.value(v, rep)
+	}
+	return out
+}
+
+func (r *Redactor) value(v interface{}, rep RedactionReport) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.Text(v, rep)
+	case map[string]interface{}:
+		return r.toolInput(v, "", rep)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = r.value(e, rep)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func toolFilePath(b Block) string {
+	for _, key := range []string{"file_path", "notebook_path"} {
+		if p, _ := b.ToolInput[key].(string); p != "" {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,258 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Search fields identify which part of a turn a hit was found in.
+const (
+	FieldUser       = "user"
+	FieldText       = "text"
+	FieldThinking   = "thinking"
+	FieldToolInput  = "input"
+	FieldToolResult = "result"
+)
+
+// snippetRadius is the number of characters of context kept on each side
+// of a match in SearchHit.Snippet.
+const snippetRadius = 40
+
+// SearchQuery is a parsed search expression.
+type SearchQuery struct {
+	Pattern *regexp.Regexp // nil matches every block that passes the filters
+	Tool    string         // only tool_use/tool_result blocks of this tool
+	Field   string         // only this field (FieldUser, FieldText, ...)
+	Error   *bool          // only tool results with this error state
+}
+
+// SearchHit is a single match within a session.
+type SearchHit struct {
+	Project  string // project display name
+	Session  SessionInfo
+	Turn     int    // 1-based turn number in the session
+	Field    string // where the match was found
+	ToolName string // tool name for input/result hits
+	Snippet  string // single-line excerpt around the match
+}
+
+// ParseSearchQuery parses a query such as `tool:Bash error:true timeout`.
+// Recognized filters are tool:<name>, error:<true|false> and in:<field>.
+// The remaining words are matched case-insensitively as a phrase; text
+// wrapped in slashes (/.../) or any text when useRegex is set is compiled
+// as a regular expression.
+func ParseSearchQuery(query string, useRegex bool) (SearchQuery, error) {
+	var q SearchQuery
+	var words []string
+
+	for _, word := range strings.Fields(query) {
+		name, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			words = append(words, word)
+			continue
+		}
+		switch name {
+		case "tool":
+			q.Tool = value
+		case "error":
+			switch value {
+			case "true", "yes":
+				v := true
+				q.Error = &v
+			case "false", "no":
+				v := false
+				q.Error = &v
+			default:
+				return q, fmt.Errorf("invalid error filter %q (want true or false)", value)
+			}
+		case "in":
+			switch value {
+			case FieldUser, FieldText, FieldThinking, FieldToolInput, FieldToolResult:
+				q.Field = value
+			default:
+				return q, fmt.Errorf("unknown field %q (want user, text, thinking, input or result)", value)
+			}
+		default:
+			words = append(words, word)
+		}
+	}
+
+	text := strings.Join(words, " ")
+	if len(text) > 1 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+		text = text[1 : len(text)-1]
+		useRegex = true
+	}
+	if text == "" {
+		return q, nil
+	}
+
+	expr := regexp.QuoteMeta(text)
+	if useRegex {
+		expr = text
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return q, fmt.Errorf("invalid pattern: %w", err)
+	}
+	q.Pattern = re
+	return q, nil
+}
+
+// IsEmpty reports whether the query has neither a pattern nor any filter.
+func (q SearchQuery) IsEmpty() bool {
+	return q.Pattern == nil && q.Tool == "" && q.Field == "" && q.Error == nil
+}
+
+// SearchSession returns every hit for q in a loaded session, including
+// matches inside subagent transcripts (reported against the parent turn).
+// The returned hits have empty Project and Session fields.
+func SearchSession(sess *Session, q SearchQuery) []SearchHit {
+	var hits []SearchHit
+	for _, turn := range sess.Turns {
+		hits = append(hits, searchTurn(turn, turn.Number, q)...)
+	}
+	return hits
+}
+
+func searchTurn(turn Turn, number int, q SearchQuery) []SearchHit {
+	var hits []SearchHit
+	add := func(field, toolName, text string) {
+		if q.Field != "" && q.Field != field {
+			return
+		}
+		if snippet, ok := q.match(text); ok {
+			hits = append(hits, SearchHit{Turn: number, Field: field, ToolName: toolName, Snippet: snippet})
+		}
+	}
+
+	if q.Tool == "" && q.Error == nil {
+		add(FieldUser, "", turn.UserText)
+	}
+
+	toolNames := map[string]string{}
+	for _, block := range turn.Blocks {
+		switch block.Type {
+		case BlockText, BlockThinking:
+			if q.Tool != "" || q.Error != nil {
+				continue
+			}
+			field := FieldText
+			if block.Type == BlockThinking {
+				field = FieldThinking
+			}
+			add(field, "", block.Text)
+
+		case BlockToolUse:
+			toolNames[block.ToolID] = block.ToolName
+			if q.Error == nil && q.matchesTool(block.ToolName) {
+				add(FieldToolInput, block.ToolName, block.RawInput)
+			}
+			for _, sub := range block.Sidechain {
+				hits = append(hits, searchTurn(sub, number, q)...)
+			}
+
+		case BlockToolResult:
+			name := toolNames[block.ToolID]
+			if !q.matchesTool(name) {
+				continue
+			}
+			if q.Error != nil && *q.Error != block.IsError {
+				continue
+			}
+			add(FieldToolResult, name, block.Text)
+		}
+	}
+
+	return hits
+}
+
+func (q SearchQuery) matchesTool(name string) bool {
+	return q.Tool == "" || strings.EqualFold(q.Tool, name)
+}
+
+// match reports whether text matches the pattern and returns a snippet
+// around the first match.
+func (q SearchQuery) match(text string) (string, bool) {
+	if q.Pattern == nil {
+		return snippet(text, 0, 0), true
+	}
+	loc := q.Pattern.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+	return snippet(text, loc[0], loc[1]), true
+}
+
+// snippet extracts a single-line excerpt of text around [start, end).
+func snippet(text string, start, end int) string {
+	from := start - snippetRadius
+	prefix := "…"
+	if from <= 0 {
+		from = 0
+		prefix = ""
+	}
+	to := end + snippetRadius
+	suffix := "…"
+	if start == end {
+		to = start + 2*snippetRadius
+	}
+	if to >= len(text) {
+		to = len(text)
+		suffix = ""
+	}
+
+	// Don't cut UTF-8 sequences in half
+	for from > 0 && !isRuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !isRuneStart(text[to]) {
+		to++
+	}
+
+	s := strings.Join(strings.Fields(text[from:to]), " ")
+	return prefix + s + suffix
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// Search scans every session from src for q. Sessions that fail to load
+// are skipped.
+func Search(src SessionSource, q SearchQuery) ([]SearchHit, error) {
+	projects, err := src.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []SearchHit
+	for _, p := range projects {
+		sessions, err := src.ListSessions(p.DirPath)
+		if err != nil {
+			continue
+		}
+		for _, info := range sessions {
+			sess, err := loadSessionInfo(src, info)
+			if err != nil {
+				continue
+			}
+			for _, hit := range SearchSession(sess, q) {
+				hit.Project = p.Name
+				hit.Session = info
+				hits = append(hits, hit)
+			}
+		}
+	}
+
+	return hits, nil
+}
+
+// loadSessionInfo loads a listed session, reading local files directly
+// instead of resolving the ID again.
+func loadSessionInfo(src SessionSource, info SessionInfo) (*Session, error) {
+	if info.Path != "" {
+		return LoadSession(info.Path)
+	}
+	return src.LoadSession(info.ID)
+}
@@ -0,0 +1,152 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func searchTestSession() *Session {
+	return &Session{
+		Turns: []Turn{
+			{
+				Number:   1,
+				UserText: "Fix the flaky test",
+				Blocks: []Block{
+					{Type: BlockThinking, Text: "The test probably has a race condition."},
+					{Type: BlockToolUse, ToolName: "Bash", ToolID: "t1", RawInput: `{"command":"go test -race ./..."}`},
+					{Type: BlockToolResult, ToolID: "t1", Text: "FAIL: data race detected", IsError: true},
+					{Type: BlockText, Text: "Found a race in the cache."},
+				},
+			},
+			{
+				Number:   2,
+				UserText: "Now run it again",
+				Blocks: []Block{
+					{Type: BlockToolUse, ToolName: "Bash", ToolID: "t2", RawInput: `{"command":"go test ./..."}`},
+					{Type: BlockToolResult, ToolID: "t2", Text: "ok"},
+					{
+						Type:      BlockToolUse,
+						ToolName:  "Task",
+						ToolID:    "t3",
+						RawInput:  `{"prompt":"review"}`,
+						Sidechain: []Turn{{Number: 1, UserText: "review", Blocks: []Block{{Type: BlockText, Text: "No more races."}}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestParseSearchQuery_Filters(t *testing.T) {
+	q, err := ParseSearchQuery("tool:Bash error:true in:result data race", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Tool != "Bash" {
+		t.Errorf("Tool: got %q", q.Tool)
+	}
+	if q.Error == nil || !*q.Error {
+		t.Errorf("Error: got %v", q.Error)
+	}
+	if q.Field != FieldToolResult {
+		t.Errorf("Field: got %q", q.Field)
+	}
+	if q.Pattern == nil || !q.Pattern.MatchString("a DATA RACE here") {
+		t.Errorf("pattern should match phrase case-insensitively")
+	}
+}
+
+func TestParseSearchQuery_Regex(t *testing.T) {
+	q, err := ParseSearchQuery("/ra[cs]e/", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !q.Pattern.MatchString("rase") {
+		t.Error("slash-wrapped text should be a regex")
+	}
+
+	q, err = ParseSearchQuery("a.c", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Pattern.MatchString("abc") {
+		t.Error("plain text should be matched literally")
+	}
+
+	if _, err := ParseSearchQuery("(", true); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if _, err := ParseSearchQuery("error:maybe", false); err == nil {
+		t.Error("expected error for invalid error filter")
+	}
+}
+
+func TestSearchSession_AllFields(t *testing.T) {
+	q, _ := ParseSearchQuery("race", false)
+	hits := SearchSession(searchTestSession(), q)
+
+	var fields []string
+	for _, h := range hits {
+		fields = append(fields, h.Field)
+	}
+	want := []string{FieldThinking, FieldToolInput, FieldToolResult, FieldText, FieldText}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Fatalf("fields: got %v, want %v", fields, want)
+	}
+	// The last hit comes from the subagent transcript of turn 2
+	if hits[4].Turn != 2 {
+		t.Errorf("sidechain hit turn: got %d, want 2", hits[4].Turn)
+	}
+}
+
+func TestSearchSession_ToolAndErrorFilter(t *testing.T) {
+	q, _ := ParseSearchQuery("tool:bash error:true", false)
+	hits := SearchSession(searchTestSession(), q)
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d: %+v", len(hits), hits)
+	}
+	if hits[0].Turn != 1 || hits[0].ToolName != "Bash" || hits[0].Field != FieldToolResult {
+		t.Errorf("unexpected hit: %+v", hits[0])
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a", 100) + " needle\nin the " + strings.Repeat("b", 100)
+	start := strings.Index(text, "needle")
+	got := snippet(text, start, start+len("needle"))
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected ellipses on both sides, got %q", got)
+	}
+	if !strings.Contains(got, "needle in the") {
+		t.Errorf("expected newline collapsed around match, got %q", got)
+	}
+
+	if got := snippet("short", 0, 5); got != "short" {
+		t.Errorf("short text: got %q", got)
+	}
+}
+
+func TestSearch_LocalSource(t *testing.T) {
+	dir := t.TempDir()
+	projectsDir := filepath.Join(dir, "projects", "-Users-test-proj")
+	os.MkdirAll(projectsDir, 0755)
+
+	content := `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"slug":"greeting","isSidechain":false}
+{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"hi there, general Kenobi"}]},"isSidechain":false}
+`
+	os.WriteFile(filepath.Join(projectsDir, "abcd-1234.jsonl"), []byte(content), 0644)
+
+	q, _ := ParseSearchQuery("kenobi", false)
+	hits, err := Search(&LocalSource{ClaudeDir: dir}, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d", len(hits))
+	}
+	if hits[0].Session.Slug != "greeting" || hits[0].Project != "proj" || hits[0].Turn != 1 {
+		t.Errorf("unexpected hit: %+v", hits[0])
+	}
+}
@@ -21,6 +21,9 @@ type Turn struct {
 	CWD       string           // Working directory
 	GitBranch string           // Git branch
 	Slug      string           // Session slug
+	Usage     Usage            // Token usage of this turn's API calls (excluding subagents)
+	ByModel   map[string]Usage // Usage split by the model of the responses
+	UUID      string           // UUID of the record that started the turn
 }
 
 // BlockType identifies what kind of content a block represents.
@@ -31,6 +34,12 @@ const (
 	BlockThinking
 	BlockToolUse
 	BlockToolResult
+
+	// Events interrupting the conversation, see events.go
+	BlockCompaction   // context compacted; Text holds the summary
+	BlockAPIError     // failed API request
+	BlockHook         // hook output or failure
+	BlockInterruption // the user interrupted a response
 )
 
 // Block is a single renderable piece of content within a turn.
@@ -42,6 +51,9 @@ type Block struct {
 	ToolID     string // Tool use ID (links tool_use to tool_result)
 	IsError    bool   // For tool_result blocks
 	RawInput   string // Raw JSON of tool input for display
+	Sidechain  []Turn // Subagent transcript (Task/Agent tool_use blocks)
+	Detail     string // For event blocks: one-line description
+	Timestamp  time.Time // When the record holding the block was written
 }
 
 // Session holds all turns parsed from a JSONL file.
@@ -56,6 +68,11 @@ type Session struct {
 	CWD       string
 	GitBranch string
 	Version   string
+	Forks     []Fork // branch points on the replayed path, in turn order
+
+	records  []parser.Record   // all records, to replay other branches
+	choices  map[string]string // selected branch per fork, see selectBranches
+	redactor *Redactor         // set on redacted copies, applied to other branches too
 }
 
 // LoadSession parses a JSONL file and segments it into turns.
@@ -69,20 +86,58 @@ func LoadSession(path string) (*Session, error) {
 		return nil, fmt.Errorf("empty session file")
 	}
 
+	return buildSession(path, records), nil
+}
+
+// buildSession segments records into a session read from path.
+func buildSession(path string, records []parser.Record) *Session {
 	sess := &Session{Path: path}
-	turns := segmentTurns(records, sess)
-	sess.Turns = turns
+	sess.Turns = segmentTurns(records, sess)
+	sess.setTimes()
+	return sess
+}
 
-	if len(turns) > 0 {
-		sess.StartTime = turns[0].Timestamp
-		sess.EndTime = turns[len(turns)-1].Timestamp
+// setTimes sets the start and end time from the turns.
+func (s *Session) setTimes() {
+	if len(s.Turns) > 0 {
+		s.StartTime = s.Turns[0].Timestamp
+		s.EndTime = s.Turns[len(s.Turns)-1].Timestamp
 	}
-
-	return sess, nil
 }
 
-// segmentTurns groups records into conversational turns.
+// segmentTurns groups records into conversational turns, following the
+// branches selected in sess (by default the latest ones) and recording the
+// forks in sess.Forks. Sidechain records are segmented separately and
+// attached to the tool_use that spawned them.
 func segmentTurns(records []parser.Record, sess *Session) []Turn {
+	var main, side []parser.Record
+	for _, rec := range records {
+		if rec.IsSidechain {
+			side = append(side, rec)
+		} else {
+			main = append(main, rec)
+		}
+	}
+
+	main, sess.Forks = selectBranches(main, sess.choices)
+	sess.records = records
+
+	turns := segmentChain(main, sess)
+	attachSidechains(turns, side)
+
+	for i := range sess.Forks {
+		f := &sess.Forks[i]
+		for _, t := range turns {
+			if t.UUID != "" && t.UUID == f.Branches[f.Selected].UUID {
+				f.Turn = t.Number
+			}
+		}
+	}
+	return turns
+}
+
+// segmentChain groups the records of a single conversation into turns.
+func segmentChain(records []parser.Record, sess *Session) []Turn {
 	var turns []Turn
 	var currentTurn *Turn
 	turnNum := 0
@@ -90,6 +145,19 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 	// Track durations from system records
 	pendingDuration := time.Duration(0)
 
+	// Each content block of a message is its own record repeating the
+	// message's usage, so count every message ID once, using its latest usage
+	msgUsage := map[string]Usage{}
+
+	// Events go to the current turn, or wait for the first one
+	var pending []Block
+	eventBlocks := func() *[]Block {
+		if currentTurn == nil {
+			return &pending
+		}
+		return &currentTurn.Blocks
+	}
+
 	for _, rec := range records {
 		// Extract session metadata from first records we see
 		if sess.ID == "" && rec.SessionID != "" {
@@ -104,6 +172,15 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 
 		switch rec.Type {
 		case parser.RecordTypeUser:
+			// The summary written after a compaction is not a prompt
+			if rec.IsCompactSummary {
+				if msg, err := rec.ParseUserMessage(); err == nil {
+					blocks := eventBlocks()
+					*blocks = addCompactSummary(*blocks, msg.UserText())
+				}
+				continue
+			}
+
 			// Skip meta messages (expanded skill prompts injected after commands)
 			if rec.IsMeta {
 				continue
@@ -127,8 +204,9 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 					}
 					if output != "" {
 						currentTurn.Blocks = append(currentTurn.Blocks, Block{
-							Type: BlockText,
-							Text: output,
+							Type:      BlockText,
+							Text:      output,
+							Timestamp: rec.Timestamp,
 						})
 					}
 				}
@@ -148,11 +226,13 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				currentTurn = &Turn{
 					Number:    turnNum,
 					UserText:  "!" + cmd,
+					UUID:      rec.UUID,
 					Timestamp: rec.Timestamp,
 					CWD:       rec.CWD,
 					GitBranch: rec.GitBranch,
 					Slug:      rec.Slug,
 				}
+				currentTurn.Blocks, pending = pending, nil
 
 				if sess.CWD == "" {
 					sess.CWD = rec.CWD
@@ -170,8 +250,9 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 								continue
 							}
 							block := Block{
-								Type:   BlockToolResult,
-								ToolID: tr.ToolUseID,
+								Type:      BlockToolResult,
+								ToolID:    tr.ToolUseID,
+								Timestamp: rec.Timestamp,
 							}
 							// Parse content: can be string or array
 							block.Text = extractToolResultContent(tr.Content)
@@ -188,6 +269,11 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				if cmdName, ok := userMsg.CommandName(); ok {
 					text = cmdName
 				}
+				if userMsg.IsInterruption() {
+					blocks := eventBlocks()
+					*blocks = append(*blocks, Block{Type: BlockInterruption, Text: text, Timestamp: rec.Timestamp})
+					continue
+				}
 				if text == "" {
 					continue
 				}
@@ -207,11 +293,13 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				currentTurn = &Turn{
 					Number:    turnNum,
 					UserText:  text,
+					UUID:      rec.UUID,
 					Timestamp: rec.Timestamp,
 					CWD:       rec.CWD,
 					GitBranch: rec.GitBranch,
 					Slug:      rec.Slug,
 				}
+				currentTurn.Blocks, pending = pending, nil
 
 				if sess.CWD == "" {
 					sess.CWD = rec.CWD
@@ -231,6 +319,21 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				continue
 			}
 
+			if rec.IsAPIErrorMessage {
+				var parts []string
+				for _, cb := range aMsg.Content {
+					if text := strings.TrimSpace(cb.Text); cb.Type == "text" && text != "" {
+						parts = append(parts, text)
+					}
+				}
+				currentTurn.Blocks = append(currentTurn.Blocks, Block{
+					Type:      BlockAPIError,
+					Text:      strings.Join(parts, "\n"),
+					Timestamp: rec.Timestamp,
+				})
+				continue
+			}
+
 			if currentTurn.Model == "" && aMsg.Model != "" {
 				currentTurn.Model = aMsg.Model
 				if sess.Model == "" {
@@ -238,6 +341,20 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				}
 			}
 
+			if aMsg.Usage != nil {
+				u := usageFrom(*aMsg.Usage)
+				if currentTurn.ByModel == nil {
+					currentTurn.ByModel = map[string]Usage{}
+				}
+				if prev, ok := msgUsage[aMsg.ID]; ok && aMsg.ID != "" {
+					currentTurn.Usage = currentTurn.Usage.sub(prev)
+					currentTurn.ByModel[aMsg.Model] = currentTurn.ByModel[aMsg.Model].sub(prev)
+				}
+				currentTurn.Usage = currentTurn.Usage.Add(u)
+				currentTurn.ByModel[aMsg.Model] = currentTurn.ByModel[aMsg.Model].Add(u)
+				msgUsage[aMsg.ID] = u
+			}
+
 			for _, cb := range aMsg.Content {
 				switch cb.Type {
 				case "text":
@@ -246,22 +363,25 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 						continue
 					}
 					currentTurn.Blocks = append(currentTurn.Blocks, Block{
-						Type: BlockText,
-						Text: text,
+						Type:      BlockText,
+						Text:      text,
+						Timestamp: rec.Timestamp,
 					})
 				case "thinking":
 					if cb.Thinking == "" {
 						continue
 					}
 					currentTurn.Blocks = append(currentTurn.Blocks, Block{
-						Type: BlockThinking,
-						Text: cb.Thinking,
+						Type:      BlockThinking,
+						Text:      cb.Thinking,
+						Timestamp: rec.Timestamp,
 					})
 				case "tool_use":
 					block := Block{
-						Type:     BlockToolUse,
-						ToolName: cb.Name,
-						ToolID:   cb.ID,
+						Type:      BlockToolUse,
+						ToolName:  cb.Name,
+						ToolID:    cb.ID,
+						Timestamp: rec.Timestamp,
 					}
 					if cb.Input != nil {
 						var input map[string]interface{}
@@ -274,9 +394,15 @@ func segmentTurns(records []parser.Record, sess *Session) []Turn {
 				}
 			}
 
-		case parser.RecordTypeSystem:
-			if rec.Subtype == "turn_duration" && rec.DurationMs > 0 {
-				pendingDuration = time.Duration(rec.DurationMs) * time.Millisecond
+		case parser.RecordTypeSystem, parser.RecordTypeAttachment:
+			if rec.Subtype == "turn_duration" {
+				if rec.DurationMs > 0 {
+					pendingDuration = time.Duration(rec.DurationMs) * time.Millisecond
+				}
+			} else if b, ok := recordEvent(rec); ok {
+				b.Timestamp = rec.Timestamp
+				blocks := eventBlocks()
+				*blocks = append(*blocks, b)
 			}
 		}
 	}
@@ -405,3 +405,128 @@ func TestLoadSession_ToolResultError(t *testing.T) {
 		t.Error("expected tool result to be an error")
 	}
 }
+
+func TestLoadSession_SidechainAttachedToTask(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sidechain.jsonl")
+
+	lines := `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"find the bug"},"isSidechain":false}
+{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Task","input":{"description":"Search code","prompt":"Look for the bug"}}]},"isSidechain":false}
+{"type":"user","parentUuid":null,"uuid":"sc1","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","message":{"role":"user","content":"Look for the bug"},"isSidechain":true}
+{"type":"user","parentUuid":null,"uuid":"w1","sessionId":"s1","timestamp":"2026-02-13T12:00:02.500Z","message":{"role":"user","content":"Warmup"},"isSidechain":true}
+{"type":"assistant","parentUuid":"sc1","uuid":"sc2","sessionId":"s1","timestamp":"2026-02-13T12:00:03.000Z","message":{"model":"claude-haiku-4-5","id":"msg_2","role":"assistant","content":[{"type":"thinking","thinking":"grep first"},{"type":"tool_use","id":"st1","name":"Grep","input":{"pattern":"bug"}}]},"isSidechain":true}
+{"type":"user","parentUuid":"sc2","uuid":"sc3","sessionId":"s1","timestamp":"2026-02-13T12:00:04.000Z","message":{"role":"user","content":[{"tool_use_id":"st1","type":"tool_result","content":"main.go:3"}]},"isSidechain":true}
+{"type":"assistant","parentUuid":"sc3","uuid":"sc4","sessionId":"s1","timestamp":"2026-02-13T12:00:06.000Z","message":{"model":"claude-haiku-4-5","id":"msg_3","role":"assistant","content":[{"type":"text","text":"It is in main.go"}]},"isSidechain":true}
+{"type":"user","parentUuid":"a1","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:00:07.000Z","message":{"role":"user","content":[{"tool_use_id":"t1","type":"tool_result","content":"It is in main.go"}]},"isSidechain":false}
+`
+	os.WriteFile(path, []byte(lines), 0644)
+
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sess.Turns) != 1 {
+		t.Fatalf("expected 1 turn, got %d", len(sess.Turns))
+	}
+	if sess.Model != "claude-opus-4-6" {
+		t.Errorf("session model should come from the main chain, got %q", sess.Model)
+	}
+
+	blocks := sess.Turns[0].Blocks
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	sub := blocks[0].Sidechain
+	if len(sub) != 1 {
+		t.Fatalf("expected 1 sidechain turn on the Task block, got %d", len(sub))
+	}
+	if sub[0].UserText != "Look for the bug" {
+		t.Errorf("sidechain user text: %q", sub[0].UserText)
+	}
+	if sub[0].Model != "claude-haiku-4-5" {
+		t.Errorf("sidechain model: %q", sub[0].Model)
+	}
+	if len(sub[0].Blocks) != 4 { // thinking + tool_use + tool_result + text
+		t.Errorf("sidechain blocks: expected 4, got %d", len(sub[0].Blocks))
+	}
+	if sub[0].Duration.Seconds() != 4 {
+		t.Errorf("sidechain duration: got %v, want 4s", sub[0].Duration)
+	}
+}
+
+func TestLoadSession_UsageCountedOncePerMessage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "usage-session.jsonl")
+
+	lines := []string{
+		`{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"isSidechain":false}`,
+		// Two records of the same message repeat its usage
+		`{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"thinking","thinking":"hmm"}],"usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"a1","uuid":"a2","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000}},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"a2","uuid":"a3","sessionId":"s1","timestamp":"2026-02-13T12:00:03.000Z","message":{"model":"claude-opus-4-6","id":"msg_2","role":"assistant","content":[{"type":"text","text":"again"}],"usage":{"input_tokens":1,"output_tokens":2,"cache_creation_input_tokens":0,"cache_read_input_tokens":1100}},"isSidechain":false}`,
+	}
+
+	content := ""
+	for _, l := range lines {
+		content += l + "\n"
+	}
+	os.WriteFile(path, []byte(content), 0644)
+
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+
+	want := Usage{InputTokens: 11, OutputTokens: 22, CacheCreationTokens: 100, CacheReadTokens: 2100}
+	if got := sess.Turns[0].Usage; got != want {
+		t.Errorf("turn usage: got %+v, want %+v", got, want)
+	}
+	if got := sess.TotalUsage(); got != want {
+		t.Errorf("session usage: got %+v, want %+v", got, want)
+	}
+}
+
+func TestFollower_ExtendsTurns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.jsonl")
+	user1 := `{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"first"},"isSidechain":false}`
+	reply1 := `{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"one"}]},"isSidechain":false}`
+	user2 := `{"type":"user","parentUuid":"a1","uuid":"u2","sessionId":"s1","timestamp":"2026-02-13T12:00:10.000Z","message":{"role":"user","content":"second"},"isSidechain":false}`
+
+	os.WriteFile(path, []byte(user1+"\n"+reply1[:30]), 0644)
+
+	f, err := NewFollower(path)
+	if err != nil {
+		t.Fatalf("NewFollower error: %v", err)
+	}
+	sess, err := f.Poll()
+	if err != nil {
+		t.Fatalf("Poll error: %v", err)
+	}
+	if len(sess.Turns) != 1 || len(sess.Turns[0].Blocks) != 0 {
+		t.Fatalf("expected one turn without blocks, got %+v", sess.Turns)
+	}
+
+	if sess, _ := f.Poll(); sess != nil {
+		t.Error("expected nil session when nothing changed")
+	}
+
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString(reply1[30:] + "\n" + user2 + "\n")
+	file.Close()
+
+	sess, err = f.Poll()
+	if err != nil {
+		t.Fatalf("Poll error: %v", err)
+	}
+	if len(sess.Turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(sess.Turns))
+	}
+	if len(sess.Turns[0].Blocks) != 1 || sess.Turns[0].Blocks[0].Text != "one" {
+		t.Errorf("expected first turn to be extended, got %+v", sess.Turns[0].Blocks)
+	}
+	if sess.Path != path || sess.ID != "s1" {
+		t.Errorf("unexpected session metadata: %q %q", sess.Path, sess.ID)
+	}
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// isSubagentTool reports whether a tool spawns a subagent sidechain.
+func isSubagentTool(name string) bool {
+	return name == "Task" || name == "Agent"
+}
+
+// attachSidechains segments sidechain records into subagent transcripts and
+// attaches each one to the Task/Agent tool_use whose prompt started it.
+// Sidechains without a matching tool_use (e.g. warmup requests) are dropped.
+func attachSidechains(turns []Turn, records []parser.Record) {
+	if len(records) == 0 {
+		return
+	}
+
+	// Index spawning tool_use blocks by prompt, in session order
+	spawners := map[string][]*Block{}
+	for i := range turns {
+		for j := range turns[i].Blocks {
+			b := &turns[i].Blocks[j]
+			if b.Type != BlockToolUse || !isSubagentTool(b.ToolName) {
+				continue
+			}
+			if prompt, _ := b.ToolInput["prompt"].(string); prompt != "" {
+				key := strings.TrimSpace(prompt)
+				spawners[key] = append(spawners[key], b)
+			}
+		}
+	}
+
+	for _, thread := range groupSidechains(records) {
+		sub := segmentChain(thread, &Session{})
+		if len(sub) == 0 {
+			continue
+		}
+		fillSidechainDurations(sub, thread[len(thread)-1].Timestamp)
+
+		key := strings.TrimSpace(sub[0].UserText)
+		candidates := spawners[key]
+		if len(candidates) == 0 {
+			continue
+		}
+		candidates[0].Sidechain = sub
+		spawners[key] = candidates[1:]
+	}
+}
+
+// groupSidechains splits sidechain records into threads by following
+// parentUuid links back to each thread's root. Threads are returned in
+// order of first appearance.
+func groupSidechains(records []parser.Record) [][]parser.Record {
+	rootOf := map[string]string{}
+	index := map[string]int{}
+	var threads [][]parser.Record
+
+	for _, rec := range records {
+		root := rec.UUID
+		if rec.ParentUUID != nil {
+			if r, ok := rootOf[*rec.ParentUUID]; ok {
+				root = r
+			}
+		}
+		if rec.UUID != "" {
+			rootOf[rec.UUID] = root
+		}
+
+		i, ok := index[root]
+		if !ok {
+			i = len(threads)
+			index[root] = i
+			threads = append(threads, nil)
+		}
+		threads[i] = append(threads[i], rec)
+	}
+
+	return threads
+}
+
+// fillSidechainDurations derives turn durations from timestamps, since
+// subagents don't emit turn_duration system records.
+func fillSidechainDurations(turns []Turn, end time.Time) {
+	for i := range turns {
+		if turns[i].Duration > 0 {
+			continue
+		}
+		next := end
+		if i+1 < len(turns) {
+			next = turns[i+1].Timestamp
+		}
+		if d := next.Sub(turns[i].Timestamp); d > 0 {
+			turns[i].Duration = d
+		}
+	}
+}
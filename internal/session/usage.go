@@ -0,0 +1,214 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Trailblaze-work/claude-replay/internal/parser"
+)
+
+// Usage is an aggregate of API token counts.
+type Usage struct {
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+}
+
+func usageFrom(u parser.Usage) Usage {
+	return Usage{
+		InputTokens:         u.InputTokens,
+		OutputTokens:        u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+	}
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:         u.InputTokens + o.InputTokens,
+		OutputTokens:        u.OutputTokens + o.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens + o.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens + o.CacheReadTokens,
+	}
+}
+
+func (u Usage) sub(o Usage) Usage {
+	return Usage{
+		InputTokens:         u.InputTokens - o.InputTokens,
+		OutputTokens:        u.OutputTokens - o.OutputTokens,
+		CacheCreationTokens: u.CacheCreationTokens - o.CacheCreationTokens,
+		CacheReadTokens:     u.CacheReadTokens - o.CacheReadTokens,
+	}
+}
+
+// Total returns all tokens, including cache reads and writes.
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// IsZero reports whether no tokens were recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// TotalUsage returns the turn's usage including its subagent transcripts.
+func (t Turn) TotalUsage() Usage {
+	u := t.Usage
+	for _, b := range t.Blocks {
+		for _, sub := range b.Sidechain {
+			u = u.Add(sub.TotalUsage())
+		}
+	}
+	return u
+}
+
+// TotalUsage returns the usage of all turns, including subagents.
+func (s *Session) TotalUsage() Usage {
+	var u Usage
+	for _, t := range s.Turns {
+		u = u.Add(t.TotalUsage())
+	}
+	return u
+}
+
+// FormatTokens renders a token count compactly (e.g. 950, 12.3k, 1.2M).
+func FormatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// Price is the cost of a model in USD per million tokens.
+type Price struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheWrite float64 `json:"cache_write"`
+	CacheRead  float64 `json:"cache_read"`
+}
+
+// Cost returns the cost in USD of usage at this price.
+func (p Price) Cost(u Usage) float64 {
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationTokens)*p.CacheWrite +
+		float64(u.CacheReadTokens)*p.CacheRead) / 1_000_000
+}
+
+// PriceTable maps model name fragments (e.g. "opus-4-6", "sonnet") to prices.
+// The longest fragment contained in a model name wins.
+type PriceTable map[string]Price
+
+// DefaultPrices returns list prices for current Claude models.
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"opus":      {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50},
+		"opus-4-5":  {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.50},
+		"opus-4-6":  {Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.50},
+		"sonnet":    {Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30},
+		"haiku":     {Input: 0.80, Output: 4, CacheWrite: 1, CacheRead: 0.08},
+		"haiku-4-5": {Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.10},
+	}
+}
+
+// DefaultPricesPath returns where a user price table is looked up
+// (e.g. ~/.config/claude-replay/prices.json).
+func DefaultPricesPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-replay", "prices.json")
+}
+
+// LoadPriceTable returns the default prices overridden by the JSON object
+// in path, keyed like PriceTable. A missing file is not an error unless
+// required is set.
+func LoadPriceTable(path string, required bool) (PriceTable, error) {
+	prices := DefaultPrices()
+	if path == "" {
+		return prices, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return prices, nil
+		}
+		return nil, fmt.Errorf("reading price table: %w", err)
+	}
+	var overrides PriceTable
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parsing price table %s: %w", path, err)
+	}
+	for model, p := range overrides {
+		prices[model] = p
+	}
+	return prices, nil
+}
+
+// Lookup returns the price for a model.
+func (pt PriceTable) Lookup(model string) (Price, bool) {
+	var best string
+	for key := range pt {
+		if strings.Contains(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return pt[best], true
+}
+
+// TurnCost estimates the cost of a turn and its subagents, each API call
+// priced by the model that answered it. ok is false if any model with
+// usage has no known price.
+func (pt PriceTable) TurnCost(t Turn) (cost float64, ok bool) {
+	ok = true
+	byModel := t.ByModel
+	if byModel == nil {
+		byModel = map[string]Usage{t.Model: t.Usage}
+	}
+	for model, u := range byModel {
+		if u.IsZero() {
+			continue
+		}
+		if model == "" {
+			model = t.Model
+		}
+		if p, found := pt.Lookup(model); found {
+			cost += p.Cost(u)
+		} else {
+			ok = false
+		}
+	}
+	for _, b := range t.Blocks {
+		for _, sub := range b.Sidechain {
+			c, subOK := pt.TurnCost(sub)
+			cost += c
+			ok = ok && subOK
+		}
+	}
+	return cost, ok
+}
+
+// SessionCost estimates the cost of a whole session.
+func (pt PriceTable) SessionCost(s *Session) (cost float64, ok bool) {
+	ok = true
+	for _, t := range s.Turns {
+		c, turnOK := pt.TurnCost(t)
+		cost += c
+		ok = ok && turnOK
+	}
+	return cost, ok
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPriceTable_LookupLongestMatch(t *testing.T) {
+	prices := DefaultPrices()
+
+	p, ok := prices.Lookup("claude-opus-4-6")
+	if !ok || p.Input != 5 {
+		t.Errorf("opus-4-6: got %+v, %v", p, ok)
+	}
+	p, ok = prices.Lookup("claude-opus-4-1-20250805")
+	if !ok || p.Input != 15 {
+		t.Errorf("opus-4-1 should fall back to opus: got %+v, %v", p, ok)
+	}
+	if _, ok := prices.Lookup("gpt-4"); ok {
+		t.Error("expected unknown model to have no price")
+	}
+}
+
+func TestPriceTable_TurnCostIncludesSubagents(t *testing.T) {
+	prices := PriceTable{
+		"opus":  {Input: 10, Output: 20},
+		"haiku": {Input: 1, Output: 2},
+	}
+	turn := Turn{
+		Model: "claude-opus-4-6",
+		Usage: Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000},
+		Blocks: []Block{{
+			Type:     BlockToolUse,
+			ToolName: "Task",
+			Sidechain: []Turn{
+				{Model: "claude-haiku-4-5", Usage: Usage{InputTokens: 1_000_000}},
+			},
+		}},
+	}
+
+	cost, ok := prices.TurnCost(turn)
+	if !ok || math.Abs(cost-31) > 1e-9 {
+		t.Errorf("cost: got %v, %v; want 31, true", cost, ok)
+	}
+	if got := turn.TotalUsage().InputTokens; got != 2_000_000 {
+		t.Errorf("total input tokens: got %d", got)
+	}
+
+	turn.Model = "mystery"
+	if _, ok := prices.TurnCost(turn); ok {
+		t.Error("expected unknown model to mark cost incomplete")
+	}
+}
+
+func TestPriceTable_TurnCostMixedModels(t *testing.T) {
+	// A fallback model answered the second call of the turn
+	lines := []string{
+		`{"type":"user","parentUuid":null,"uuid":"u1","sessionId":"s1","timestamp":"2026-02-13T12:00:00.000Z","message":{"role":"user","content":"hello"},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"u1","uuid":"a1","sessionId":"s1","timestamp":"2026-02-13T12:00:01.000Z","message":{"model":"claude-opus-4-6","id":"msg_1","role":"assistant","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":1000000,"output_tokens":0}},"isSidechain":false}`,
+		`{"type":"assistant","parentUuid":"a1","uuid":"a2","sessionId":"s1","timestamp":"2026-02-13T12:00:02.000Z","message":{"model":"claude-haiku-4-5","id":"msg_2","role":"assistant","content":[{"type":"text","text":"again"}],"usage":{"input_tokens":1000000,"output_tokens":0}},"isSidechain":false}`,
+	}
+	path := filepath.Join(t.TempDir(), "mixed.jsonl")
+	content := ""
+	for _, l := range lines {
+		content += l + "\n"
+	}
+	os.WriteFile(path, []byte(content), 0644)
+	sess, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession error: %v", err)
+	}
+
+	prices := PriceTable{
+		"opus":  {Input: 10},
+		"haiku": {Input: 1},
+	}
+	cost, ok := prices.TurnCost(sess.Turns[0])
+	if !ok || math.Abs(cost-11) > 1e-9 {
+		t.Errorf("cost: got %v, %v; want 11, true", cost, ok)
+	}
+
+	delete(prices, "haiku")
+	if _, ok := prices.TurnCost(sess.Turns[0]); ok {
+		t.Error("expected the unpriced fallback model to mark cost incomplete")
+	}
+}
+
+func TestLoadPriceTable_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	os.WriteFile(path, []byte(`{"sonnet":{"input":1,"output":2},"custom-model":{"input":3}}`), 0644)
+
+	prices, err := LoadPriceTable(path, true)
+	if err != nil {
+		t.Fatalf("LoadPriceTable error: %v", err)
+	}
+	if p, _ := prices.Lookup("claude-sonnet-4-6"); p.Input != 1 {
+		t.Errorf("expected sonnet override, got %+v", p)
+	}
+	if _, ok := prices.Lookup("custom-model-v1"); !ok {
+		t.Error("expected added model")
+	}
+	if _, ok := prices.Lookup("claude-opus-4-6"); !ok {
+		t.Error("expected defaults to be kept")
+	}
+
+	if _, err := LoadPriceTable(filepath.Join(t.TempDir(), "missing.json"), false); err != nil {
+		t.Errorf("missing optional file should not error: %v", err)
+	}
+	if _, err := LoadPriceTable(filepath.Join(t.TempDir(), "missing.json"), true); err == nil {
+		t.Error("missing required file should error")
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	tests := map[int]string{0: "0", 950: "950", 12_345: "12.3k", 1_200_000: "1.2M"}
+	for n, want := range tests {
+		if got := FormatTokens(n); got != want {
+			t.Errorf("FormatTokens(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/Trailblaze-work/claude-replay/internal/export"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/browse"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/replay"
@@ -16,6 +17,8 @@ const (
 	ScreenProjects Screen = iota
 	ScreenSessions
 	ScreenReplay
+	ScreenSearch
+	ScreenStats
 )
 
 // AppModel is the top-level Bubble Tea model.
@@ -29,6 +32,12 @@ type AppModel struct {
 	projectList  browse.ProjectListModel
 	sessionList  browse.SessionListModel
 	replayModel  replay.Model
+	searchModel  browse.SearchModel
+	statsModel   browse.StatsModel
+
+	searchReturn Screen // screen to restore when leaving search
+	replayReturn Screen // screen to restore when leaving replay
+	statsReturn  Screen // screen to restore when leaving analytics
 
 	currentProject session.Project
 
@@ -70,9 +79,20 @@ type sessionsLoadedMsg struct {
 
 type sessionLoadedMsg struct {
 	session *session.Session
+	turn    int // 1-based turn to open at, 0 for the first
 	err     error
 }
 
+type searchDoneMsg struct {
+	hits []session.SearchHit
+	err  error
+}
+
+type statsDoneMsg struct {
+	analytics *session.Analytics
+	err       error
+}
+
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -91,6 +111,10 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmd tea.Cmd
 			m.replayModel, cmd = m.replayModel.Update(msg)
 			return m, cmd
+		case ScreenSearch:
+			m.searchModel, _ = m.searchModel.Update(msg)
+		case ScreenStats:
+			m.statsModel, _ = m.statsModel.Update(msg)
 		}
 		return m, nil
 
@@ -115,8 +139,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
+		m.replayReturn = m.screen
 		m.screen = ScreenReplay
 		m.replayModel = replay.New(msg.session, m.width, m.height)
+		m.replayModel.SetSnapshotter(export.SaveSnapshot)
+		m.replayModel.SetAnnotationStore(session.AnnotationStoreFor(m.source))
+		if msg.turn > 0 {
+			m.replayModel.JumpToTurn(msg.turn)
+		}
 		return m, nil
 
 	case browse.ProjectSelected:
@@ -135,8 +165,46 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.loadProjects()
 
 	case replay.BackToList:
+		if m.replayReturn == ScreenSearch {
+			m.screen = ScreenSearch
+			return m, nil
+		}
 		m.screen = ScreenSessions
 		return m, m.loadSessions(m.currentProject.DirPath)
+
+	case browse.OpenSearch:
+		m.searchReturn = m.screen
+		m.screen = ScreenSearch
+		m.searchModel = browse.NewSearch(m.width, m.height)
+		return m, m.searchModel.Init()
+
+	case browse.SearchSubmitted:
+		return m, m.runSearch(msg.Query)
+
+	case searchDoneMsg:
+		m.searchModel = m.searchModel.SetResults(msg.hits, msg.err)
+		return m, nil
+
+	case browse.SearchHitSelected:
+		return m, m.loadSessionAt(msg.Hit.Session.ID, msg.Hit.Turn)
+
+	case browse.SearchClosed:
+		m.screen = m.searchReturn
+		return m, nil
+
+	case browse.OpenStats:
+		m.statsReturn = m.screen
+		m.screen = ScreenStats
+		m.statsModel = browse.NewStats(m.width, m.height)
+		return m, m.runStats()
+
+	case statsDoneMsg:
+		m.statsModel = m.statsModel.SetAnalytics(msg.analytics, msg.err)
+		return m, nil
+
+	case browse.StatsClosed:
+		m.screen = m.statsReturn
+		return m, nil
 	}
 
 	// Route updates to current screen
@@ -153,6 +221,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.replayModel, cmd = m.replayModel.Update(msg)
 		return m, cmd
+	case ScreenSearch:
+		var cmd tea.Cmd
+		m.searchModel, cmd = m.searchModel.Update(msg)
+		return m, cmd
+	case ScreenStats:
+		var cmd tea.Cmd
+		m.statsModel, cmd = m.statsModel.Update(msg)
+		return m, cmd
 	}
 
 	return m, nil
@@ -170,6 +246,10 @@ func (m AppModel) View() string {
 		return m.sessionList.View()
 	case ScreenReplay:
 		return m.replayModel.View()
+	case ScreenSearch:
+		return m.searchModel.View()
+	case ScreenStats:
+		return m.statsModel.View()
 	}
 
 	return "Loading..."
@@ -190,8 +270,34 @@ func (m AppModel) loadSessions(projectID string) tea.Cmd {
 }
 
 func (m AppModel) loadSession(sessionID string) tea.Cmd {
+	return m.loadSessionAt(sessionID, 0)
+}
+
+func (m AppModel) loadSessionAt(sessionID string, turn int) tea.Cmd {
 	return func() tea.Msg {
 		sess, err := m.source.LoadSession(sessionID)
-		return sessionLoadedMsg{session: sess, err: err}
+		return sessionLoadedMsg{session: sess, turn: turn, err: err}
+	}
+}
+
+func (m AppModel) runSearch(query string) tea.Cmd {
+	return func() tea.Msg {
+		q, err := session.ParseSearchQuery(query, false)
+		if err != nil {
+			return searchDoneMsg{err: err}
+		}
+		hits, err := session.Search(m.source, q)
+		return searchDoneMsg{hits: hits, err: err}
+	}
+}
+
+func (m AppModel) runStats() tea.Cmd {
+	return func() tea.Msg {
+		prices, err := session.LoadPriceTable(session.DefaultPricesPath(), false)
+		if err != nil {
+			return statsDoneMsg{err: err}
+		}
+		a, err := session.Analyze(m.source, prices)
+		return statsDoneMsg{analytics: a, err: err}
 	}
 }
@@ -86,6 +86,9 @@ func NewProjectList(projects []session.Project, width, height int) ProjectListMo
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = theme.StyleListTitle
 	l.SetShowHelp(true)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{theme.DefaultKeyMap.Search, theme.DefaultKeyMap.Stats}
+	}
 
 	return ProjectListModel{
 		list:     l,
@@ -112,6 +115,10 @@ func (m ProjectListModel) Update(msg tea.Msg) (ProjectListModel, tea.Cmd) {
 			if item, ok := m.list.SelectedItem().(projectItem); ok {
 				return m, func() tea.Msg { return ProjectSelected{Project: item.project} }
 			}
+		case key.Matches(msg, theme.DefaultKeyMap.Search):
+			return m, func() tea.Msg { return OpenSearch{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Stats):
+			return m, func() tea.Msg { return OpenStats{} }
 		case key.Matches(msg, theme.DefaultKeyMap.Quit):
 			return m, tea.Quit
 		}
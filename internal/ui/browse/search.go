@@ -0,0 +1,224 @@
+package browse
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// OpenSearch is sent when the user asks for the search screen.
+type OpenSearch struct{}
+
+// SearchSubmitted is sent when a search query is entered.
+type SearchSubmitted struct {
+	Query string
+}
+
+// SearchHitSelected is sent when a search result is chosen.
+type SearchHitSelected struct {
+	Hit session.SearchHit
+}
+
+// SearchClosed signals leaving the search screen.
+type SearchClosed struct{}
+
+// searchHitItem wraps a SearchHit for the list.
+type searchHitItem struct {
+	hit session.SearchHit
+}
+
+func (i searchHitItem) FilterValue() string {
+	return i.hit.Session.Slug + " " + i.hit.Snippet
+}
+
+type searchHitDelegate struct{}
+
+func (d searchHitDelegate) Height() int                             { return 2 }
+func (d searchHitDelegate) Spacing() int                            { return 1 }
+func (d searchHitDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d searchHitDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(searchHitItem)
+	if !ok {
+		return
+	}
+
+	isSelected := index == m.Index()
+	h := item.hit
+
+	name := h.Session.Slug
+	if name == "" && len(h.Session.ID) > 8 {
+		name = h.Session.ID[:8] + "..."
+	}
+	title := fmt.Sprintf("%s  ·  turn %d", name, h.Turn)
+	if h.Project != "" {
+		title = h.Project + " / " + title
+	}
+
+	field := h.Field
+	if h.ToolName != "" {
+		field = h.ToolName + " " + field
+	}
+	snippet := h.Snippet
+	if maxLen := m.Width() - 8 - len(field); maxLen > 10 && len([]rune(snippet)) > maxLen {
+		snippet = string([]rune(snippet)[:maxLen-1]) + "…"
+	}
+	detail := fmt.Sprintf("%s: %s", field, snippet)
+
+	var nameStyle, detailStyle lipgloss.Style
+	if isSelected {
+		nameStyle = lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).PaddingLeft(2)
+		detailStyle = lipgloss.NewStyle().Foreground(theme.ColorSecondary).PaddingLeft(4)
+		fmt.Fprintf(w, "%s\n%s", nameStyle.Render("> "+title), detailStyle.Render(detail))
+	} else {
+		nameStyle = lipgloss.NewStyle().Foreground(theme.ColorText).PaddingLeft(2)
+		detailStyle = lipgloss.NewStyle().Foreground(theme.ColorDim).PaddingLeft(4)
+		fmt.Fprintf(w, "%s\n%s", nameStyle.Render("  "+title), detailStyle.Render(detail))
+	}
+}
+
+// SearchModel is the cross-session search screen.
+type SearchModel struct {
+	input     textinput.Model
+	list      list.Model
+	hits      []session.SearchHit
+	searching bool
+	err       error
+	width     int
+	height    int
+}
+
+// NewSearch creates an empty search screen with the query input focused.
+func NewSearch(width, height int) SearchModel {
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "text, /regex/, tool:Bash, error:true, in:thinking"
+	input.Focus()
+
+	l := list.New(nil, searchHitDelegate{}, width, height-6)
+	l.Title = "Search results"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = theme.StyleListTitle
+	l.SetShowHelp(true)
+
+	return SearchModel{
+		input:  input,
+		list:   l,
+		width:  width,
+		height: height,
+	}
+}
+
+// SetResults shows the hits of a finished search.
+func (m SearchModel) SetResults(hits []session.SearchHit, err error) SearchModel {
+	m.searching = false
+	m.err = err
+	m.hits = hits
+
+	items := make([]list.Item, len(hits))
+	for i, h := range hits {
+		items[i] = searchHitItem{hit: h}
+	}
+	m.list.SetItems(items)
+	m.list.ResetSelected()
+	m.list.Title = fmt.Sprintf("Search results — %d matches", len(hits))
+
+	if err == nil && len(hits) > 0 {
+		m.input.Blur()
+	}
+	return m
+}
+
+func (m SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.input.Focused() {
+			switch msg.Type {
+			case tea.KeyEnter:
+				query := strings.TrimSpace(m.input.Value())
+				if query == "" || m.searching {
+					return m, nil
+				}
+				m.searching = true
+				m.err = nil
+				return m, func() tea.Msg { return SearchSubmitted{Query: query} }
+			case tea.KeyEsc:
+				return m, func() tea.Msg { return SearchClosed{} }
+			case tea.KeyDown, tea.KeyTab:
+				if len(m.hits) > 0 {
+					m.input.Blur()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, theme.DefaultKeyMap.Select):
+			if item, ok := m.list.SelectedItem().(searchHitItem); ok {
+				return m, func() tea.Msg { return SearchHitSelected{Hit: item.hit} }
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.Filter), msg.Type == tea.KeyTab:
+			return m, m.input.Focus()
+		case key.Matches(msg, theme.DefaultKeyMap.Back):
+			return m, func() tea.Msg { return SearchClosed{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Quit):
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width, msg.Height-6)
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m SearchModel) View() string {
+	header := lipgloss.NewStyle().
+		Foreground(theme.ColorPrimary).
+		Bold(true).
+		PaddingLeft(1).
+		Render("> claude-replay")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(theme.ColorDim).
+		PaddingLeft(1).
+		Render("  search all sessions")
+
+	input := lipgloss.NewStyle().PaddingLeft(1).Render(m.input.View())
+
+	var status string
+	switch {
+	case m.searching:
+		status = lipgloss.NewStyle().Foreground(theme.ColorSecondary).PaddingLeft(1).Render("Searching…")
+	case m.err != nil:
+		status = lipgloss.NewStyle().Foreground(theme.ColorError).PaddingLeft(1).Render(m.err.Error())
+	}
+
+	return strings.Join([]string{
+		header + subtitle,
+		"",
+		input,
+		status,
+		m.list.View(),
+	}, "\n")
+}
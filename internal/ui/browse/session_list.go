@@ -95,6 +95,9 @@ func NewSessionList(sessions []session.SessionInfo, projectName string, width, h
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = theme.StyleListTitle
 	l.SetShowHelp(true)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{theme.DefaultKeyMap.Search, theme.DefaultKeyMap.Stats}
+	}
 
 	return SessionListModel{
 		list:        l,
@@ -123,6 +126,10 @@ func (m SessionListModel) Update(msg tea.Msg) (SessionListModel, tea.Cmd) {
 			}
 		case key.Matches(msg, theme.DefaultKeyMap.Back):
 			return m, func() tea.Msg { return GoBack{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Search):
+			return m, func() tea.Msg { return OpenSearch{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Stats):
+			return m, func() tea.Msg { return OpenStats{} }
 		case key.Matches(msg, theme.DefaultKeyMap.Quit):
 			return m, tea.Quit
 		}
@@ -0,0 +1,191 @@
+package browse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// OpenStats is sent when the user asks for the analytics screen.
+type OpenStats struct{}
+
+// StatsClosed signals leaving the analytics screen.
+type StatsClosed struct{}
+
+// statsTopN is how many models, tools and projects are listed.
+const statsTopN = 10
+
+// StatsModel is the aggregate analytics screen.
+type StatsModel struct {
+	viewport  viewport.Model
+	analytics *session.Analytics
+	err       error
+	width     int
+	height    int
+}
+
+// NewStats creates the analytics screen, empty until SetAnalytics is called.
+func NewStats(width, height int) StatsModel {
+	vp := viewport.New(width, height-3)
+	vp.SetContent(lipgloss.NewStyle().Foreground(theme.ColorSecondary).PaddingLeft(1).
+		Render("Analyzing sessions…"))
+	return StatsModel{viewport: vp, width: width, height: height}
+}
+
+// SetAnalytics shows the result of a finished analysis.
+func (m StatsModel) SetAnalytics(a *session.Analytics, err error) StatsModel {
+	m.analytics = a
+	m.err = err
+	m.viewport.SetContent(m.renderContent())
+	return m
+}
+
+func (m StatsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, theme.DefaultKeyMap.Back):
+			return m, func() tea.Msg { return StatsClosed{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Quit):
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height - 3
+		if m.analytics != nil || m.err != nil {
+			m.viewport.SetContent(m.renderContent())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m StatsModel) View() string {
+	header := lipgloss.NewStyle().
+		Foreground(theme.ColorPrimary).
+		Bold(true).
+		PaddingLeft(1).
+		Render("> claude-replay")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(theme.ColorDim).
+		PaddingLeft(1).
+		Render("  analytics across all sessions")
+
+	help := lipgloss.NewStyle().
+		Foreground(theme.ColorDim).
+		PaddingLeft(1).
+		Render("↑/↓ scroll • esc back • q quit")
+
+	return strings.Join([]string{header + subtitle, m.viewport.View(), help}, "\n")
+}
+
+func (m StatsModel) renderContent() string {
+	if m.err != nil {
+		return lipgloss.NewStyle().Foreground(theme.ColorError).PaddingLeft(1).Render(m.err.Error())
+	}
+	a := m.analytics
+
+	title := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true)
+	label := lipgloss.NewStyle().Foreground(theme.ColorSecondary)
+	value := lipgloss.NewStyle().Foreground(theme.ColorText)
+	dim := lipgloss.NewStyle().Foreground(theme.ColorDim)
+	bar := lipgloss.NewStyle().Foreground(theme.ColorAccent)
+	errBar := lipgloss.NewStyle().Foreground(theme.ColorError)
+
+	barWidth := m.width - 50
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	var b strings.Builder
+	section := func(name string) {
+		b.WriteString("\n " + title.Render(name) + "\n")
+	}
+	row := func(name, val string) {
+		fmt.Fprintf(&b, "   %s %s\n", label.Render(fmt.Sprintf("%-22s", name)), value.Render(val))
+	}
+
+	section("Overview")
+	row("Sessions", fmt.Sprintf("%d", a.Sessions))
+	row("Turns", fmt.Sprintf("%d", a.Turns))
+	row("Turns per session", fmt.Sprintf("min %d · median %d · mean %.1f · max %d",
+		a.TurnsPerSession.Min, a.TurnsPerSession.Median, a.TurnsPerSession.Mean, a.TurnsPerSession.Max))
+	row("Total turn duration", formatHours(a.TotalDuration))
+	row("Tokens", fmt.Sprintf("%s in · %s out · cache %s w / %s r",
+		session.FormatTokens(a.Usage.InputTokens), session.FormatTokens(a.Usage.OutputTokens),
+		session.FormatTokens(a.Usage.CacheCreationTokens), session.FormatTokens(a.Usage.CacheReadTokens)))
+	cost := fmt.Sprintf("$%.2f", a.Usage.Cost)
+	if !a.Usage.CostComplete {
+		cost += dim.Render(" (some models unpriced)")
+	}
+	row("Estimated cost", cost)
+
+	section("Sessions per day")
+	busiest := 0
+	for _, d := range a.SessionsPerDay {
+		busiest = max(busiest, d.Sessions)
+	}
+	for _, d := range a.SessionsPerDay {
+		n := max(1, d.Sessions*barWidth/busiest)
+		fmt.Fprintf(&b, "   %s %s %s\n", label.Render(d.Date), bar.Render(strings.Repeat("▇", n)), value.Render(fmt.Sprintf("%d", d.Sessions)))
+	}
+
+	section("Models")
+	for i, mc := range a.Models {
+		if i == statsTopN {
+			break
+		}
+		row(mc.Model, fmt.Sprintf("%d turns (%.0f%%)", mc.Turns, float64(mc.Turns)*100/float64(max(1, a.Turns))))
+	}
+
+	section("Tools")
+	mostCalls := 0
+	for _, t := range a.Tools {
+		mostCalls = max(mostCalls, t.Calls)
+	}
+	for i, t := range a.Tools {
+		if i == statsTopN {
+			break
+		}
+		ok := (t.Calls - t.Errors) * barWidth / mostCalls
+		failed := t.Errors * barWidth / mostCalls
+		fmt.Fprintf(&b, "   %s %s%s %s\n",
+			label.Render(fmt.Sprintf("%-14s", t.Name)),
+			bar.Render(strings.Repeat("▇", ok)),
+			errBar.Render(strings.Repeat("▇", failed)),
+			value.Render(fmt.Sprintf("%d calls · %.1f%% errors", t.Calls, t.ErrorRate*100)))
+	}
+
+	section("Busiest projects")
+	for i, p := range a.Projects {
+		if i == statsTopN {
+			break
+		}
+		row(p.Name, fmt.Sprintf("%d sessions · %d turns · %s", p.Sessions, p.Turns, formatHours(p.Duration)))
+	}
+
+	return b.String()
+}
+
+// formatHours renders a long duration as hours and minutes.
+func formatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -4,6 +4,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
 )
 
 func TestRenderHeader_ContainsSlug(t *testing.T) {
@@ -30,13 +33,13 @@ func TestRenderHeader_HidesHEADBranch(t *testing.T) {
 
 func TestRenderTimeline_Boundaries(t *testing.T) {
 	// First turn: bar should be mostly empty
-	first := RenderTimeline(1, 10, 80)
+	first := RenderTimeline(1, 10, 80, nil, nil)
 	if first == "" {
 		t.Fatal("expected non-empty timeline for first turn")
 	}
 
 	// Last turn: bar should be mostly filled
-	last := RenderTimeline(10, 10, 80)
+	last := RenderTimeline(10, 10, 80, nil, nil)
 	if last == "" {
 		t.Fatal("expected non-empty timeline for last turn")
 	}
@@ -50,12 +53,41 @@ func TestRenderTimeline_Boundaries(t *testing.T) {
 }
 
 func TestRenderTimeline_ZeroTotal(t *testing.T) {
-	got := RenderTimeline(0, 0, 80)
+	got := RenderTimeline(0, 0, 80, nil, nil)
 	if got != "" {
 		t.Errorf("expected empty string for zero total, got %q", got)
 	}
 }
 
+func TestRenderTimeline_EventTicks(t *testing.T) {
+	turns := []session.Turn{
+		{Number: 1},
+		{Number: 2, Blocks: []session.Block{{Type: session.BlockHook}, {Type: session.BlockCompaction}}},
+		{Number: 3},
+		{Number: 4, Blocks: []session.Block{{Type: session.BlockText}, {Type: session.BlockAPIError}}},
+	}
+	events := TurnEvents(turns)
+	if len(events) != 2 || events[2] != session.BlockCompaction || events[4] != session.BlockAPIError {
+		t.Fatalf("TurnEvents = %v", events)
+	}
+
+	got := RenderTimeline(1, 4, 80, events, nil)
+	if strings.Count(got, "◆") != 1 || strings.Count(got, "✕") != 1 || strings.Contains(got, "⚑") {
+		t.Errorf("expected one tick per event turn, got %q", got)
+	}
+	if plain := RenderTimeline(1, 4, 80, nil, nil); lipgloss.Width(got) != lipgloss.Width(plain) {
+		t.Errorf("ticks should not change the timeline width: %d vs %d", lipgloss.Width(got), lipgloss.Width(plain))
+	}
+}
+
+func TestRenderTimeline_Bookmarks(t *testing.T) {
+	events := map[int]session.BlockType{2: session.BlockCompaction}
+	got := RenderTimeline(1, 4, 80, events, map[int]bool{2: true, 3: true})
+	if strings.Count(got, BookmarkSymbol) != 2 || strings.Contains(got, "◆") {
+		t.Errorf("bookmarks should be ticked over events, got %q", got)
+	}
+}
+
 func TestFormatModelShort(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -96,3 +128,19 @@ func TestFormatDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderStatusBar_Usage(t *testing.T) {
+	ts := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+
+	without := RenderStatusBar(1, 2, "claude-opus-4-6", time.Second, ts, session.Usage{}, 160)
+	if strings.Contains(without, "in ") {
+		t.Error("status bar should omit usage when none was recorded")
+	}
+
+	with := RenderStatusBar(1, 2, "claude-opus-4-6", time.Second, ts, session.Usage{InputTokens: 1500, OutputTokens: 20, CacheReadTokens: 2_000_000}, 160)
+	for _, want := range []string{"in 1.5k", "out 20", "r 2.0M"} {
+		if !strings.Contains(with, want) {
+			t.Errorf("status bar missing %q: %q", want, with)
+		}
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
 )
 
-// RenderStatusBar renders the bottom status bar.
-func RenderStatusBar(turnNum, totalTurns int, model string, duration time.Duration, timestamp time.Time, width int) string {
+// RenderStatusBar renders the bottom status bar. Token usage is shown when
+// the turn recorded any.
+func RenderStatusBar(turnNum, totalTurns int, model string, duration time.Duration, timestamp time.Time, usage session.Usage, width int) string {
 	turnInfo := lipgloss.NewStyle().
 		Foreground(theme.ColorPrimary).
 		Bold(true).
@@ -33,6 +35,11 @@ func RenderStatusBar(turnNum, totalTurns int, model string, duration time.Durati
 		Render("  │  ")
 
 	content := turnInfo + sep + modelInfo + sep + durationInfo + sep + timeInfo
+	if !usage.IsZero() {
+		content += sep + lipgloss.NewStyle().
+			Foreground(theme.ColorSecondary).
+			Render(formatUsage(usage))
+	}
 
 	bar := lipgloss.NewStyle().
 		Background(theme.ColorBgAlt).
@@ -43,8 +50,10 @@ func RenderStatusBar(turnNum, totalTurns int, model string, duration time.Durati
 	return bar.Render(content)
 }
 
-// RenderTimeline renders the visual timeline scrubber.
-func RenderTimeline(current, total, width int) string {
+// RenderTimeline renders the visual timeline scrubber. events maps turn
+// numbers to the event shown as a tick at that point, see TurnEvents;
+// bookmarked turns get a bookmark tick, over any event.
+func RenderTimeline(current, total, width int, events map[int]session.BlockType, bookmarked map[int]bool) string {
 	if total <= 0 {
 		return ""
 	}
@@ -64,13 +73,131 @@ func RenderTimeline(current, total, width int) string {
 		filled = barWidth
 	}
 
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	// Ticks replace the bar cell of their turn
+	ticks := map[int]session.BlockType{}
+	for turn, ev := range events {
+		if turn < 1 || turn > total {
+			continue
+		}
+		pos := 0
+		if total > 1 {
+			pos = min((turn-1)*barWidth/(total-1), barWidth-1)
+		}
+		if prev, ok := ticks[pos]; !ok || eventRank(ev) < eventRank(prev) {
+			ticks[pos] = ev
+		}
+	}
+	marks := map[int]bool{}
+	for turn := range bookmarked {
+		if turn < 1 || turn > total {
+			continue
+		}
+		pos := 0
+		if total > 1 {
+			pos = min((turn-1)*barWidth/(total-1), barWidth-1)
+		}
+		marks[pos] = true
+	}
+
+	barStyle := lipgloss.NewStyle().Foreground(theme.ColorPrimary)
+	var bar, run strings.Builder
+	for i := 0; i < barWidth; i++ {
+		ev, ok := ticks[i]
+		if !ok && !marks[i] {
+			if i < filled {
+				run.WriteString("█")
+			} else {
+				run.WriteString("░")
+			}
+			continue
+		}
+		if run.Len() > 0 {
+			bar.WriteString(barStyle.Render(run.String()))
+			run.Reset()
+		}
+		if marks[i] {
+			bar.WriteString(lipgloss.NewStyle().Foreground(theme.ColorSuccess).Render(BookmarkSymbol))
+			continue
+		}
+		bar.WriteString(lipgloss.NewStyle().Foreground(EventColor(ev)).Render(EventSymbol(ev)))
+	}
+	if run.Len() > 0 {
+		bar.WriteString(barStyle.Render(run.String()))
+	}
 
 	left := lipgloss.NewStyle().Foreground(theme.ColorDim).Render(prefix)
-	activeBar := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Render(bar)
 	right := lipgloss.NewStyle().Foreground(theme.ColorDim).Render(suffix)
 
-	return left + activeBar + right
+	return left + bar.String() + right
+}
+
+// BookmarkSymbol marks annotated turns on the timeline and in the replay.
+const BookmarkSymbol = "✦"
+
+// TurnEvents returns the most notable event of each turn that has one, by
+// turn number: compaction first, then API errors, interruptions and hooks.
+func TurnEvents(turns []session.Turn) map[int]session.BlockType {
+	events := map[int]session.BlockType{}
+	for _, turn := range turns {
+		for _, b := range turn.Blocks {
+			if !b.Type.IsEvent() {
+				continue
+			}
+			if prev, ok := events[turn.Number]; !ok || eventRank(b.Type) < eventRank(prev) {
+				events[turn.Number] = b.Type
+			}
+		}
+	}
+	return events
+}
+
+func eventRank(t session.BlockType) int {
+	switch t {
+	case session.BlockCompaction:
+		return 0
+	case session.BlockAPIError:
+		return 1
+	case session.BlockInterruption:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// EventSymbol returns the marker of an event block type.
+func EventSymbol(t session.BlockType) string {
+	switch t {
+	case session.BlockCompaction:
+		return "◆"
+	case session.BlockAPIError:
+		return "✕"
+	case session.BlockInterruption:
+		return "■"
+	case session.BlockHook:
+		return "⚑"
+	default:
+		return "·"
+	}
+}
+
+// EventColor returns the color of an event block type's marker.
+func EventColor(t session.BlockType) lipgloss.Color {
+	switch t {
+	case session.BlockCompaction:
+		return theme.ColorAccent
+	case session.BlockAPIError, session.BlockInterruption:
+		return theme.ColorError
+	default:
+		return theme.ColorWarning
+	}
+}
+
+func formatUsage(u session.Usage) string {
+	s := fmt.Sprintf("in %s  out %s", session.FormatTokens(u.InputTokens), session.FormatTokens(u.OutputTokens))
+	if u.CacheCreationTokens > 0 || u.CacheReadTokens > 0 {
+		s += fmt.Sprintf("  cache w %s r %s", session.FormatTokens(u.CacheCreationTokens), session.FormatTokens(u.CacheReadTokens))
+	}
+	return s
 }
 
 func formatModelShort(model string) string {
@@ -0,0 +1,160 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/components"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// annotationsClosed is sent when the annotations screen is left. Turn is
+// the top-level turn to show, or 0 to stay where the replay was; Block is
+// the 1-based block to focus, or 0.
+type annotationsClosed struct {
+	Turn  int
+	Block int
+}
+
+// AnnotationsModel lists the bookmarks and notes of a session.
+type AnnotationsModel struct {
+	items    []session.Annotation
+	prompts  map[int]string // user text of the annotated turns
+	selected int
+	width    int
+	height   int
+}
+
+// NewAnnotations creates the annotations screen of a session.
+func NewAnnotations(sess *session.Session, a *session.Annotations, width, height int) AnnotationsModel {
+	m := AnnotationsModel{prompts: map[int]string{}, width: width, height: height}
+	if a != nil {
+		m.items = a.Items
+	}
+	for _, turn := range sess.Turns {
+		m.prompts[turn.Number] = turn.UserText
+	}
+	return m
+}
+
+func (m AnnotationsModel) Update(msg tea.Msg) (AnnotationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if len(m.items) == 0 {
+			return m, func() tea.Msg { return annotationsClosed{} }
+		}
+		switch {
+		case key.Matches(msg, theme.DefaultKeyMap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, theme.DefaultKeyMap.Back), key.Matches(msg, theme.DefaultKeyMap.Annotations):
+			return m, func() tea.Msg { return annotationsClosed{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Select):
+			item := m.items[m.selected]
+			return m, func() tea.Msg { return annotationsClosed{Turn: item.Turn, Block: item.Block} }
+		case key.Matches(msg, theme.DefaultKeyMap.ScrollDown):
+			if m.selected < len(m.items)-1 {
+				m.selected++
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.ScrollUp):
+			if m.selected > 0 {
+				m.selected--
+			}
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+func (m AnnotationsModel) View() string {
+	title := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).Render("> claude-replay")
+	header := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(theme.ColorDim).
+		Width(m.width).
+		Render(title + lipgloss.NewStyle().Foreground(theme.ColorAccent).
+			Render(fmt.Sprintf("  Annotations (%d)", len(m.items))))
+
+	if len(m.items) == 0 {
+		return header + "\n\n  No bookmarks or notes yet: press m to bookmark a turn, a to write a note.\n\n  Press any key to go back."
+	}
+
+	// Two lines per annotation, keeping the selected one visible
+	height := max(1, (m.height-4)/2)
+	start := 0
+	if m.selected >= height {
+		start = m.selected - height + 1
+	}
+
+	var lines []string
+	for i := start; i < len(m.items) && i < start+height; i++ {
+		item := m.items[i]
+		where := fmt.Sprintf("turn %d", item.Turn)
+		if item.Block > 0 {
+			where += fmt.Sprintf(" · block %d", item.Block)
+		}
+		note := item.Note
+		if note == "" {
+			note = "bookmark"
+		}
+		prompt := strings.Join(strings.Fields(m.prompts[item.Turn]), " ")
+		prompt = truncateString(prompt, max(10, m.width-8))
+
+		style := lipgloss.NewStyle().Foreground(theme.ColorText)
+		marker := "  "
+		if i == m.selected {
+			style = style.Bold(true).Reverse(true)
+			marker = "▸ "
+		}
+		lines = append(lines,
+			style.Render(marker+components.BookmarkSymbol+" "+where+"  "+truncateString(note, max(10, m.width-len(where)-8))),
+			lipgloss.NewStyle().Foreground(theme.ColorSecondary).PaddingLeft(4).Render(prompt))
+	}
+
+	help := lipgloss.NewStyle().Foreground(theme.ColorDim).PaddingLeft(1).
+		Render("↑/↓ select • enter go to turn • esc back")
+	return header + "\n" + strings.Join(lines, "\n") + "\n\n" + help
+}
+
+// RenderAnnotations renders the bookmarks and notes of a turn, shown above
+// it in the replay and in recordings.
+func RenderAnnotations(items []session.Annotation, width int) string {
+	if len(items) == 0 {
+		return ""
+	}
+	mark := lipgloss.NewStyle().Foreground(theme.ColorSuccess).Render(components.BookmarkSymbol)
+	text := lipgloss.NewStyle().Foreground(theme.ColorSuccess).Italic(true).Width(max(20, width-6))
+
+	var lines []string
+	for _, item := range items {
+		for i, line := range strings.Split(text.Render(AnnotationText(item)), "\n") {
+			prefix := "    "
+			if i == 0 {
+				prefix = "  " + mark + " "
+			}
+			lines = append(lines, prefix+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AnnotationText describes an annotation: its note, or that it is a
+// bookmark, and the block it is about.
+func AnnotationText(item session.Annotation) string {
+	switch {
+	case item.Block > 0 && item.Note == "":
+		return fmt.Sprintf("block %d bookmarked", item.Block)
+	case item.Block > 0:
+		return fmt.Sprintf("block %d: %s", item.Block, item.Note)
+	case item.Note == "":
+		return "bookmarked"
+	}
+	return item.Note
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/components"
 	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
 )
 
@@ -23,15 +24,16 @@ var toolDisplayNames = map[string]string{
 	"Edit": "Update",
 }
 
-func toolDisplayName(name string) string {
+// ToolDisplayName returns the name Claude Code shows for a tool.
+func ToolDisplayName(name string) string {
 	if dn, ok := toolDisplayNames[name]; ok {
 		return dn
 	}
 	return name
 }
 
-// shortenPath strips the CWD prefix from a path to show relative paths.
-func shortenPath(path, cwd string) string {
+// ShortenPath strips the CWD prefix from a path to show relative paths.
+func ShortenPath(path, cwd string) string {
 	if cwd != "" && strings.HasPrefix(path, cwd) {
 		rel := strings.TrimPrefix(path, cwd)
 		return strings.TrimPrefix(rel, "/")
@@ -39,27 +41,61 @@ func shortenPath(path, cwd string) string {
 	return path
 }
 
+// BlockState is how a block is shown: expanded to its full content, and
+// whether it has the focus cursor.
+type BlockState struct {
+	Expanded bool
+	Focused  bool
+}
+
 // RenderBlock renders a single content block.
 // readContents maps file paths to their content from earlier Read results,
 // used to compute diffs for Write operations.
-func RenderBlock(block session.Block, allExpanded bool, width int, cwd string, toolInputs map[string]toolUseInfo, readContents map[string]string) string {
+func RenderBlock(block session.Block, state BlockState, width int, cwd string, toolInputs map[string]toolUseInfo, readContents map[string]string) string {
 	contentWidth := width - 4
 	if contentWidth < 20 {
 		contentWidth = 20
 	}
 
+	var rendered string
 	switch block.Type {
 	case session.BlockText:
-		return renderTextBlock(block.Text, contentWidth)
+		rendered = renderTextBlock(block.Text, contentWidth)
 	case session.BlockThinking:
-		return renderThinkingBlock(block.Text, allExpanded, contentWidth)
+		rendered = renderThinkingBlock(block.Text, state.Expanded, contentWidth)
 	case session.BlockToolUse:
-		return renderToolUseBlock(block, allExpanded, contentWidth, cwd, readContents)
+		rendered = renderToolUseBlock(block, state.Expanded, contentWidth, cwd, readContents)
 	case session.BlockToolResult:
-		return renderToolResultBlock(block, allExpanded, contentWidth, cwd, toolInputs, readContents)
-	default:
-		return ""
+		rendered = renderToolResultBlock(block, state.Expanded, contentWidth, cwd, toolInputs, readContents)
+	case session.BlockCompaction, session.BlockAPIError, session.BlockHook, session.BlockInterruption:
+		rendered = renderEventBlock(block, state.Expanded, contentWidth)
+	}
+	if state.Focused && rendered != "" {
+		rendered = markFocused(rendered)
+	}
+	return rendered
+}
+
+// markFocused draws the focus cursor, a bar in the left margin of every
+// line of a rendered block.
+func markFocused(rendered string) string {
+	bar := lipgloss.NewStyle().Foreground(theme.ColorAccent).Render("▌")
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		// Take the place of the first space, after any leading styles
+		start := 0
+		for _, loc := range sgrSequence.FindAllStringIndex(line, -1) {
+			if loc[0] != start {
+				break
+			}
+			start = loc[1]
+		}
+		if start < len(line) && line[start] == ' ' {
+			line = line[:start] + line[start+1:]
+		}
+		lines[i] = bar + line
 	}
+	return strings.Join(lines, "\n")
 }
 
 func renderTextBlock(text string, width int) string {
@@ -102,11 +138,67 @@ func renderThinkingBlock(text string, expanded bool, width int) string {
 	return header + "\n" + body
 }
 
+// eventPreviewLines is how much of an event's text shows while collapsed.
+const eventPreviewLines = 3
+
+// EventTitle returns the one-line description of an event block.
+func EventTitle(block session.Block) string {
+	var title string
+	switch block.Type {
+	case session.BlockCompaction:
+		title = "Context compacted"
+	case session.BlockAPIError:
+		title = "API error"
+	case session.BlockHook:
+		title = "Hook"
+	case session.BlockInterruption:
+		title = "Interrupted by user"
+		if strings.Contains(block.Text, "tool use") {
+			title += " during tool use"
+		}
+	}
+	if block.Detail != "" {
+		title += " (" + block.Detail + ")"
+	}
+	return title
+}
+
+// renderEventBlock renders a compaction, API error, hook or interruption as
+// a marker line, followed by the start of its text (all of it when
+// expanded). A compaction's text is the summary the conversation continued
+// from.
+func renderEventBlock(block session.Block, expanded bool, width int) string {
+	color := components.EventColor(block.Type)
+	header := lipgloss.NewStyle().Foreground(color).Render(components.EventSymbol(block.Type)) + " " +
+		lipgloss.NewStyle().Foreground(color).Bold(true).Render(EventTitle(block))
+
+	text := strings.TrimSpace(block.Text)
+	if block.Type == session.BlockInterruption {
+		text = ""
+	}
+	if text == "" {
+		return "  " + header
+	}
+
+	lines := strings.Split(text, "\n")
+	if !expanded && len(lines) > eventPreviewLines {
+		header += lipgloss.NewStyle().Foreground(theme.ColorDim).Render(
+			fmt.Sprintf("  %d lines  [ctrl+o:toggle]", len(lines)))
+		text = strings.Join(lines[:eventPreviewLines], "\n") + "\n..."
+	}
+	body := lipgloss.NewStyle().
+		Foreground(theme.ColorSecondary).
+		PaddingLeft(4).
+		Width(width).
+		Render(text)
+	return "  " + header + "\n" + body
+}
+
 func renderToolUseBlock(block session.Block, expanded bool, width int, cwd string, readContents map[string]string) string {
 	bullet := lipgloss.NewStyle().
 		Foreground(theme.ColorSuccess).
 		Render("●")
-	displayName := toolDisplayName(block.ToolName)
+	displayName := ToolDisplayName(block.ToolName)
 	name := lipgloss.NewStyle().
 		Bold(true).
 		Render(displayName)
@@ -117,7 +209,7 @@ func renderToolUseBlock(block session.Block, expanded bool, width int, cwd strin
 		return fmt.Sprintf("  %s %s %s %s", bullet, name, "1 file", hint)
 	}
 
-	brief := toolBriefParam(block, cwd)
+	brief := ToolBriefParam(block, cwd)
 	var header string
 	if brief != "" {
 		paramStyle := lipgloss.NewStyle()
@@ -140,7 +232,9 @@ func renderToolUseBlock(block session.Block, expanded bool, width int, cwd strin
 	return header
 }
 
-func toolBriefParam(block session.Block, cwd string) string {
+// ToolBriefParam returns the short parameter shown next to a tool name,
+// e.g. the command for Bash or the relative path for Read.
+func ToolBriefParam(block session.Block, cwd string) string {
 	input := block.ToolInput
 	if input == nil {
 		return ""
@@ -156,13 +250,13 @@ func toolBriefParam(block session.Block, cwd string) string {
 		}
 	case "Read":
 		path, _ := input["file_path"].(string)
-		return shortenPath(path, cwd)
+		return ShortenPath(path, cwd)
 	case "Write":
 		path, _ := input["file_path"].(string)
-		return shortenPath(path, cwd)
+		return ShortenPath(path, cwd)
 	case "Edit":
 		path, _ := input["file_path"].(string)
-		return shortenPath(path, cwd)
+		return ShortenPath(path, cwd)
 	case "Glob":
 		pattern, _ := input["pattern"].(string)
 		return pattern
@@ -170,7 +264,7 @@ func toolBriefParam(block session.Block, cwd string) string {
 		pattern, _ := input["pattern"].(string)
 		path, _ := input["path"].(string)
 		if path != "" {
-			return fmt.Sprintf("/%s/ in %s", pattern, shortenPath(path, cwd))
+			return fmt.Sprintf("/%s/ in %s", pattern, ShortenPath(path, cwd))
 		}
 		return fmt.Sprintf("/%s/", pattern)
 	case "WebFetch":
@@ -222,7 +316,7 @@ func renderToolInput(block session.Block, expanded bool, width int, cwd string,
 
 	case "Read":
 		path, _ := input["file_path"].(string)
-		return style.Render(shortenPath(path, cwd))
+		return style.Render(ShortenPath(path, cwd))
 
 	case "Write":
 		path, _ := input["file_path"].(string)
@@ -232,12 +326,12 @@ func renderToolInput(block session.Block, expanded bool, width int, cwd string,
 		}
 		// No prior Read: show as new file
 		lines := strings.Count(content, "\n") + 1
-		return style.Render(fmt.Sprintf("%s (%d lines)", shortenPath(path, cwd), lines))
+		return style.Render(fmt.Sprintf("%s (%d lines)", ShortenPath(path, cwd), lines))
 
 	case "Edit":
 		path, _ := input["file_path"].(string)
 		if !expanded {
-			return style.Render(shortenPath(path, cwd))
+			return style.Render(ShortenPath(path, cwd))
 		}
 		return renderEditDiff(input, width, cwd)
 
@@ -249,7 +343,7 @@ func renderToolInput(block session.Block, expanded bool, width int, cwd string,
 		pattern, _ := input["pattern"].(string)
 		path, _ := input["path"].(string)
 		if path != "" {
-			return style.Render(fmt.Sprintf("/%s/ in %s", pattern, shortenPath(path, cwd)))
+			return style.Render(fmt.Sprintf("/%s/ in %s", pattern, ShortenPath(path, cwd)))
 		}
 		return style.Render(fmt.Sprintf("/%s/", pattern))
 
@@ -344,17 +438,19 @@ func renderToolResultBlock(block session.Block, expanded bool, width int, cwd st
 	return fmt.Sprintf("    %s  %s", bracket, style.Render(text))
 }
 
-// diffOp represents one line in a computed diff.
-type diffOp struct {
+// DiffOp represents one line in a computed diff.
+type DiffOp struct {
 	Kind byte   // ' ' context, '+' added, '-' removed
 	Text string // the line content
 }
 
-// computeDiff computes a line-level diff between old and new text using LCS.
-func computeDiff(oldStr, newStr string) []diffOp {
-	oldLines := splitLines(oldStr)
-	newLines := splitLines(newStr)
+// ComputeDiff computes a line-level diff between old and new text using LCS.
+func ComputeDiff(oldStr, newStr string) []DiffOp {
+	return lcsDiff(splitLines(oldStr), splitLines(newStr))
+}
 
+// lcsDiff computes the diff of two line slices using LCS.
+func lcsDiff(oldLines, newLines []string) []DiffOp {
 	// LCS table
 	m, n := len(oldLines), len(newLines)
 	dp := make([][]int, m+1)
@@ -374,18 +470,18 @@ func computeDiff(oldStr, newStr string) []diffOp {
 	}
 
 	// Backtrack to produce diff ops
-	var ops []diffOp
+	var ops []DiffOp
 	i, j := m, n
 	for i > 0 || j > 0 {
 		if i > 0 && j > 0 && oldLines[i-1] == newLines[j-1] {
-			ops = append(ops, diffOp{' ', oldLines[i-1]})
+			ops = append(ops, DiffOp{' ', oldLines[i-1]})
 			i--
 			j--
 		} else if j > 0 && (i == 0 || dp[i][j-1] >= dp[i-1][j]) {
-			ops = append(ops, diffOp{'+', newLines[j-1]})
+			ops = append(ops, DiffOp{'+', newLines[j-1]})
 			j--
 		} else {
-			ops = append(ops, diffOp{'-', oldLines[i-1]})
+			ops = append(ops, DiffOp{'-', oldLines[i-1]})
 			i--
 		}
 	}
@@ -405,7 +501,7 @@ func splitLines(s string) []string {
 }
 
 // countDiffChanges returns the number of added and removed lines.
-func countDiffChanges(ops []diffOp) (added, removed int) {
+func countDiffChanges(ops []DiffOp) (added, removed int) {
 	for _, op := range ops {
 		switch op.Kind {
 		case '+':
@@ -430,9 +526,9 @@ func renderEditDiff(input map[string]interface{}, width int, cwd string) string
 	}
 
 	var out []string
-	out = append(out, "    "+shortenPath(path, cwd))
+	out = append(out, "    "+ShortenPath(path, cwd))
 
-	ops := computeDiff(oldStr, newStr)
+	ops := ComputeDiff(oldStr, newStr)
 
 	// Get lexer once for all lines
 	lexer := getLexer(path)
@@ -461,7 +557,7 @@ func renderEditResultBlock(input map[string]interface{}, expanded bool, width in
 	oldStr, _ := input["old_string"].(string)
 	newStr, _ := input["new_string"].(string)
 
-	ops := computeDiff(oldStr, newStr)
+	ops := ComputeDiff(oldStr, newStr)
 	added, removed := countDiffChanges(ops)
 
 	var summary string
@@ -486,9 +582,9 @@ func renderWriteDiff(oldContent, newContent, path string, width int, cwd string)
 	}
 
 	var out []string
-	out = append(out, "    "+shortenPath(path, cwd))
+	out = append(out, "    "+ShortenPath(path, cwd))
 
-	ops := computeDiff(oldContent, newContent)
+	ops := ComputeDiff(oldContent, newContent)
 	lexer := getLexer(path)
 
 	ctxStyle := lipgloss.NewStyle().
@@ -526,7 +622,7 @@ func renderWriteResultBlock(input map[string]interface{}, readContents map[strin
 		return fmt.Sprintf("    %s  %s", bracket, summary)
 	}
 
-	ops := computeDiff(oldContent, content)
+	ops := ComputeDiff(oldContent, content)
 	added, removed := countDiffChanges(ops)
 
 	var summary string
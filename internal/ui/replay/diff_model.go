@@ -0,0 +1,357 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// DiffModel shows two sessions side by side, advancing through their
+// aligned turns together.
+type DiffModel struct {
+	a, b        *session.Session
+	cmp         session.Comparison
+	current     int // index into cmp.Pairs
+	left, right viewport.Model
+	width       int
+	height      int
+	allExpanded bool
+	showSummary bool
+}
+
+// NewDiff creates a split-pane comparison of two sessions.
+func NewDiff(a, b *session.Session, width, height int) DiffModel {
+	m := DiffModel{
+		a:      a,
+		b:      b,
+		cmp:    session.Compare(a, b),
+		width:  width,
+		height: height,
+	}
+	m.initViewports()
+	return m
+}
+
+// diffChromeHeight is the number of lines around the panes' viewports:
+// header (2), pane titles and tool sequences (2), timeline and help.
+const diffChromeHeight = 6
+
+func (m *DiffModel) paneWidth() int {
+	return (m.width - 3) / 2
+}
+
+func (m *DiffModel) initViewports() {
+	h := m.height - diffChromeHeight
+	if h < 5 {
+		h = 5
+	}
+	m.left = viewport.New(m.paneWidth(), h)
+	m.right = viewport.New(m.paneWidth(), h)
+	m.updateContent()
+}
+
+func (m *DiffModel) updateContent() {
+	if len(m.cmp.Pairs) == 0 {
+		return
+	}
+	p := m.cmp.Pairs[m.current]
+	m.left.SetContent(m.renderPane(m.a, p.A))
+	m.right.SetContent(m.renderPane(m.b, p.B))
+	m.left.GotoTop()
+	m.right.GotoTop()
+}
+
+func (m *DiffModel) renderPane(sess *session.Session, idx int) string {
+	if idx < 0 {
+		return lipgloss.NewStyle().Foreground(theme.ColorDim).Italic(true).PaddingLeft(2).
+			Render("(no matching turn in this session)")
+	}
+	return renderTurn(sess.Turns[idx], m.allExpanded, m.paneWidth(), sess.CWD, -1)
+}
+
+func (m *DiffModel) gotoPair(i int) {
+	m.current = i
+	m.updateContent()
+}
+
+func (m DiffModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DiffModel) Update(msg tea.Msg) (DiffModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.showSummary {
+			m.showSummary = false
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, theme.DefaultKeyMap.Quit), key.Matches(msg, theme.DefaultKeyMap.Back):
+			return m, tea.Quit
+		case key.Matches(msg, theme.DefaultKeyMap.NextTurn):
+			if m.current < len(m.cmp.Pairs)-1 {
+				m.gotoPair(m.current + 1)
+			}
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.PrevTurn):
+			if m.current > 0 {
+				m.gotoPair(m.current - 1)
+			}
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.FirstTurn):
+			m.gotoPair(0)
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.LastTurn):
+			m.gotoPair(len(m.cmp.Pairs) - 1)
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.NextDivergence):
+			for i := m.current + 1; i < len(m.cmp.Pairs); i++ {
+				if m.pairStatus(i) != pairSame {
+					m.gotoPair(i)
+					break
+				}
+			}
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.ExpandTool):
+			m.allExpanded = !m.allExpanded
+			m.updateContent()
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.Summary):
+			m.showSummary = true
+			return m, nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.initViewports()
+		return m, nil
+	}
+
+	// Both panes scroll together
+	var cmdL, cmdR tea.Cmd
+	m.left, cmdL = m.left.Update(msg)
+	m.right, cmdR = m.right.Update(msg)
+	return m, tea.Batch(cmdL, cmdR)
+}
+
+// pairStatus classifies an aligned pair for the timeline.
+type pairStatus int
+
+const (
+	pairSame     pairStatus = iota // same prompt and tool calls
+	pairDiverged                   // same prompt, different tool calls
+	pairChanged                    // different prompts
+	pairOnlyA                      // turn only in session A
+	pairOnlyB                      // turn only in session B
+)
+
+func (m *DiffModel) pairStatus(i int) pairStatus {
+	return classifyPair(m.a, m.b, m.cmp.Pairs[i])
+}
+
+func classifyPair(a, b *session.Session, p session.TurnPair) pairStatus {
+	switch {
+	case p.B < 0:
+		return pairOnlyA
+	case p.A < 0:
+		return pairOnlyB
+	}
+	ta, tb := a.Turns[p.A], b.Turns[p.B]
+	if !session.SameUserText(ta, tb) {
+		return pairChanged
+	}
+	if session.ToolDivergence(ta, tb) >= 0 {
+		return pairDiverged
+	}
+	return pairSame
+}
+
+func (m DiffModel) View() string {
+	if len(m.cmp.Pairs) == 0 {
+		return "Both sessions are empty."
+	}
+	if m.showSummary {
+		return m.summaryView()
+	}
+
+	p := m.cmp.Pairs[m.current]
+	pw := m.paneWidth()
+
+	title := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).Render("> claude-replay")
+	names := lipgloss.NewStyle().Foreground(theme.ColorAccent).
+		Render("  " + sessionName(m.a) + "  ⇄  " + sessionName(m.b))
+	header := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(theme.ColorDim).
+		Width(m.width).
+		Render(title + names)
+
+	div := -1
+	if p.A >= 0 && p.B >= 0 {
+		div = session.ToolDivergence(m.a.Turns[p.A], m.b.Turns[p.B])
+	}
+	leftHead := paneTitle("A", m.a, p.A, pw) + "\n" + toolSequence(m.a, p.A, div, pw)
+	rightHead := paneTitle("B", m.b, p.B, pw) + "\n" + toolSequence(m.b, p.B, div, pw)
+
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDim).
+		Render(strings.Repeat(" │ \n", 2+m.left.Height-1) + " │ ")
+	leftCol := lipgloss.NewStyle().Width(pw).Render(leftHead + "\n" + m.left.View())
+	rightCol := lipgloss.NewStyle().Width(pw).Render(rightHead + "\n" + m.right.View())
+	panes := lipgloss.JoinHorizontal(lipgloss.Top, leftCol, sep, rightCol)
+
+	help := lipgloss.NewStyle().Foreground(theme.ColorDim).PaddingLeft(1).
+		Render("←/→ turn • d next difference • j/k scroll • ctrl+o expand • s summary • q quit")
+
+	return header + "\n" + panes + "\n" + m.renderTimeline() + "\n" + help
+}
+
+// renderTimeline draws one cell per aligned pair, colored by status, with
+// the current pair highlighted.
+func (m DiffModel) renderTimeline() string {
+	marks := map[pairStatus]struct {
+		char  string
+		color lipgloss.Color
+	}{
+		pairSame:     {"═", theme.ColorSuccess},
+		pairDiverged: {"≠", theme.ColorWarning},
+		pairChanged:  {"~", theme.ColorWarning},
+		pairOnlyA:    {"◀", theme.ColorError},
+		pairOnlyB:    {"▶", theme.ColorError},
+	}
+
+	// Show a window of pairs around the current one when they don't fit
+	maxCells := m.width - 20
+	if maxCells < 10 {
+		maxCells = 10
+	}
+	start := 0
+	if len(m.cmp.Pairs) > maxCells {
+		start = min(max(0, m.current-maxCells/2), len(m.cmp.Pairs)-maxCells)
+	}
+	end := min(len(m.cmp.Pairs), start+maxCells)
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		mark := marks[m.pairStatus(i)]
+		style := lipgloss.NewStyle().Foreground(mark.color)
+		if i == m.current {
+			style = style.Bold(true).Underline(true).Reverse(true)
+		}
+		b.WriteString(style.Render(mark.char))
+	}
+
+	pos := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).
+		Render(fmt.Sprintf(" %d/%d ", m.current+1, len(m.cmp.Pairs)))
+	return pos + b.String()
+}
+
+func (m DiffModel) summaryView() string {
+	label := lipgloss.NewStyle().Foreground(theme.ColorSecondary)
+	title := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(title.Render("Comparison") + "\n\n")
+	b.WriteString(FormatComparison(m.a, m.b, m.cmp))
+	b.WriteString("\n" + label.Render("Press any key to close"))
+	return theme.StyleBorder.Width(m.width - 4).Render(b.String())
+}
+
+// FormatComparison renders a plain-text summary of a comparison: turn
+// counts, durations, tokens, aligned turns and files touched.
+func FormatComparison(a, b *session.Session, c session.Comparison) string {
+	counts := map[pairStatus]int{}
+	for _, p := range c.Pairs {
+		counts[classifyPair(a, b, p)]++
+	}
+
+	var s strings.Builder
+	row := func(name, va, vb string) {
+		fmt.Fprintf(&s, "  %-14s %-20s %s\n", name, va, vb)
+	}
+	row("", "A: "+sessionName(a), "B: "+sessionName(b))
+	row("Turns", fmt.Sprintf("%d", c.A.Turns), fmt.Sprintf("%d", c.B.Turns))
+	row("Duration", FormatDuration(c.A.Duration), FormatDuration(c.B.Duration))
+	row("Input tokens", session.FormatTokens(c.A.Usage.InputTokens), session.FormatTokens(c.B.Usage.InputTokens))
+	row("Output tokens", session.FormatTokens(c.A.Usage.OutputTokens), session.FormatTokens(c.B.Usage.OutputTokens))
+	row("Cache read", session.FormatTokens(c.A.Usage.CacheReadTokens), session.FormatTokens(c.B.Usage.CacheReadTokens))
+	row("Files touched", fmt.Sprintf("%d", len(c.A.Files)), fmt.Sprintf("%d", len(c.B.Files)))
+
+	fmt.Fprintf(&s, "\n  Aligned turns: %d identical, %d with different tool calls, %d reworded, %d only in A, %d only in B\n",
+		counts[pairSame], counts[pairDiverged], counts[pairChanged], counts[pairOnlyA], counts[pairOnlyB])
+
+	files := func(name string, list []string) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Fprintf(&s, "\n  %s:\n", name)
+		for _, f := range list {
+			fmt.Fprintf(&s, "    %s\n", f)
+		}
+	}
+	files("Files only in A", c.FilesOnlyA)
+	files("Files only in B", c.FilesOnlyB)
+	files("Files in both", c.FilesBoth)
+	return s.String()
+}
+
+func sessionName(sess *session.Session) string {
+	if sess.Slug != "" {
+		return sess.Slug
+	}
+	if len(sess.ID) > 8 {
+		return sess.ID[:8]
+	}
+	return sess.ID
+}
+
+func paneTitle(side string, sess *session.Session, idx, width int) string {
+	style := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).MaxWidth(width)
+	if idx < 0 {
+		return style.Render(fmt.Sprintf("%s  —/%d", side, len(sess.Turns)))
+	}
+	t := sess.Turns[idx]
+	text := fmt.Sprintf("%s  turn %d/%d", side, idx+1, len(sess.Turns))
+	meta := ""
+	if t.Model != "" {
+		meta += "  " + t.Model
+	}
+	if u := t.TotalUsage(); !u.IsZero() {
+		meta += fmt.Sprintf("  %s out", session.FormatTokens(u.OutputTokens))
+	}
+	if t.Duration > 0 {
+		meta += "  " + FormatDuration(t.Duration)
+	}
+	return style.Render(text + lipgloss.NewStyle().Foreground(theme.ColorSecondary).Bold(false).Render(meta))
+}
+
+// toolSequence renders a turn's tool calls, highlighting those from the
+// point where the two sides diverge.
+func toolSequence(sess *session.Session, idx, divergence, width int) string {
+	if idx < 0 {
+		return ""
+	}
+	calls := session.ToolCalls(sess.Turns[idx])
+	if len(calls) == 0 {
+		return lipgloss.NewStyle().Foreground(theme.ColorDim).Render("no tool calls")
+	}
+
+	same := lipgloss.NewStyle().Foreground(theme.ColorDim)
+	diff := lipgloss.NewStyle().Foreground(theme.ColorWarning).Bold(true)
+	parts := make([]string, len(calls))
+	for i, c := range calls {
+		style := same
+		if divergence >= 0 && i >= divergence {
+			style = diff
+		}
+		parts[i] = style.Render(ToolDisplayName(c.Name))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(strings.Join(parts, same.Render(" → ")))
+}
@@ -0,0 +1,411 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// filesClosed is sent when the files view is left. Turn is the top-level
+// turn to show, or 0 to stay where the replay was.
+type filesClosed struct {
+	Turn int
+}
+
+// FilesModel lists the files a session touched next to the timeline of
+// the selected file: the diff of each tool call, or the reconstructed
+// content after it.
+type FilesModel struct {
+	files       []session.FileHistory
+	cwd         string
+	file        int // selected file
+	change      int // selected change of the file
+	offsets     []int
+	showContent bool
+	viewport    viewport.Model
+	width       int
+	height      int
+}
+
+// filesChromeHeight is the number of lines around the viewport: header,
+// change title and help.
+const filesChromeHeight = 4
+
+// NewFiles creates the files view of a session, selecting the first file
+// touched in the given turn, if any.
+func NewFiles(sess *session.Session, turn, width, height int) FilesModel {
+	m := FilesModel{
+		files:  session.FileHistories(sess),
+		cwd:    sess.CWD,
+		width:  width,
+		height: height,
+	}
+	m.selectTurn(turn)
+	m.initViewport()
+	return m
+}
+
+// selectTurn selects the first change made in a turn.
+func (m *FilesModel) selectTurn(turn int) {
+	for i, h := range m.files {
+		for j, c := range h.Changes {
+			if c.Turn == turn {
+				m.file, m.change = i, j
+				return
+			}
+		}
+	}
+}
+
+func (m *FilesModel) listWidth() int {
+	return min(40, m.width/3)
+}
+
+func (m *FilesModel) initViewport() {
+	h := m.height - filesChromeHeight
+	if h < 5 {
+		h = 5
+	}
+	m.viewport = viewport.New(m.width-m.listWidth()-3, h)
+	m.updateContent()
+}
+
+// selectFile selects file i and its first change.
+func (m *FilesModel) selectFile(i int) {
+	m.file = i
+	m.change = 0
+	m.updateContent()
+}
+
+// selectChange selects change i of the current file and scrolls to it.
+func (m *FilesModel) selectChange(i int) {
+	m.change = i
+	m.updateContent()
+}
+
+func (m *FilesModel) updateContent() {
+	if len(m.files) == 0 {
+		return
+	}
+	h := m.files[m.file]
+	if m.showContent {
+		m.viewport.SetContent(m.renderContent(h.Changes[m.change]))
+		m.viewport.GotoTop()
+		return
+	}
+
+	var b strings.Builder
+	m.offsets = m.offsets[:0]
+	line := 0
+	for i, c := range h.Changes {
+		m.offsets = append(m.offsets, line)
+		block := m.renderChange(h.Path, c, i == m.change)
+		b.WriteString(block)
+		b.WriteString("\n\n")
+		line += strings.Count(block, "\n") + 2
+	}
+	m.viewport.SetContent(strings.TrimRight(b.String(), "\n"))
+	m.viewport.SetYOffset(m.offsets[m.change])
+}
+
+// renderChange renders one tool call of a file's timeline.
+func (m *FilesModel) renderChange(path string, c session.FileChange, selected bool) string {
+	marker := "  "
+	style := lipgloss.NewStyle().Foreground(theme.ColorSecondary)
+	if selected {
+		marker = "▶ "
+		style = lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true)
+	}
+	title := style.Render(marker + FileChangeTitle(c))
+
+	dim := lipgloss.NewStyle().Foreground(theme.ColorDim).PaddingLeft(4)
+	switch {
+	case c.IsError:
+		return title + "\n" + lipgloss.NewStyle().Foreground(theme.ColorError).PaddingLeft(4).
+			Render("failed, not applied")
+	case c.Op == session.FileRead:
+		if c.AfterKnown {
+			return title + "\n" + dim.Render(fmt.Sprintf("%d lines", lineCount(c.After)))
+		}
+		return title + "\n" + dim.Render("partial read")
+	}
+
+	ops := ChangeDiff(c)
+	if len(ops) == 0 {
+		return title + "\n" + dim.Render("no changes")
+	}
+	diffWidth := max(20, m.viewport.Width-4)
+	lexer := getLexer(path)
+	ctxStyle := lipgloss.NewStyle().Foreground(theme.ColorDiffCtx).Width(diffWidth)
+	lines := []string{title}
+	for _, op := range ops {
+		var rendered string
+		switch op.Kind {
+		case '-':
+			rendered = highlightDiffLine("- ", op.Text, lexer, theme.ColorDiffDelBg, theme.ColorDiffDelFg, diffWidth)
+		case '+':
+			rendered = highlightDiffLine("+ ", op.Text, lexer, theme.ColorDiffAddBg, theme.ColorDiffAddFg, diffWidth)
+		case '@':
+			rendered = lipgloss.NewStyle().Foreground(theme.ColorDim).Render("  " + op.Text)
+		default:
+			rendered = ctxStyle.Render("  " + op.Text)
+		}
+		lines = append(lines, "    "+rendered)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderContent shows the file content after a change, with line numbers.
+func (m *FilesModel) renderContent(c session.FileChange) string {
+	if !c.AfterKnown {
+		return lipgloss.NewStyle().Foreground(theme.ColorDim).Italic(true).PaddingLeft(2).
+			Render("Content unknown at this point: no complete Read or Write of the file\nprecedes it, or an edit did not apply to the known content.")
+	}
+	num := lipgloss.NewStyle().Foreground(theme.ColorDim)
+	lines := splitLines(strings.TrimSuffix(c.After, "\n"))
+	width := len(fmt.Sprint(len(lines)))
+	var b strings.Builder
+	for i, line := range lines {
+		fmt.Fprintf(&b, "%s  %s\n", num.Render(fmt.Sprintf("%*d", width, i+1)), line)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (m FilesModel) Update(msg tea.Msg) (FilesModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if len(m.files) == 0 {
+			return m, func() tea.Msg { return filesClosed{} }
+		}
+		changes := len(m.files[m.file].Changes)
+
+		switch {
+		case key.Matches(msg, theme.DefaultKeyMap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, theme.DefaultKeyMap.Back), key.Matches(msg, theme.DefaultKeyMap.Files):
+			return m, func() tea.Msg { return filesClosed{} }
+		case key.Matches(msg, theme.DefaultKeyMap.Select):
+			turn := m.files[m.file].Changes[m.change].Turn
+			return m, func() tea.Msg { return filesClosed{Turn: turn} }
+
+		case key.Matches(msg, theme.DefaultKeyMap.ScrollDown):
+			if m.file < len(m.files)-1 {
+				m.selectFile(m.file + 1)
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.ScrollUp):
+			if m.file > 0 {
+				m.selectFile(m.file - 1)
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.NextTurn):
+			if m.change < changes-1 {
+				m.selectChange(m.change + 1)
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.PrevTurn):
+			if m.change > 0 {
+				m.selectChange(m.change - 1)
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.FirstTurn):
+			m.selectChange(0)
+		case key.Matches(msg, theme.DefaultKeyMap.LastTurn):
+			m.selectChange(changes - 1)
+		case key.Matches(msg, theme.DefaultKeyMap.ToggleContent):
+			m.showContent = !m.showContent
+			m.updateContent()
+		case key.Matches(msg, theme.DefaultKeyMap.PageDown):
+			m.viewport.HalfViewDown()
+		case key.Matches(msg, theme.DefaultKeyMap.PageUp):
+			m.viewport.HalfViewUp()
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.initViewport()
+	}
+	return m, nil
+}
+
+func (m FilesModel) View() string {
+	title := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true).Render("> claude-replay")
+	header := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(theme.ColorDim).
+		Width(m.width).
+		Render(title + lipgloss.NewStyle().Foreground(theme.ColorAccent).
+			Render(fmt.Sprintf("  Files (%d)", len(m.files))))
+
+	if len(m.files) == 0 {
+		return header + "\n\n  No files were read or written in this session.\n\n  Press any key to go back."
+	}
+
+	h := m.files[m.file]
+	c := h.Changes[m.change]
+	mode := "diff"
+	if m.showContent {
+		mode = "content after"
+	}
+	changeTitle := lipgloss.NewStyle().Foreground(theme.ColorSecondary).
+		Render(fmt.Sprintf("%s · %s %s · %d/%d", ShortenPath(h.Path, m.cwd), mode, FileChangeTitle(c), m.change+1, len(h.Changes)))
+
+	lw := m.listWidth()
+	list := lipgloss.NewStyle().Width(lw).Height(m.viewport.Height + 1).Render(m.renderList(lw))
+	sep := lipgloss.NewStyle().Foreground(theme.ColorDim).
+		Render(strings.TrimSuffix(strings.Repeat(" │ \n", m.viewport.Height+1), "\n"))
+	right := changeTitle + "\n" + m.viewport.View()
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, sep, right)
+
+	help := lipgloss.NewStyle().Foreground(theme.ColorDim).PaddingLeft(1).
+		Render("↑/↓ file • ←/→ change • c content/diff • enter go to turn • PgUp/PgDn scroll • esc back")
+	return header + "\n" + body + "\n" + help
+}
+
+// renderList renders the file list, keeping the selected file visible.
+func (m FilesModel) renderList(width int) string {
+	height := m.viewport.Height + 1
+	start := 0
+	if m.file >= height {
+		start = m.file - height + 1
+	}
+
+	var lines []string
+	for i := start; i < len(m.files) && i < start+height; i++ {
+		h := m.files[i]
+		counts := fmt.Sprintf("%dE %dW %dR", h.Count(session.FileEdit), h.Count(session.FileWrite), h.Count(session.FileRead))
+		name := truncateString(ShortenPath(h.Path, m.cwd), max(5, width-len(counts)-3))
+		gap := max(1, width-2-lipgloss.Width(name)-len(counts))
+		line := name + strings.Repeat(" ", gap) + counts
+
+		style := lipgloss.NewStyle().Foreground(theme.ColorText)
+		if !h.Modified() {
+			style = style.Foreground(theme.ColorSecondary)
+		}
+		if i == m.file {
+			lines = append(lines, style.Bold(true).Reverse(true).Render("▸ "+line))
+		} else {
+			lines = append(lines, style.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FileChangeTitle describes a change, e.g. "turn 3 · Edit +4 -1".
+func FileChangeTitle(c session.FileChange) string {
+	title := fmt.Sprintf("turn %d · %s", c.Turn, c.Tool)
+	if c.IsError {
+		return title + " (failed)"
+	}
+	if c.Op != session.FileRead {
+		added, removed := countDiffChanges(ChangeDiff(c))
+		title += fmt.Sprintf(" +%d -%d", added, removed)
+	}
+	return title
+}
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// ChangeDiff returns the line diff of a file change: of the whole file
+// when its content before and after the call is known, otherwise of the
+// text each edit replaced (or all lines of a Write to an unknown file).
+// Long unchanged runs are collapsed into '@' ops describing the gap.
+func ChangeDiff(c session.FileChange) []DiffOp {
+	if c.IsError || c.Op == session.FileRead {
+		return nil
+	}
+	if c.BeforeKnown && c.AfterKnown {
+		ops := diffLines(c.Before, c.After)
+		if added, removed := countDiffChanges(ops); added+removed == 0 {
+			return nil
+		}
+		return TrimDiffContext(ops, diffContext)
+	}
+
+	var ops []DiffOp
+	if c.Op == session.FileWrite && c.AfterKnown {
+		for _, line := range splitLines(strings.TrimSuffix(c.After, "\n")) {
+			ops = append(ops, DiffOp{'+', line})
+		}
+		return ops
+	}
+	for i, e := range c.Edits {
+		if i > 0 {
+			ops = append(ops, DiffOp{'@', "⋯"})
+		}
+		ops = append(ops, TrimDiffContext(diffLines(e.Old, e.New), diffContext)...)
+	}
+	return ops
+}
+
+// diffLines diffs two texts line by line, ignoring a final newline.
+func diffLines(oldStr, newStr string) []DiffOp {
+	return DiffLines(splitLines(strings.TrimSuffix(oldStr, "\n")), splitLines(strings.TrimSuffix(newStr, "\n")))
+}
+
+// DiffLines is the LCS diff of two line slices with their common leading
+// and trailing lines split off first, so diffs of small edits to large
+// files stay cheap.
+func DiffLines(oldLines, newLines []string) []DiffOp {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffOp
+	for _, line := range oldLines[:prefix] {
+		ops = append(ops, DiffOp{' ', line})
+	}
+	ops = append(ops, lcsDiff(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		ops = append(ops, DiffOp{' ', line})
+	}
+	return ops
+}
+
+// TrimDiffContext keeps n unchanged lines around each change and replaces
+// longer unchanged runs with a single '@' op.
+func TrimDiffContext(ops []DiffOp, n int) []DiffOp {
+	var out []DiffOp
+	for i := 0; i < len(ops); {
+		if ops[i].Kind != ' ' {
+			out = append(out, ops[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(ops) && ops[j].Kind == ' ' {
+			j++
+		}
+		keepBefore, keepAfter := n, n
+		if i == 0 {
+			keepBefore = 0
+		}
+		if j == len(ops) {
+			keepAfter = 0
+		}
+		if j-i <= keepBefore+keepAfter {
+			out = append(out, ops[i:j]...)
+		} else {
+			out = append(out, ops[i:i+keepBefore]...)
+			out = append(out, DiffOp{'@', fmt.Sprintf("⋯ %d unchanged lines", j-i-keepBefore-keepAfter)})
+			out = append(out, ops[j-keepAfter:j]...)
+		}
+		i = j
+	}
+	return out
+}
+
+func lineCount(s string) int {
+	return len(splitLines(strings.TrimSuffix(s, "\n")))
+}
@@ -1,9 +1,13 @@
 package replay
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/Trailblaze-work/claude-replay/internal/session"
@@ -17,25 +21,93 @@ type BackToList struct{}
 // autoPlayTick is sent during autoplay mode.
 type autoPlayTick struct{}
 
+// followInterval is how often a followed session file is polled.
+const followInterval = 500 * time.Millisecond
+
+// followTick schedules the next poll of a followed session.
+type followTick struct{}
+
+// followPolledMsg carries the result of polling a followed session.
+type followPolledMsg struct {
+	follower *session.Follower
+	session  *session.Session // nil when nothing changed
+	err      error
+}
+
+// subagentFrame is a parent transcript saved while drilled into a subagent.
+type subagentFrame struct {
+	turns       []session.Turn
+	currentTurn int
+	subagentIdx int
+	visible     int
+	focus       int
+	title       string
+}
+
+// blockKey identifies a block across turns and transcripts.
+type blockKey struct {
+	turn   string // UUID
+	number int
+	block  int
+}
+
 // Model is the replay screen model.
 type Model struct {
 	session       *session.Session
-	currentTurn   int // 0-indexed
+	turns         []session.Turn  // turns being shown: the session's or a subagent's
+	stack         []subagentFrame // parent transcripts, innermost last
+	title         string          // subagent description, empty at the top level
+	currentTurn   int             // 0-indexed
+	subagentIdx   int             // selected subagent within the current turn
 	viewport      viewport.Model
 	width         int
 	height        int
 	allExpanded   bool
+	expanded      map[blockKey]bool // blocks toggled with Enter, overriding allExpanded
+	focus         int               // focused block of the current turn, -1 if none
+	stepping      bool              // revealing the current turn's blocks one at a time
+	visible       int               // blocks of the current turn shown while stepping
 	showHelp      bool
 	autoPlay      bool
 	autoPlaySpeed time.Duration
+	follower      *session.Follower // non-nil while following the session file
+	followErr     error
+	redactor      *session.Redactor
+	original      *session.Session // unredacted session while previewing redaction
+	redactReport  session.RedactionReport
+	redactErr     error
+	files         *FilesModel // non-nil while the files view is open
+	snapshot      Snapshotter
+	snapshotNote  string // result of the last snapshot, until the next key
+	searching     bool   // typing a search query
+	searchInput   textinput.Model
+	query         string         // last query entered
+	search        *regexp.Regexp // compiled query, nil if no search is active
+	searchErr     error
+	matches       []searchMatch // in the transcript shown
+	matchIdx      int           // current match
+	blockLines    []int         // first line of each block of the current turn
+	notes         *session.Annotations
+	noteStore     session.AnnotationStore // nil disables bookmarks and notes
+	noteErr       error
+	notesChanged  bool // annotations not saved yet
+	annotating    bool // typing a note
+	noteInput     textinput.Model
+	noteList      *AnnotationsModel // non-nil while the annotations screen is open
 	ready         bool
 }
 
+// Snapshotter saves a rendered view, a screen width cells wide, as an
+// image at path.
+type Snapshotter func(path, view string, width int) error
+
 // New creates a new replay model for the given session.
 func New(sess *session.Session, width, height int) Model {
 	m := Model{
 		session:       sess,
+		turns:         sess.Turns,
 		currentTurn:   0,
+		focus:         -1,
 		width:         width,
 		height:        height,
 		autoPlaySpeed: 2 * time.Second,
@@ -53,65 +125,804 @@ func (m *Model) initViewport() {
 	}
 
 	m.viewport = viewport.New(m.width, contentHeight)
-	// Add j/k to viewport scroll keys
-	m.viewport.KeyMap.Up.SetKeys("up", "k")
-	m.viewport.KeyMap.Down.SetKeys("down", "j")
+	// j/k move the block focus instead of scrolling
+	m.viewport.KeyMap.Up.SetKeys("up")
+	m.viewport.KeyMap.Down.SetKeys("down")
 	m.updateContent()
 	m.ready = true
 }
 
 func (m *Model) updateContent() {
-	if len(m.session.Turns) == 0 {
+	if len(m.turns) == 0 {
 		m.viewport.SetContent("No turns to display")
 		return
 	}
 
-	turn := m.session.Turns[m.currentTurn]
-	content := RenderTurn(turn, m.allExpanded, m.width, m.session.CWD)
+	turn := m.turns[m.currentTurn]
+	if m.stepping {
+		turn = PartialTurn(turn, m.visible)
+	}
+	if m.focus >= len(turn.Blocks) {
+		m.focus = -1
+	}
+	state := func(i int) BlockState {
+		return BlockState{Expanded: m.blockExpanded(i), Focused: i == m.focus}
+	}
+	layout := layoutTurn(turn, state, m.width, m.session.CWD, m.subagentIdx)
+	content := layout.content
+	if len(m.stack) == 0 {
+		// Bookmarks and notes above the turn
+		if notes := RenderAnnotations(m.notes.ForTurn(turn.Number), m.width); notes != "" {
+			content = notes + "\n\n" + content
+			shift := strings.Count(notes, "\n") + 2
+			for i, line := range layout.blockLines {
+				if line >= 0 {
+					layout.blockLines[i] = line + shift
+				}
+			}
+		}
+	}
+	if m.redacting() {
+		content = highlightRedactions(content)
+	}
+	if m.search != nil {
+		content = highlightMatches(content, m.search)
+	}
+	m.blockLines = layout.blockLines
 	m.viewport.SetContent(content)
 }
 
-func (m Model) Init() tea.Cmd {
+// blockKey returns the key of block i of the current turn.
+func (m *Model) blockKey(i int) blockKey {
+	turn := m.turns[m.currentTurn]
+	return blockKey{turn: turn.UUID, number: turn.Number, block: i}
+}
+
+// blockExpanded reports whether block i of the current turn is shown in
+// full: as last toggled with Enter, or else when everything is expanded or
+// the block matches the search.
+func (m *Model) blockExpanded(i int) bool {
+	if expanded, ok := m.expanded[m.blockKey(i)]; ok {
+		return expanded
+	}
+	if m.allExpanded {
+		return true
+	}
+	for _, match := range m.matches {
+		if match.turn == m.currentTurn && match.block == i {
+			return true
+		}
+	}
+	return false
+}
+
+// moveFocus moves the focus cursor to the next block shown, or the
+// previous one with a negative delta.
+func (m *Model) moveFocus(delta int) {
+	if len(m.turns) == 0 {
+		return
+	}
+	i := m.focus
+	if i < 0 && delta < 0 {
+		i = len(m.blockLines)
+	}
+	for {
+		i += delta
+		if i < 0 || i >= len(m.blockLines) {
+			return
+		}
+		if m.blockLines[i] >= 0 {
+			break
+		}
+	}
+	m.focus = i
+
+	// Focusing a subagent call selects it for Enter
+	sub := 0
+	for _, block := range m.turns[m.currentTurn].Blocks[:i] {
+		if len(block.Sidechain) > 0 {
+			sub++
+		}
+	}
+	if len(m.turns[m.currentTurn].Blocks[i].Sidechain) > 0 {
+		m.subagentIdx = sub
+	}
+	m.updateContent()
+	m.scrollToFocus()
+}
+
+// toggleFocused expands or collapses the focused block. The choice is kept
+// when moving to other turns.
+func (m *Model) toggleFocused() {
+	if m.expanded == nil {
+		m.expanded = map[blockKey]bool{}
+	}
+	m.expanded[m.blockKey(m.focus)] = !m.blockExpanded(m.focus)
+	m.updateContent()
+	m.scrollToFocus()
+}
+
+// scrollToFocus scrolls the focused block's first line into view.
+func (m *Model) scrollToFocus() {
+	if m.focus < 0 || m.focus >= len(m.blockLines) {
+		return
+	}
+	line := m.blockLines[m.focus]
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line)
+	}
+}
+
+// gotoTurn switches to turn i of the current transcript.
+func (m *Model) gotoTurn(i int) {
+	m.currentTurn = i
+	m.subagentIdx = 0
+	m.focus = -1
+	m.visible = 0
+	m.updateContent()
+	m.viewport.GotoTop()
+}
+
+// JumpToTurn shows the top-level turn with the given 1-based number.
+func (m *Model) JumpToTurn(number int) {
+	if number < 1 || number > len(m.turns) {
+		return
+	}
+	m.gotoTurn(number - 1)
+}
+
+// subagentCount returns the number of subagents in the current turn.
+func (m *Model) subagentCount() int {
+	if len(m.turns) == 0 {
+		return 0
+	}
+	return len(subagentBlocks(m.turns[m.currentTurn]))
+}
+
+// enterSubagent drills into the selected subagent of the current turn.
+func (m *Model) enterSubagent() {
+	if m.subagentIdx >= m.subagentCount() {
+		return
+	}
+	block := subagentBlocks(m.turns[m.currentTurn])[m.subagentIdx]
+
+	m.stack = append(m.stack, subagentFrame{
+		turns:       m.turns,
+		currentTurn: m.currentTurn,
+		subagentIdx: m.subagentIdx,
+		visible:     m.visible,
+		focus:       m.focus,
+		title:       m.title,
+	})
+	m.turns = block.Sidechain
+	m.refreshMatches()
+	m.title = ToolBriefParam(block, m.session.CWD)
+	if m.title == "" {
+		m.title = block.ToolName
+	}
+	m.gotoTurn(0)
+}
+
+// exitSubagent returns to the parent transcript.
+func (m *Model) exitSubagent() {
+	frame := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	m.turns = frame.turns
+	m.refreshMatches()
+	m.title = frame.title
+	m.currentTurn = frame.currentTurn
+	m.subagentIdx = frame.subagentIdx
+	m.visible = frame.visible
+	m.focus = frame.focus
+	m.updateContent()
+	m.viewport.GotoTop()
+}
+
+// toggleStepping switches step mode, which reveals the blocks of a turn one
+// at a time starting from its prompt.
+func (m *Model) toggleStepping() {
+	m.stepping = !m.stepping
+	m.visible = 0
+	m.updateContent()
+	m.viewport.GotoTop()
+}
+
+// stepForward reveals the next block, moving on to the next turn once the
+// current one is complete. It reports false at the end of the transcript.
+func (m *Model) stepForward() bool {
+	if len(m.turns) == 0 {
+		return false
+	}
+	if m.visible < len(m.turns[m.currentTurn].Blocks) {
+		m.visible++
+		m.showStep()
+		return true
+	}
+	if m.currentTurn < len(m.turns)-1 {
+		m.gotoTurn(m.currentTurn + 1)
+		return true
+	}
+	return false
+}
+
+// stepBack hides the newest block, moving back to the end of the previous
+// turn from a turn's prompt.
+func (m *Model) stepBack() {
+	if len(m.turns) == 0 {
+		return
+	}
+	if m.visible > 0 {
+		m.visible--
+		m.showStep()
+		return
+	}
+	if m.currentTurn > 0 {
+		m.gotoTurn(m.currentTurn - 1)
+		m.visible = len(m.turns[m.currentTurn].Blocks)
+		m.showStep()
+	}
+}
+
+// showStep renders the current step, scrolled to the newest block.
+func (m *Model) showStep() {
+	m.updateContent()
+	m.viewport.GotoBottom()
+}
+
+// openFiles shows the files view, starting at the files touched in the
+// current top-level turn.
+func (m *Model) openFiles() {
+	idx := m.currentTurn
+	if len(m.stack) > 0 {
+		idx = m.stack[0].currentTurn
+	}
+	turn := 0
+	if idx < len(m.session.Turns) {
+		turn = m.session.Turns[idx].Number
+	}
+	files := NewFiles(m.session, turn, m.width, m.height)
+	m.files = &files
+}
+
+// closeFiles leaves the files view, going to a top-level turn if given.
+func (m *Model) closeFiles(turn int) {
+	m.files = nil
+	if turn == 0 {
+		return
+	}
+	for len(m.stack) > 0 {
+		m.exitSubagent()
+	}
+	m.JumpToTurn(turn)
+}
+
+// SetRedactor sets the rules used by the redaction preview. Without one,
+// the default rules file is loaded on first use.
+func (m *Model) SetRedactor(r *session.Redactor) {
+	m.redactor = r
+}
+
+// SetSnapshotter sets how the snapshot key saves the current view.
+func (m *Model) SetSnapshotter(s Snapshotter) {
+	m.snapshot = s
+}
+
+// takeSnapshot saves the current view as an SVG in the working directory.
+// While the redaction preview is on the view, and so the image, is the
+// redacted one; its name says so.
+func (m *Model) takeSnapshot() {
+	if m.snapshot == nil || len(m.turns) == 0 {
+		return
+	}
+	slug := m.session.Slug
+	if slug == "" && len(m.session.ID) > 8 {
+		slug = m.session.ID[:8]
+	}
+	path := fmt.Sprintf("%s-turn%d.svg", slug, m.turns[m.currentTurn].Number)
+	if m.redacting() {
+		path = strings.TrimSuffix(path, ".svg") + "-redacted.svg"
+	}
+	if err := m.snapshot(path, m.View(), m.width); err != nil {
+		m.snapshotNote = "snapshot failed: " + err.Error()
+		return
+	}
+	m.snapshotNote = "saved " + path
+}
+
+// startSearch shows the search prompt.
+func (m *Model) startSearch() {
+	m.searchInput = textinput.New()
+	m.searchInput.Prompt = "/ "
+	m.searchInput.Placeholder = "text or /regex/"
+	m.searchInput.SetValue(m.query)
+	m.searchInput.CursorEnd()
+	m.searchInput.Focus()
+	m.searching = true
+}
+
+// setSearch searches the transcript for query and shows the first match
+// from the current turn on. An empty query clears the search.
+func (m *Model) setSearch(query string) {
+	m.query = query
+	m.searchErr = nil
+	if query == "" {
+		m.clearSearch()
+		return
+	}
+	re, err := compileSearch(query)
+	if err != nil {
+		m.clearSearch()
+		m.searchErr = err
+		return
+	}
+	m.search = re
+	m.matches = findMatches(m.turns, re)
+	m.matchIdx = 0
+	for i, match := range m.matches {
+		if match.turn >= m.currentTurn {
+			m.matchIdx = i
+			break
+		}
+	}
+	if len(m.matches) == 0 {
+		m.updateContent()
+		return
+	}
+	m.gotoMatch(m.matchIdx)
+}
+
+// refreshMatches searches the turns shown again after they changed; the
+// matches are kept otherwise, so moving around does not rescan them.
+func (m *Model) refreshMatches() {
+	if m.search == nil {
+		return
+	}
+	m.matches = findMatches(m.turns, m.search)
+	if m.matchIdx >= len(m.matches) {
+		m.matchIdx = 0
+	}
+}
+
+// clearSearch ends the search, removing its highlights.
+func (m *Model) clearSearch() {
+	m.search = nil
+	m.matches = nil
+	m.matchIdx = 0
+	m.updateContent()
+}
+
+// stepMatch moves to the next match, or the previous one with a negative
+// delta, wrapping around the transcript.
+func (m *Model) stepMatch(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.gotoMatch((m.matchIdx + delta + len(m.matches)) % len(m.matches))
+}
+
+// gotoMatch shows match i, scrolled to its block.
+func (m *Model) gotoMatch(i int) {
+	m.matchIdx = i
+	match := m.matches[i]
+	if match.turn != m.currentTurn {
+		m.gotoTurn(match.turn)
+	}
+	if m.stepping && m.visible <= match.block {
+		m.visible = match.block + 1
+	}
+	m.focus = match.block
+	m.updateContent()
+	m.viewport.GotoTop()
+	if match.block >= 0 && match.block < len(m.blockLines) && m.blockLines[match.block] > 0 {
+		m.viewport.SetYOffset(m.blockLines[match.block])
+	}
+}
+
+// SetAnnotationStore sets where bookmarks and notes are kept, and loads
+// the session's.
+func (m *Model) SetAnnotationStore(store session.AnnotationStore) {
+	m.noteStore = store
+	a, err := store.LoadAnnotations(m.session.ID)
+	if err != nil {
+		m.noteErr = err
+		a = &session.Annotations{SessionID: m.session.ID}
+	}
+	m.notes = a
+	m.updateContent()
+}
+
+// annotationTarget returns the top-level turn number and 1-based block an
+// annotation made now is about: the focused block, or the whole turn.
+// Inside a subagent, it is the turn that started it.
+func (m *Model) annotationTarget() (int, int) {
+	if len(m.stack) > 0 {
+		frame := m.stack[0]
+		return frame.turns[frame.currentTurn].Number, 0
+	}
+	return m.turns[m.currentTurn].Number, m.focus + 1
+}
+
+// toggleBookmark bookmarks the focused block or the current turn, or
+// removes its annotation.
+func (m *Model) toggleBookmark() {
+	if m.noteStore == nil || len(m.turns) == 0 {
+		return
+	}
+	turn, block := m.annotationTarget()
+	if !m.notes.Remove(turn, block) {
+		m.notes.Set(session.Annotation{Turn: turn, Block: block, Created: time.Now()})
+	}
+	m.notesChanged = true
+	m.updateContent()
+}
+
+// startNote shows the note prompt, filled with the existing note.
+func (m *Model) startNote() {
+	if m.noteStore == nil || len(m.turns) == 0 {
+		return
+	}
+	m.noteInput = textinput.New()
+	m.noteInput.Prompt = components.BookmarkSymbol + " "
+	m.noteInput.Placeholder = "note"
+	if i := m.notes.Find(m.annotationTarget()); i >= 0 {
+		m.noteInput.SetValue(m.notes.Items[i].Note)
+	}
+	m.noteInput.CursorEnd()
+	m.noteInput.Focus()
+	m.annotating = true
+}
+
+// setNote annotates the focused block or the current turn with note.
+func (m *Model) setNote(note string) {
+	turn, block := m.annotationTarget()
+	item := session.Annotation{Turn: turn, Block: block, Note: strings.TrimSpace(note), Created: time.Now()}
+	if i := m.notes.Find(turn, block); i >= 0 {
+		item.Created = m.notes.Items[i].Created
+	}
+	m.notes.Set(item)
+	m.notesChanged = true
+	m.updateContent()
+}
+
+// saveAnnotations writes the annotations changed since the last save.
+// Changes are kept until the annotations screen or the replay is left,
+// so a store that commits each save is not written on every key.
+func (m *Model) saveAnnotations() {
+	if !m.notesChanged {
+		return
+	}
+	m.noteErr = m.noteStore.SaveAnnotations(m.notes)
+	if m.noteErr == nil {
+		m.notesChanged = false
+	}
+	m.updateContent()
+}
+
+// closeAnnotations leaves the annotations screen, going to a top-level
+// turn and focusing a block if given.
+func (m *Model) closeAnnotations(turn, block int) {
+	m.noteList = nil
+	if turn == 0 {
+		return
+	}
+	for len(m.stack) > 0 {
+		m.exitSubagent()
+	}
+	m.JumpToTurn(turn)
+	if block > 0 && block <= len(m.turns[m.currentTurn].Blocks) {
+		m.focus = block - 1
+		m.updateContent()
+		m.scrollToFocus()
+	}
+}
+
+// redacting reports whether the redaction preview is on.
+func (m *Model) redacting() bool {
+	return m.original != nil
+}
+
+// toggleRedaction switches between the session and its redacted copy,
+// keeping the current position.
+func (m *Model) toggleRedaction() {
+	if m.redacting() {
+		sess := m.original
+		m.original = nil
+		m.redactReport = nil
+		m.rebase(sess)
+		return
+	}
+
+	if m.redactor == nil {
+		r, err := session.LoadRedactor(session.DefaultRedactRulesPath(), false)
+		if err != nil {
+			m.redactErr = err
+			return
+		}
+		m.redactor = r
+	}
+	m.redactErr = nil
+	m.original = m.session
+	redacted, report := m.redactor.Session(m.session)
+	m.redactReport = report
+	m.rebase(redacted)
+}
+
+// redactionMarker matches the markers left by session.Redactor.
+var redactionMarker = regexp.MustCompile(`\[REDACTED:[^\]\n]*\]`)
+
+// highlightRedactions makes redaction markers stand out in rendered output.
+func highlightRedactions(content string) string {
+	return redactionMarker.ReplaceAllStringFunc(content, func(s string) string {
+		return theme.StyleRedacted.Render(s)
+	})
+}
+
+// rebase swaps in a session with the same structure (e.g. its redacted
+// copy), following the subagent path currently drilled into.
+func (m *Model) rebase(sess *session.Session) {
+	m.session = sess
+	turns := sess.Turns
+	for i := range m.stack {
+		m.stack[i].turns = turns
+		frame := m.stack[i]
+		turns = subagentBlocks(turns[frame.currentTurn])[frame.subagentIdx].Sidechain
+	}
+	m.turns = turns
+	m.refreshMatches()
+	m.updateContent()
+}
+
+// fork returns the index of the fork the current top-level turn starts a
+// branch of, or -1.
+func (m *Model) fork() int {
+	if len(m.stack) > 0 || len(m.turns) == 0 {
+		return -1
+	}
+	return m.session.ForkAt(m.turns[m.currentTurn].Number)
+}
+
+// switchBranch replays the next branch of the fork at the current turn,
+// staying on the turn that starts it.
+func (m *Model) switchBranch() {
+	f := m.fork()
+	if f < 0 {
+		return
+	}
+	fork := m.session.Forks[f]
+	next := (fork.Selected + 1) % len(fork.Branches)
+
+	var sess *session.Session
+	if m.redacting() {
+		m.original = m.original.WithBranch(f, next)
+		sess, m.redactReport = m.redactor.Session(m.original)
+	} else {
+		sess = m.session.WithBranch(f, next)
+	}
+	m.session = sess
+	m.turns = sess.Turns
+	m.refreshMatches()
+	if n := sess.Forks[f].Turn; n > 0 {
+		m.currentTurn = n - 1
+	}
+	m.subagentIdx = 0
+	m.updateContent()
+	m.viewport.GotoTop()
+}
+
+// StartFollow tails the session file, pinning the view to the latest turn
+// until the user navigates away. Polling starts with Init or the returned
+// command.
+func (m *Model) StartFollow() error {
+	f, err := session.NewFollower(m.session.Path)
+	if err != nil {
+		m.followErr = err
+		return err
+	}
+	m.follower = f
+	m.followErr = nil
+	if len(m.stack) == 0 && len(m.turns) > 0 {
+		m.gotoTurn(len(m.turns) - 1)
+		m.viewport.GotoBottom()
+	}
 	return nil
 }
 
+// pinned reports whether the view is at the end of the latest top-level turn.
+func (m *Model) pinned() bool {
+	if len(m.stack) > 0 {
+		return false
+	}
+	return len(m.turns) == 0 || (m.currentTurn == len(m.turns)-1 && m.viewport.AtBottom())
+}
+
+// applyFollowUpdate swaps in a session rebuilt from the growing file.
+func (m *Model) applyFollowUpdate(sess *session.Session) {
+	pinned := m.pinned()
+	// Stay on the branches picked with the fork selector
+	if m.redacting() {
+		m.original = sess.KeepBranches(m.original)
+		sess, m.redactReport = m.redactor.Session(m.original)
+	} else {
+		sess = sess.KeepBranches(m.session)
+	}
+	m.session = sess
+
+	// While drilled into a subagent, refresh the top-level transcript only
+	if len(m.stack) > 0 {
+		m.stack[0].turns = sess.Turns
+		return
+	}
+
+	m.turns = sess.Turns
+	m.refreshMatches()
+	if len(m.turns) == 0 {
+		m.updateContent()
+		return
+	}
+	if pinned {
+		if m.currentTurn != len(m.turns)-1 {
+			m.currentTurn = len(m.turns) - 1
+			m.subagentIdx = 0
+		}
+		if m.stepping {
+			m.visible = len(m.turns[m.currentTurn].Blocks)
+		}
+		m.updateContent()
+		m.viewport.GotoBottom()
+		return
+	}
+	if m.currentTurn >= len(m.turns) {
+		m.currentTurn = len(m.turns) - 1
+	}
+	m.updateContent()
+}
+
+func (m Model) pollFollow() tea.Cmd {
+	f := m.follower
+	if f == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		sess, err := f.Poll()
+		return followPolledMsg{follower: f, session: sess, err: err}
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.pollFollow()
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.searching && !m.annotating && key.Matches(msg, theme.DefaultKeyMap.Quit) {
+		m.saveAnnotations()
+	}
+
+	if m.files != nil {
+		switch msg.(type) {
+		case tea.KeyMsg, tea.WindowSizeMsg:
+			files, cmd := m.files.Update(msg)
+			m.files = &files
+			if _, ok := msg.(tea.WindowSizeMsg); !ok {
+				return m, cmd
+			}
+		}
+	}
+
+	if m.noteList != nil {
+		switch msg.(type) {
+		case tea.KeyMsg, tea.WindowSizeMsg:
+			list, cmd := m.noteList.Update(msg)
+			m.noteList = &list
+			if _, ok := msg.(tea.WindowSizeMsg); !ok {
+				return m, cmd
+			}
+		}
+	}
+
 	switch msg := msg.(type) {
+	case filesClosed:
+		m.closeFiles(msg.Turn)
+		return m, nil
+	case annotationsClosed:
+		m.saveAnnotations()
+		m.closeAnnotations(msg.Turn, msg.Block)
+		return m, nil
+
 	case tea.KeyMsg:
+		m.snapshotNote = ""
 		if m.showHelp {
 			m.showHelp = false
 			return m, nil
 		}
+		if m.searching {
+			switch msg.Type {
+			case tea.KeyEnter:
+				m.searching = false
+				m.setSearch(m.searchInput.Value())
+			case tea.KeyEsc:
+				m.searching = false
+			default:
+				var cmd tea.Cmd
+				m.searchInput, cmd = m.searchInput.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+		if m.annotating {
+			switch msg.Type {
+			case tea.KeyEnter:
+				m.annotating = false
+				m.setNote(m.noteInput.Value())
+			case tea.KeyEsc:
+				m.annotating = false
+			default:
+				var cmd tea.Cmd
+				m.noteInput, cmd = m.noteInput.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
 
 		switch {
 		case key.Matches(msg, theme.DefaultKeyMap.Quit):
 			return m, tea.Quit
 		case key.Matches(msg, theme.DefaultKeyMap.Back):
+			if m.search != nil || m.searchErr != nil {
+				m.searchErr = nil
+				m.clearSearch()
+				return m, nil
+			}
+			if len(m.stack) > 0 {
+				m.exitSubagent()
+				return m, nil
+			}
+			m.saveAnnotations()
 			return m, func() tea.Msg { return BackToList{} }
 
 		case key.Matches(msg, theme.DefaultKeyMap.NextTurn):
-			if m.currentTurn < len(m.session.Turns)-1 {
-				m.currentTurn++
-				m.updateContent()
-				m.viewport.GotoTop()
+			if m.stepping {
+				m.stepForward()
+			} else if m.currentTurn < len(m.turns)-1 {
+				m.gotoTurn(m.currentTurn + 1)
 			}
 		case key.Matches(msg, theme.DefaultKeyMap.PrevTurn):
-			if m.currentTurn > 0 {
-				m.currentTurn--
-				m.updateContent()
-				m.viewport.GotoTop()
+			if m.stepping {
+				m.stepBack()
+			} else if m.currentTurn > 0 {
+				m.gotoTurn(m.currentTurn - 1)
 			}
 		case key.Matches(msg, theme.DefaultKeyMap.FirstTurn):
-			m.currentTurn = 0
-			m.updateContent()
-			m.viewport.GotoTop()
+			m.gotoTurn(0)
 		case key.Matches(msg, theme.DefaultKeyMap.LastTurn):
-			m.currentTurn = len(m.session.Turns) - 1
-			m.updateContent()
-			m.viewport.GotoTop()
+			if len(m.turns) == 0 {
+				return m, nil
+			}
+			m.gotoTurn(len(m.turns) - 1)
+			if m.stepping {
+				m.visible = len(m.turns[m.currentTurn].Blocks)
+				m.showStep()
+			}
+		case key.Matches(msg, theme.DefaultKeyMap.Step):
+			m.toggleStepping()
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Select):
+			if m.focus >= 0 && len(m.turns[m.currentTurn].Blocks[m.focus].Sidechain) == 0 {
+				m.toggleFocused()
+			} else {
+				m.enterSubagent()
+			}
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.NextBlock):
+			m.moveFocus(1)
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.PrevBlock):
+			m.moveFocus(-1)
+			return m, nil
 
 		case key.Matches(msg, theme.DefaultKeyMap.ExpandTool):
 			m.allExpanded = !m.allExpanded
+			m.expanded = nil
 			m.updateContent()
 
 		case key.Matches(msg, theme.DefaultKeyMap.AutoPlay):
@@ -120,6 +931,55 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				return m, m.autoPlayCmd()
 			}
 
+		case key.Matches(msg, theme.DefaultKeyMap.Follow):
+			if m.follower != nil {
+				m.follower = nil
+				return m, nil
+			}
+			if err := m.StartFollow(); err != nil {
+				return m, nil
+			}
+			return m, m.pollFollow()
+
+		case key.Matches(msg, theme.DefaultKeyMap.Files):
+			m.openFiles()
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Branch):
+			m.switchBranch()
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Redact):
+			m.toggleRedaction()
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Snapshot):
+			m.takeSnapshot()
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Bookmark):
+			m.toggleBookmark()
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.Annotate):
+			m.startNote()
+			return m, textinput.Blink
+		case key.Matches(msg, theme.DefaultKeyMap.Annotations):
+			if m.noteStore != nil {
+				list := NewAnnotations(m.session, m.notes, m.width, m.height)
+				m.noteList = &list
+			}
+			return m, nil
+
+		case key.Matches(msg, theme.DefaultKeyMap.Filter):
+			m.startSearch()
+			return m, textinput.Blink
+		case key.Matches(msg, theme.DefaultKeyMap.NextMatch):
+			m.stepMatch(1)
+			return m, nil
+		case key.Matches(msg, theme.DefaultKeyMap.PrevMatch):
+			m.stepMatch(-1)
+			return m, nil
+
 		case key.Matches(msg, theme.DefaultKeyMap.SpeedUp):
 			if m.autoPlaySpeed > 500*time.Millisecond {
 				m.autoPlaySpeed -= 500 * time.Millisecond
@@ -135,14 +995,36 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if !m.autoPlay {
 			return m, nil
 		}
-		if m.currentTurn < len(m.session.Turns)-1 {
-			m.currentTurn++
-			m.updateContent()
-			m.viewport.GotoTop()
+		if m.stepping {
+			if m.stepForward() {
+				return m, m.autoPlayCmd()
+			}
+		} else if m.currentTurn < len(m.turns)-1 {
+			m.gotoTurn(m.currentTurn + 1)
 			return m, m.autoPlayCmd()
 		}
 		m.autoPlay = false
 
+	case followTick:
+		return m, m.pollFollow()
+
+	case followPolledMsg:
+		// Ignore polls from a follow that was stopped or restarted since
+		if msg.follower != m.follower {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.follower = nil
+			m.followErr = msg.err
+			return m, nil
+		}
+		if msg.session != nil {
+			m.applyFollowUpdate(msg.session)
+		}
+		return m, tea.Tick(followInterval, func(time.Time) tea.Msg {
+			return followTick{}
+		})
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -155,36 +1037,100 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m Model) autoPlayCmd() tea.Cmd {
-	return tea.Tick(m.autoPlaySpeed, func(time.Time) tea.Msg {
+	d := m.autoPlaySpeed
+	if m.stepping {
+		d /= 4
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg {
 		return autoPlayTick{}
 	})
 }
 
 func (m Model) View() string {
-	if !m.ready || len(m.session.Turns) == 0 {
+	if m.ready && len(m.turns) == 0 && m.follower != nil {
+		return "Waiting for the first turn..."
+	}
+	if !m.ready || len(m.turns) == 0 {
 		return "Loading..."
 	}
 
 	if m.showHelp {
 		return m.helpView()
 	}
+	if m.files != nil {
+		return m.files.View()
+	}
+	if m.noteList != nil {
+		return m.noteList.View()
+	}
 
-	turn := m.session.Turns[m.currentTurn]
+	turn := m.turns[m.currentTurn]
 
 	slug := m.session.Slug
 	if slug == "" && len(m.session.ID) > 8 {
 		slug = m.session.ID[:8]
 	}
+	for _, frame := range m.stack {
+		if frame.title != "" {
+			slug += " › " + frame.title
+		}
+	}
+	if m.title != "" {
+		slug += " › " + m.title
+	}
+	if m.follower != nil {
+		slug += "  ● live"
+	} else if m.followErr != nil {
+		slug += "  (follow stopped: " + m.followErr.Error() + ")"
+	}
+	if m.redacting() {
+		slug += fmt.Sprintf("  ⊘ redacted: %d", m.redactReport.Total())
+	} else if m.redactErr != nil {
+		slug += "  (" + m.redactErr.Error() + ")"
+	}
+
+	if m.stepping {
+		slug += fmt.Sprintf("  ▸ step %d/%d", m.visible, len(turn.Blocks))
+	}
+	if f := m.fork(); f >= 0 {
+		fork := m.session.Forks[f]
+		slug += fmt.Sprintf("  ⑂ branch %d/%d", fork.Selected+1, len(fork.Branches))
+	}
+	if m.snapshotNote != "" {
+		slug += "  (" + m.snapshotNote + ")"
+	}
+	switch {
+	case m.annotating:
+		slug += "  " + m.noteInput.View()
+	case m.noteErr != nil:
+		slug += "  (annotations: " + m.noteErr.Error() + ")"
+	}
+	switch {
+	case m.searching:
+		slug += "  " + m.searchInput.View()
+	case m.searchErr != nil:
+		slug += "  (" + m.searchErr.Error() + ")"
+	case m.search != nil && len(m.matches) == 0:
+		slug += "  / " + m.query + ": no matches"
+	case m.search != nil:
+		slug += fmt.Sprintf("  / %s %d/%d", m.query, m.matchIdx+1, len(m.matches))
+	}
+
+	var bookmarked map[int]bool
+	if len(m.stack) == 0 {
+		bookmarked = m.notes.Turns()
+	}
 
 	header := components.RenderHeader(slug, m.session.CWD, m.session.GitBranch, m.width)
 	content := m.viewport.View()
-	timeline := components.RenderTimeline(m.currentTurn+1, len(m.session.Turns), m.width)
+	timeline := components.RenderTimeline(m.currentTurn+1, len(m.turns), m.width, components.TurnEvents(m.turns), bookmarked)
 	status := components.RenderStatusBar(
 		m.currentTurn+1,
-		len(m.session.Turns),
+		len(m.turns),
 		turn.Model,
 		turn.Duration,
 		turn.Timestamp,
+		turn.TotalUsage(),
 		m.width,
 	)
 
@@ -199,19 +1145,34 @@ func (m Model) helpView() string {
   →/l        Next turn
   Home/g     First turn
   End/G      Last turn
-  ↑/k/↓/j   Scroll
+  ↑/↓        Scroll
+  Tab/j      Focus next block
+  S-Tab/k    Focus previous block
   PgUp/PgDn  Page up/down
 
   Display
   ───────
   Ctrl+O     Expand/collapse all
+  Enter      Expand/collapse the focused block,
+             or open a subagent transcript
   Space      Toggle autoplay
+  S          Step mode: ←/→ reveal blocks one at a time
+  f          Follow live session (tail file)
+  r          Preview redaction (as export --redact)
+  F          Files touched, with per-file history
+  b          Switch branch at a rewound prompt (⑂)
+  P          Save the current view as <slug>-turn<N>.svg
+  /          Search the transcript (text or /regex/)
+  m          Bookmark the focused block or the turn
+  a          Write a note on the focused block or the turn
+  M          List bookmarks and notes
+  n/N        Next/previous match
   +/-        Adjust autoplay speed
 
   General
   ───────
   ?          Toggle help
-  Esc        Back to session list
+  Esc        Back to parent / session list
   q          Quit
 
   Press any key to close help
@@ -0,0 +1,146 @@
+package replay
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Trailblaze-work/claude-replay/internal/session"
+	"github.com/Trailblaze-work/claude-replay/internal/ui/theme"
+)
+
+// searchMatch is a part of a transcript matching the search: a turn's
+// prompt (block -1) or one of its blocks.
+type searchMatch struct {
+	turn  int // 0-indexed
+	block int
+}
+
+// compileSearch compiles a query typed in the replay: text matched
+// case-insensitively, or a regular expression between slashes.
+func compileSearch(query string) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(query)
+	if len(query) > 1 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/") {
+		expr = query[1 : len(query)-1]
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// findMatches returns the prompts and blocks of turns matching re, in
+// transcript order. Collapsed content counts: thinking, tool inputs and
+// complete tool results.
+func findMatches(turns []session.Turn, re *regexp.Regexp) []searchMatch {
+	var matches []searchMatch
+	for t, turn := range turns {
+		if re.MatchString(turn.UserText) {
+			matches = append(matches, searchMatch{turn: t, block: -1})
+		}
+		for b, block := range turn.Blocks {
+			if blockMatches(block, re) {
+				matches = append(matches, searchMatch{turn: t, block: b})
+			}
+		}
+	}
+	return matches
+}
+
+// blockMatches reports whether any text of the block matches re. Tool
+// inputs are matched by their decoded values, as they are shown, not by
+// their JSON with its keys and escapes.
+func blockMatches(block session.Block, re *regexp.Regexp) bool {
+	for _, text := range []string{block.Text, block.ToolName, block.Detail} {
+		if text != "" && re.MatchString(text) {
+			return true
+		}
+	}
+	if block.ToolInput == nil {
+		return block.RawInput != "" && re.MatchString(block.RawInput)
+	}
+	return inputMatches(block.ToolInput, re)
+}
+
+// inputMatches reports whether a decoded JSON value holds text matching re.
+func inputMatches(v interface{}, re *regexp.Regexp) bool {
+	switch v := v.(type) {
+	case string:
+		return re.MatchString(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			if inputMatches(item, re) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if inputMatches(item, re) {
+				return true
+			}
+		}
+	case nil:
+	default:
+		return re.MatchString(fmt.Sprint(v))
+	}
+	return false
+}
+
+// sgrSequence matches the escape sequences setting colors and styles.
+var sgrSequence = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// highlightMatches marks the text matching re in rendered content. Matches
+// are found in each line's visible text, so styling inside a match does
+// not hide it; the styles in effect after a match are restored.
+func highlightMatches(content string, re *regexp.Regexp) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = highlightLine(line, re)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func highlightLine(line string, re *regexp.Regexp) string {
+	// Visible text, and where each of its bytes is in the line
+	var plain strings.Builder
+	var offsets []int
+	escapes := sgrSequence.FindAllStringIndex(line, -1)
+	next := 0
+	for i := 0; i < len(line); i++ {
+		if next < len(escapes) && i == escapes[next][0] {
+			i = escapes[next][1] - 1
+			next++
+			continue
+		}
+		plain.WriteByte(line[i])
+		offsets = append(offsets, i)
+	}
+
+	found := re.FindAllStringIndex(plain.String(), -1)
+	if len(found) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	last := 0 // bytes of line written
+	text := plain.String()
+	for _, m := range found {
+		if m[0] == m[1] {
+			continue
+		}
+		start, end := offsets[m[0]], offsets[m[1]-1]+1
+		b.WriteString(line[last:start])
+		b.WriteString(theme.StyleSearchMatch.Render(text[m[0]:m[1]]))
+
+		// Restore the styles set so far in the line
+		b.WriteString("\x1b[0m")
+		for _, e := range sgrSequence.FindAllString(line[:end], -1) {
+			b.WriteString(e)
+		}
+		// Skip the styling inside the match, keeping its effect
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
@@ -112,3 +112,33 @@ func highlightDiffLine(prefix, text string, lexer chroma.Lexer, bg, defaultFg li
 
 	return str
 }
+
+// SyntaxSpan is a run of text sharing one syntax highlighting color.
+type SyntaxSpan struct {
+	Text  string
+	Color string // hex foreground color, empty for the default text color
+}
+
+// HighlightSpans splits a single line of code from filePath into colored
+// spans using the same palette as the diff renderer. Without a lexer for
+// the file type the whole line is returned as one uncolored span.
+func HighlightSpans(filePath, text string) []SyntaxSpan {
+	lexer := getLexer(filePath)
+	if lexer == nil {
+		return []SyntaxSpan{{Text: text}}
+	}
+	iterator, err := lexer.Tokenise(nil, text)
+	if err != nil {
+		return []SyntaxSpan{{Text: text}}
+	}
+
+	var spans []SyntaxSpan
+	for _, token := range iterator.Tokens() {
+		val := strings.TrimRight(token.Value, "\n\r")
+		if val == "" {
+			continue
+		}
+		spans = append(spans, SyntaxSpan{Text: val, Color: string(tokenColor(token.Type))})
+	}
+	return spans
+}
@@ -18,7 +18,35 @@ var durationVerbs = []string{
 
 // RenderTurn renders a complete turn (user message + all blocks).
 func RenderTurn(turn session.Turn, allExpanded bool, width int, cwd string) string {
+	return renderTurn(turn, allExpanded, width, cwd, 0)
+}
+
+// renderTurn renders a turn, marking the subagent at index selectedSubagent
+// (among the turn's subagent tool calls) as the one Enter will open. A
+// negative index marks none and leaves out the key hints.
+func renderTurn(turn session.Turn, allExpanded bool, width int, cwd string, selectedSubagent int) string {
+	state := func(int) BlockState { return BlockState{Expanded: allExpanded} }
+	return layoutTurn(turn, state, width, cwd, selectedSubagent).content
+}
+
+// turnLayout is a rendered turn and where its blocks are.
+type turnLayout struct {
+	content    string
+	blockLines []int // first line of each block, -1 if it renders nothing
+}
+
+// layoutTurn renders a turn like renderTurn, showing block i as state(i)
+// says, and records the line each block starts at.
+func layoutTurn(turn session.Turn, state func(i int) BlockState, width int, cwd string, selectedSubagent int) turnLayout {
 	var parts []string
+	lines := 0 // lines in parts
+	add := func(part string) {
+		parts = append(parts, part)
+		lines += strings.Count(part, "\n") + 1
+	}
+	blockLines := make([]int, len(turn.Blocks))
+	subagents := len(subagentBlocks(turn))
+	subagentIdx := 0
 
 	// User message
 	userPrefix := lipgloss.NewStyle().
@@ -32,8 +60,8 @@ func RenderTurn(turn session.Turn, allExpanded bool, width int, cwd string) stri
 		Render(turn.UserText)
 
 	userRendered := lipgloss.NewStyle().PaddingLeft(2).Render(userPrefix + userText)
-	parts = append(parts, userRendered)
-	parts = append(parts, "") // blank line
+	add(userRendered)
+	add("") // blank line
 
 	// Build tool_use info lookup for rendering tool results with context
 	toolInputs := map[string]toolUseInfo{}
@@ -62,9 +90,15 @@ func RenderTurn(turn session.Turn, allExpanded bool, width int, cwd string) stri
 
 	// Content blocks
 	for i, block := range turn.Blocks {
-		rendered := RenderBlock(block, allExpanded, width, cwd, toolInputs, readContents)
+		rendered := RenderBlock(block, state(i), width, cwd, toolInputs, readContents)
+		if len(block.Sidechain) > 0 {
+			rendered += "\n" + renderSubagentSummary(block, subagentIdx == selectedSubagent, subagents > 1 && selectedSubagent >= 0)
+			subagentIdx++
+		}
+		blockLines[i] = -1
 		if rendered != "" {
-			parts = append(parts, rendered)
+			blockLines[i] = lines
+			add(rendered)
 
 			// Skip blank line between tool_use and its matching tool_result
 			addSpacing := true
@@ -75,7 +109,7 @@ func RenderTurn(turn session.Turn, allExpanded bool, width int, cwd string) stri
 				}
 			}
 			if addSpacing {
-				parts = append(parts, "")
+				add("")
 			}
 		}
 	}
@@ -83,15 +117,76 @@ func RenderTurn(turn session.Turn, allExpanded bool, width int, cwd string) stri
 	// Duration at the end of the turn (matches Claude Code placement)
 	if turn.Duration > 0 {
 		durLine := renderDuration(turn.Duration, turn.Number)
-		parts = append(parts, durLine)
+		add(durLine)
 	}
 
-	return strings.Join(parts, "\n")
+	return turnLayout{content: strings.Join(parts, "\n"), blockLines: blockLines}
+}
+
+// PartialTurn returns the turn with only its first visible blocks, as shown
+// while stepping through it. The duration is left out until the turn is
+// complete.
+func PartialTurn(turn session.Turn, visible int) session.Turn {
+	if visible < 0 || visible >= len(turn.Blocks) {
+		return turn
+	}
+	turn.Blocks = turn.Blocks[:visible]
+	turn.Duration = 0
+	return turn
+}
+
+// subagentBlocks returns the tool_use blocks in a turn that carry a
+// subagent transcript, in display order.
+func subagentBlocks(turn session.Turn) []session.Block {
+	var blocks []session.Block
+	for _, block := range turn.Blocks {
+		if len(block.Sidechain) > 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// renderSubagentSummary renders the one-line summary shown under a Task/Agent
+// tool call, e.g. "⤷ subagent: 12 tool calls · 1m 4s (enter to open)".
+func renderSubagentSummary(block session.Block, selected, multiple bool) string {
+	toolCalls := 0
+	var total time.Duration
+	for _, t := range block.Sidechain {
+		total += t.Duration
+		for _, b := range t.Blocks {
+			if b.Type == session.BlockToolUse {
+				toolCalls++
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("⤷ subagent: %d tool calls", toolCalls)
+	if toolCalls == 1 {
+		summary = "⤷ subagent: 1 tool call"
+	}
+	if total > 0 {
+		summary += " · " + FormatDuration(total)
+	}
+
+	style := lipgloss.NewStyle().Foreground(theme.ColorDim)
+	hint := ""
+	if selected {
+		style = lipgloss.NewStyle().Foreground(theme.ColorAccent)
+		hint = "(enter to open)"
+	} else if multiple {
+		hint = "(tab to select)"
+	}
+	line := style.Render(summary)
+	if hint != "" {
+		line += " " + lipgloss.NewStyle().Foreground(theme.ColorDim).Render(hint)
+	}
+	return "    " + line
 }
 
 func renderDuration(d time.Duration, turnNumber int) string {
 	verb := durationVerbs[turnNumber%len(durationVerbs)]
-	formatted := formatDuration(d)
+	formatted := FormatDuration(d)
 	star := lipgloss.NewStyle().Foreground(theme.ColorDim).Render("*")
 	text := lipgloss.NewStyle().
 		Foreground(theme.ColorDim).
@@ -100,7 +195,8 @@ func renderDuration(d time.Duration, turnNumber int) string {
 	return fmt.Sprintf("    %s %s", star, text)
 }
 
-func formatDuration(d time.Duration) string {
+// FormatDuration formats a turn duration the way Claude Code does ("1m 4s").
+func FormatDuration(d time.Duration) string {
 	if d < time.Second {
 		return fmt.Sprintf("%dms", d.Milliseconds())
 	}
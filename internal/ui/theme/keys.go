@@ -16,11 +16,33 @@ type KeyMap struct {
 	PageUp       key.Binding
 	PageDown     key.Binding
 	ExpandTool   key.Binding
+	NextBlock    key.Binding
+	PrevBlock    key.Binding
 	AutoPlay     key.Binding
+	Follow       key.Binding
+	Redact       key.Binding
+	Files        key.Binding
+	Branch       key.Binding
+	Step         key.Binding
+	Snapshot     key.Binding
+	Bookmark     key.Binding
+	Annotate     key.Binding
+	Annotations  key.Binding
 	SpeedUp      key.Binding
 	SpeedDown    key.Binding
 	Help         key.Binding
 	Filter       key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+	Search       key.Binding
+	Stats        key.Binding
+
+	// Files view
+	ToggleContent key.Binding
+
+	// Session diff
+	NextDivergence key.Binding
+	Summary        key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -73,10 +95,54 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("ctrl+o"),
 		key.WithHelp("ctrl+o", "expand/collapse"),
 	),
+	NextBlock: key.NewBinding(
+		key.WithKeys("tab", "j"),
+		key.WithHelp("tab/j", "next block"),
+	),
+	PrevBlock: key.NewBinding(
+		key.WithKeys("shift+tab", "k"),
+		key.WithHelp("shift+tab/k", "prev block"),
+	),
 	AutoPlay: key.NewBinding(
 		key.WithKeys(" "),
 		key.WithHelp("space", "autoplay"),
 	),
+	Follow: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "follow live session"),
+	),
+	Redact: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "redaction preview"),
+	),
+	Files: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "files touched"),
+	),
+	Branch: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "switch branch"),
+	),
+	Step: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "step through blocks"),
+	),
+	Snapshot: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "save view as SVG"),
+	),
+	Bookmark: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "bookmark"),
+	),
+	Annotate: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "write a note"),
+	),
+	Annotations: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "annotations"),
+	),
 	SpeedUp: key.NewBinding(
 		key.WithKeys("+", "="),
 		key.WithHelp("+", "speed up"),
@@ -93,4 +159,32 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("/"),
 		key.WithHelp("/", "filter"),
 	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "search all sessions"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "analytics"),
+	),
+	ToggleContent: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "content/diff"),
+	),
+	NextDivergence: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "next difference"),
+	),
+	Summary: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "summary"),
+	),
 }
@@ -87,6 +87,14 @@ var (
 			Foreground(ColorError).
 			PaddingLeft(4)
 
+	StyleRedacted = lipgloss.NewStyle().
+			Foreground(ColorBg).
+			Background(ColorWarning)
+
+	StyleSearchMatch = lipgloss.NewStyle().
+				Foreground(ColorBg).
+				Background(ColorAccent)
+
 	StyleTimeline = lipgloss.NewStyle().
 			Foreground(ColorDim)
 